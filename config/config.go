@@ -0,0 +1,396 @@
+// Package config reúne toda la configuración de la aplicación en un struct tipado.
+//
+// Cada campo se puede definir, de menor a mayor prioridad, por los valores por defecto del
+// perfil (dev o prod), un archivo de configuración con formato .env (-config o CONFIG_FILE),
+// variables de entorno (incluido el .env del directorio actual) y flags de línea de comandos.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Perfiles de configuración.
+const (
+	ProfileDev  = "dev"
+	ProfileProd = "prod"
+)
+
+// minSessionKeyLen es el largo mínimo de SESSION_KEY en producción.
+const minSessionKeyLen = 32
+
+// devSessionKey es la clave de sesión que se usa en desarrollo si no se define otra.
+const devSessionKey = "supersecretkey"
+
+// Config es la configuración efectiva de la aplicación. Los tags indican la variable de entorno,
+// el flag y si el valor es secreto (se oculta al imprimir la configuración).
+type Config struct {
+	Profile         string        `env:"APP_ENV" flag:"profile" usage:"perfil de configuración: dev o prod"`
+	Port            int           `env:"PORT" flag:"port" usage:"puerto HTTP"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"tiempo máximo de espera de las peticiones en curso al apagar"`
+	TrustProxy      bool          `env:"TRUST_PROXY" flag:"trust-proxy" usage:"confiar en X-Forwarded-For (solo detrás de un proxy)"`
+	AdminEmail      string        `env:"ADMIN_EMAIL" flag:"admin-email" usage:"email del usuario que se promueve a administrador al arrancar"`
+	PublicURL       string        `env:"PUBLIC_URL" flag:"public-url" usage:"URL pública del sitio para los enlaces absolutos (vacío = http://localhost:PORT)"`
+
+	Database   DatabaseConfig
+	Session    SessionConfig
+	Log        LogConfig
+	Tracing    TracingConfig
+	Metrics    MetricsConfig
+	RateLimit  RateLimitConfig
+	Lockout    LockoutConfig
+	Moderation ModerationConfig
+	Slack      SlackConfig
+	Mail       MailConfig
+}
+
+// DatabaseConfig indica cómo conectarse a Postgres. DATABASE_URL tiene prioridad sobre DB_*.
+type DatabaseConfig struct {
+	URL      string `env:"DATABASE_URL" flag:"database-url" secret:"url" usage:"URL de conexión a Postgres"`
+	Host     string `env:"DB_HOST" flag:"db-host" usage:"host de Postgres (si no hay DATABASE_URL)"`
+	Port     int    `env:"DB_PORT" flag:"db-port" usage:"puerto de Postgres"`
+	User     string `env:"DB_USER" flag:"db-user" usage:"usuario de Postgres"`
+	Password string `env:"DB_PASSWORD" flag:"db-password" secret:"true" usage:"contraseña de Postgres"`
+	Name     string `env:"DB_NAME" flag:"db-name" usage:"nombre de la base de datos"`
+}
+
+// ConnString devuelve la URL de conexión, armándola a partir de DB_* si no hay DATABASE_URL.
+func (c DatabaseConfig) ConnString() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.Name,
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
+
+// SessionConfig configura la cookie de sesión.
+type SessionConfig struct {
+	Key    string `env:"SESSION_KEY" flag:"session-key" secret:"true" usage:"clave para firmar la cookie de sesión"`
+	Secure bool   `env:"SECURE" flag:"secure" usage:"marcar la cookie de sesión como Secure (HTTPS)"`
+}
+
+// LogConfig configura los logs estructurados.
+type LogConfig struct {
+	Format string `env:"LOG_FORMAT" flag:"log-format" usage:"formato de los logs: text o json"`
+	Level  string `env:"LOG_LEVEL" flag:"log-level" usage:"nivel de los logs: debug, info, warn o error"`
+}
+
+// TracingConfig configura OpenTelemetry. El endpoint de OTLP se toma de las variables
+// estándar OTEL_EXPORTER_OTLP_*.
+type TracingConfig struct {
+	Exporter string `env:"OTEL_TRACES_EXPORTER" flag:"traces-exporter" usage:"exportador de trazas: otlp, stdout o none"`
+}
+
+// MetricsConfig configura el endpoint /metrics.
+type MetricsConfig struct {
+	Token string `env:"METRICS_TOKEN" flag:"metrics-token" secret:"true" usage:"token Bearer exigido en /metrics (vacío = abierto)"`
+}
+
+// RateLimitConfig define dónde se guardan los contadores y los límites de cada acción (formato N/unidad).
+type RateLimitConfig struct {
+	Store      string    `env:"RATE_LIMIT_STORE" flag:"rate-limit-store" usage:"almacén de rate limit: memory o postgres"`
+	Login      RateLimit `env:"RATE_LIMIT_LOGIN" flag:"rate-limit-login" usage:"límite de intentos de login por IP"`
+	Account    RateLimit `env:"RATE_LIMIT_ACCOUNT" flag:"rate-limit-account" usage:"límite de acciones de cuenta por usuario"`
+	Register   RateLimit `env:"RATE_LIMIT_REGISTER" flag:"rate-limit-register" usage:"límite de registros por IP"`
+	VoteIP     RateLimit `env:"RATE_LIMIT_VOTE_IP" flag:"rate-limit-vote-ip" usage:"límite de votos por IP"`
+	VoteUser   RateLimit `env:"RATE_LIMIT_VOTE_USER" flag:"rate-limit-vote-user" usage:"límite de votos por usuario"`
+	CreatePoll RateLimit `env:"RATE_LIMIT_CREATE_POLL" flag:"rate-limit-create-poll" usage:"límite de encuestas creadas por usuario"`
+	Report     RateLimit `env:"RATE_LIMIT_REPORT" flag:"rate-limit-report" usage:"límite de reportes por usuario"`
+	Comment    RateLimit `env:"RATE_LIMIT_COMMENT" flag:"rate-limit-comment" usage:"límite de comentarios por usuario"`
+}
+
+// RateLimit es un límite "N/unidad" ya interpretado: Rate tokens por segundo con ráfagas de hasta Burst.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+	spec  string
+}
+
+// ParseRateLimit interpreta una especificación "N/unidad", por ejemplo "10/m" (10 por
+// minuto, ráfagas de 10), "1/s" o "100/h".
+func ParseRateLimit(spec string) (RateLimit, error) {
+	spec = strings.TrimSpace(spec)
+	countStr, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("límite %q inválido: se espera el formato N/unidad (ej. 10/m)", spec)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return RateLimit{}, fmt.Errorf("límite %q inválido: la cantidad debe ser un entero positivo", spec)
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return RateLimit{}, fmt.Errorf("límite %q inválido: la unidad debe ser s, m o h", spec)
+	}
+	return RateLimit{Rate: float64(count) / period.Seconds(), Burst: count, spec: spec}, nil
+}
+
+// mustRateLimit es ParseRateLimit para los valores por defecto, que son constantes.
+func mustRateLimit(spec string) RateLimit {
+	limit, err := ParseRateLimit(spec)
+	if err != nil {
+		panic(err)
+	}
+	return limit
+}
+
+// String devuelve la especificación tal como se configuró.
+func (l RateLimit) String() string {
+	return l.spec
+}
+
+// LockoutConfig define el bloqueo progresivo tras logins fallidos: a partir de Threshold
+// fallos se bloquea BaseDelay, duplicando en cada fallo extra hasta MaxDelay. Se cuenta por
+// email e IP y, con un umbral más alto, por cuenta sin importar la IP.
+type LockoutConfig struct {
+	Threshold        int           `env:"LOGIN_LOCKOUT_THRESHOLD" flag:"login-lockout-threshold" usage:"logins fallidos por email e IP antes de bloquear"`
+	BaseDelay        time.Duration `env:"LOGIN_LOCKOUT_BASE_DELAY" flag:"login-lockout-base-delay" usage:"primer bloqueo por email e IP"`
+	MaxDelay         time.Duration `env:"LOGIN_LOCKOUT_MAX_DELAY" flag:"login-lockout-max-delay" usage:"bloqueo máximo por email e IP"`
+	AccountThreshold int           `env:"LOGIN_LOCKOUT_ACCOUNT_THRESHOLD" flag:"login-lockout-account-threshold" usage:"logins fallidos de una cuenta (desde cualquier IP) antes de bloquear"`
+	AccountBaseDelay time.Duration `env:"LOGIN_LOCKOUT_ACCOUNT_BASE_DELAY" flag:"login-lockout-account-base-delay" usage:"primer bloqueo de una cuenta"`
+	AccountMaxDelay  time.Duration `env:"LOGIN_LOCKOUT_ACCOUNT_MAX_DELAY" flag:"login-lockout-account-max-delay" usage:"bloqueo máximo de una cuenta"`
+	ResetAfter       time.Duration `env:"LOGIN_LOCKOUT_RESET_AFTER" flag:"login-lockout-reset-after" usage:"tiempo sin fallos tras el que se olvidan los anteriores"`
+}
+
+// ModerationConfig configura el filtro de palabras y el ocultamiento automático por reportes.
+type ModerationConfig struct {
+	ProfanityWords          []string `env:"PROFANITY_WORDS" flag:"profanity-words" usage:"palabras prohibidas separadas por comas"`
+	ProfanityWordsFile      string   `env:"PROFANITY_WORDS_FILE" flag:"profanity-words-file" usage:"archivo con una palabra prohibida por línea"`
+	ProfanityMode           string   `env:"PROFANITY_MODE" flag:"profanity-mode" usage:"qué hacer con el contenido filtrado: reject o flag (los comentarios solo se filtran con reject)"`
+	ReportAutoHideThreshold int      `env:"REPORT_AUTO_HIDE_THRESHOLD" flag:"report-auto-hide-threshold" usage:"reportes abiertos para ocultar una encuesta (0 = nunca)"`
+}
+
+// SlackConfig configura la integración con Slack (slash command y botones de voto).
+type SlackConfig struct {
+	SigningSecret string `env:"SLACK_SIGNING_SECRET" flag:"slack-signing-secret" secret:"true" usage:"Signing Secret de la app de Slack (vacío = integración desactivada)"`
+}
+
+// MailConfig elige cómo salen los correos (el resumen diario de notificaciones).
+type MailConfig struct {
+	Mailer       string `env:"MAILER" flag:"mailer" usage:"envío de correos: none, log o smtp (none = sin resumen por correo)"`
+	From         string `env:"MAIL_FROM" flag:"mail-from" usage:"remitente de los correos"`
+	SMTPAddr     string `env:"SMTP_ADDR" flag:"smtp-addr" usage:"servidor SMTP como host:puerto"`
+	SMTPUsername string `env:"SMTP_USERNAME" flag:"smtp-username" usage:"usuario SMTP (vacío = sin autenticación)"`
+	SMTPPassword string `env:"SMTP_PASSWORD" flag:"smtp-password" secret:"true" usage:"contraseña SMTP"`
+}
+
+// BaseURL devuelve la URL pública del sitio, sin barra final.
+func (c *Config) BaseURL() string {
+	if c.PublicURL == "" {
+		return fmt.Sprintf("http://localhost:%d", c.Port)
+	}
+	return strings.TrimSuffix(c.PublicURL, "/")
+}
+
+// Defaults devuelve la configuración por defecto del perfil.
+func Defaults(profile string) *Config {
+	cfg := &Config{
+		Profile:         profile,
+		Port:            8080,
+		ShutdownTimeout: 30 * time.Second,
+		Database:        DatabaseConfig{Port: 5432},
+		Log:             LogConfig{Format: "text", Level: "debug"},
+		Tracing:         TracingConfig{Exporter: "none"},
+		RateLimit: RateLimitConfig{
+			Store:      "memory",
+			Login:      mustRateLimit("10/m"),
+			Account:    mustRateLimit("5/m"),
+			Register:   mustRateLimit("5/m"),
+			VoteIP:     mustRateLimit("60/m"),
+			VoteUser:   mustRateLimit("20/m"),
+			CreatePoll: mustRateLimit("10/m"),
+			Report:     mustRateLimit("5/m"),
+			Comment:    mustRateLimit("10/m"),
+		},
+		Lockout: LockoutConfig{
+			Threshold:        5,
+			BaseDelay:        30 * time.Second,
+			MaxDelay:         15 * time.Minute,
+			AccountThreshold: 20,
+			AccountBaseDelay: time.Minute,
+			AccountMaxDelay:  time.Hour,
+			ResetAfter:       24 * time.Hour,
+		},
+		Moderation: ModerationConfig{
+			ProfanityMode:           "reject",
+			ReportAutoHideThreshold: 3,
+		},
+		Mail: MailConfig{Mailer: "none"},
+	}
+
+	switch profile {
+	case ProfileProd:
+		cfg.Session.Secure = true
+		cfg.Log = LogConfig{Format: "json", Level: "info"}
+	default:
+		cfg.Session.Key = devSessionKey
+	}
+	return cfg
+}
+
+// Validate revisa la configuración y devuelve todos los problemas juntos.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Profile == ProfileDev || c.Profile == ProfileProd, "APP_ENV %q inválido: debe ser %s o %s", c.Profile, ProfileDev, ProfileProd)
+	check(c.Port > 0 && c.Port < 65536, "PORT %d inválido: debe estar entre 1 y 65535", c.Port)
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT debe ser mayor que cero")
+	if c.PublicURL != "" {
+		u, err := url.Parse(c.PublicURL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "PUBLIC_URL %q inválida: debe ser una URL http(s) absoluta", c.PublicURL)
+	}
+
+	if c.Database.URL != "" {
+		_, err := url.Parse(c.Database.URL)
+		check(err == nil, "DATABASE_URL inválida")
+	} else {
+		check(c.Database.Host != "" && c.Database.User != "" && c.Database.Name != "",
+			"falta la base de datos: definir DATABASE_URL o DB_HOST, DB_USER y DB_NAME")
+	}
+
+	check(c.Session.Key != "", "SESSION_KEY es obligatoria")
+	if c.Profile == ProfileProd {
+		check(c.Session.Key == "" || (c.Session.Key != devSessionKey && len(c.Session.Key) >= minSessionKeyLen),
+			"SESSION_KEY debe tener al menos %d caracteres en producción", minSessionKeyLen)
+		check(c.Session.Secure, "SECURE debe ser true en producción")
+	}
+
+	check(oneOf(c.Log.Format, "text", "json"), "LOG_FORMAT %q inválido: debe ser text o json", c.Log.Format)
+	check(oneOf(strings.ToLower(c.Log.Level), "debug", "info", "warn", "error"), "LOG_LEVEL %q inválido: debe ser debug, info, warn o error", c.Log.Level)
+	check(oneOf(c.Tracing.Exporter, "none", "otlp", "stdout"), "OTEL_TRACES_EXPORTER %q inválido: debe ser otlp, stdout o none", c.Tracing.Exporter)
+
+	check(oneOf(c.RateLimit.Store, "memory", "postgres"), "RATE_LIMIT_STORE %q inválido: debe ser memory o postgres", c.RateLimit.Store)
+	check(c.Lockout.Threshold > 0 && c.Lockout.AccountThreshold > 0, "LOGIN_LOCKOUT_THRESHOLD y LOGIN_LOCKOUT_ACCOUNT_THRESHOLD deben ser mayores que cero")
+	check(c.Lockout.BaseDelay > 0 && c.Lockout.MaxDelay >= c.Lockout.BaseDelay,
+		"LOGIN_LOCKOUT_BASE_DELAY debe ser mayor que cero y no superar LOGIN_LOCKOUT_MAX_DELAY")
+	check(c.Lockout.AccountBaseDelay > 0 && c.Lockout.AccountMaxDelay >= c.Lockout.AccountBaseDelay,
+		"LOGIN_LOCKOUT_ACCOUNT_BASE_DELAY debe ser mayor que cero y no superar LOGIN_LOCKOUT_ACCOUNT_MAX_DELAY")
+	check(c.Lockout.ResetAfter >= 0, "LOGIN_LOCKOUT_RESET_AFTER no puede ser negativo")
+
+	check(oneOf(c.Moderation.ProfanityMode, "reject", "flag"), "PROFANITY_MODE %q inválido: debe ser reject o flag", c.Moderation.ProfanityMode)
+	check(c.Moderation.ReportAutoHideThreshold >= 0, "REPORT_AUTO_HIDE_THRESHOLD debe ser un entero no negativo")
+	if path := c.Moderation.ProfanityWordsFile; path != "" {
+		_, err := os.Stat(path)
+		check(err == nil, "PROFANITY_WORDS_FILE: no se puede leer %s", path)
+	}
+
+	check(oneOf(c.Mail.Mailer, "none", "log", "smtp"), "MAILER %q inválido: debe ser none, log o smtp", c.Mail.Mailer)
+	if c.Mail.Mailer == "smtp" {
+		check(c.Mail.SMTPAddr != "" && c.Mail.From != "", "MAILER=smtp necesita SMTP_ADDR y MAIL_FROM")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Print escribe la configuración efectiva, una variable por línea, con los secretos ocultos.
+func (c *Config) Print(w io.Writer) {
+	for _, f := range fields(c) {
+		fmt.Fprintf(w, "%s=%s\n", f.env, f.display())
+	}
+}
+
+// LogValue permite registrar la configuración efectiva con slog, con los secretos ocultos.
+func (c *Config) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for _, f := range fields(c) {
+		attrs = append(attrs, slog.String(f.env, f.display()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func oneOf(value string, options ...string) bool {
+	for _, option := range options {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+// field es un campo configurable de Config, descubierto a partir de sus tags.
+type field struct {
+	env    string
+	flag   string
+	usage  string
+	secret string
+	value  reflect.Value
+}
+
+// fields recorre Config (y sus sub-structs) en orden de declaración.
+func fields(c *Config) []field {
+	var out []field
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(RateLimit{}) {
+				walk(v.Field(i))
+				continue
+			}
+			out = append(out, field{
+				env:    sf.Tag.Get("env"),
+				flag:   sf.Tag.Get("flag"),
+				usage:  sf.Tag.Get("usage"),
+				secret: sf.Tag.Get("secret"),
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem())
+	return out
+}
+
+// display devuelve el valor para mostrar, ocultando secretos.
+func (f field) display() string {
+	value := f.String()
+	switch {
+	case value == "":
+		return ""
+	case f.secret == "url":
+		if u, err := url.Parse(value); err == nil {
+			return u.Redacted()
+		}
+		return "[REDACTED]"
+	case f.secret != "":
+		return "[REDACTED]"
+	}
+	return value
+}
+
+func (f field) String() string {
+	switch v := f.value.Interface().(type) {
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ErrPrintConfig lo devuelve Load cuando se pidió -print-config: la configuración ya se imprimió.
+var ErrPrintConfig = errors.New("configuración impresa")
+
+// Load arma la configuración a partir de los flags (args, sin el nombre del programa), las
+// variables de entorno, el .env y el archivo de configuración, y la valida.
+func Load(args []string, output io.Writer) (*Config, error) {
+	fs := flag.NewFlagSet("webpolls", flag.ContinueOnError)
+	fs.SetOutput(output)
+	configFile := fs.String("config", "", "archivo de configuración con formato .env (también CONFIG_FILE)")
+	printConfig := fs.Bool("print-config", false, "imprimir la configuración efectiva (con secretos ocultos) y salir")
+
+	// Los flags se registran sobre una configuración descartable solo para conocer sus nombres;
+	// los valores se guardan crudos y se aplican después, respetando las prioridades.
+	flagValues := map[string]string{}
+	for _, f := range fields(Defaults(ProfileDev)) {
+		name := f.flag
+		_, isBool := f.value.Interface().(bool)
+		fs.Var(rawFlag{isBool: isBool, set: func(value string) { flagValues[name] = value }}, name, f.usage+" ("+f.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// El .env no pisa variables ya definidas en el entorno
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf(".env: %w", err)
+	}
+
+	if *configFile == "" {
+		*configFile = os.Getenv("CONFIG_FILE")
+	}
+	fileValues := map[string]string{}
+	if *configFile != "" {
+		var err error
+		fileValues, err = godotenv.Read(*configFile)
+		if err != nil {
+			return nil, fmt.Errorf("archivo de configuración: %w", err)
+		}
+	}
+
+	// Un valor vacío cuenta como no definido (como SESSION_KEY= en un .env)
+	lookup := func(f field) (string, bool) {
+		for _, value := range []string{flagValues[f.flag], os.Getenv(f.env), fileValues[f.env]} {
+			if value != "" {
+				return value, true
+			}
+		}
+		return "", false
+	}
+
+	// El perfil decide los valores por defecto, así que se resuelve primero
+	profile := ProfileDev
+	if value, ok := lookup(field{env: "APP_ENV", flag: "profile"}); ok {
+		profile = value
+	}
+	cfg := Defaults(profile)
+
+	var errs []error
+	for _, f := range fields(cfg) {
+		value, ok := lookup(f)
+		if !ok {
+			continue
+		}
+		if err := f.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if *printConfig {
+		cfg.Print(output)
+		return cfg, ErrPrintConfig
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// set convierte el valor crudo al tipo del campo.
+func (f field) set(value string) error {
+	value = strings.TrimSpace(value)
+	switch f.value.Interface().(type) {
+	case string:
+		f.value.SetString(value)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q no es un booleano (true/false)", value)
+		}
+		f.value.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q no es un entero", value)
+		}
+		f.value.SetInt(int64(n))
+	case RateLimit:
+		limit, err := ParseRateLimit(value)
+		if err != nil {
+			return err
+		}
+		f.value.Set(reflect.ValueOf(limit))
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q no es una duración válida (ej. 30s)", value)
+		}
+		f.value.SetInt(int64(d))
+	case []string:
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("tipo %s no soportado", f.value.Type())
+	}
+	return nil
+}
+
+// rawFlag guarda el valor crudo de un flag para aplicarlo después. Los booleanos se marcan
+// con IsBoolFlag para que -secure sin valor funcione como en los flags de la biblioteca estándar.
+type rawFlag struct {
+	isBool bool
+	set    func(string)
+}
+
+func (f rawFlag) String() string { return "" }
+
+func (f rawFlag) Set(value string) error {
+	f.set(value)
+	return nil
+}
+
+func (f rawFlag) IsBoolFlag() bool { return f.isBool }
@@ -5,35 +5,19 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/joho/godotenv"
 )
 
-func InitDB() *pgxpool.Pool {
-	// cargar variables .env
-	if err := godotenv.Load(); err != nil {
-		log.Println("No se pudo cargar el archivo .env, usando variables del sistema.")
-	}
-
-	// conexion a BD
-	connStr := os.Getenv("DATABASE_URL")
-	if connStr == "" {
-		// Fallback to constructing it if DATABASE_URL is missing
-		dbUser := os.Getenv("DB_USER")
-		dbPassword := os.Getenv("DB_PASSWORD")
-		dbName := os.Getenv("DB_NAME")
-		dbHost := os.Getenv("DB_HOST")
-		connStr = fmt.Sprintf(
-			"postgres://%s:%s@%s:5432/%s?sslmode=disable",
-			dbUser, dbPassword, dbHost, dbName,
-		)
-	}
-
+// InitDB abre el pool de conexiones (ver config.DatabaseConfig.ConnString) y aplica schema.sql.
+func InitDB(connStr string) *pgxpool.Pool {
 	config, err := pgxpool.ParseConfig(connStr)
 	if err != nil {
 		log.Fatalf("Unable to parse connection string: %v", err)
 	}
+	config.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -54,7 +38,69 @@ func InitDB() *pgxpool.Pool {
 	if _, err := pool.Exec(context.Background(), string(schemaBytes)); err != nil {
 		log.Fatalf("Error aplicando schema: %v", err)
 	}
+	schema = parseSchema(string(schemaBytes))
 
 	fmt.Println("Conexión a la base de datos exitosa (pgxpool)")
 	return pool
 }
+
+var (
+	createRelationRe = regexp.MustCompile(`(?i)CREATE (?:UNIQUE )?(?:TABLE|INDEX) IF NOT EXISTS (\w+)`)
+	addColumnRe      = regexp.MustCompile(`(?i)ALTER TABLE (\w+) ADD COLUMN IF NOT EXISTS (\w+)`)
+	constraintRe     = regexp.MustCompile(`(?i)CONSTRAINT (\w+) (?:UNIQUE|CHECK|FOREIGN KEY|PRIMARY KEY|EXCLUDE)`)
+)
+
+// schemaObjects son los objetos con nombre que crea schema.sql, para verificar que sigue
+// aplicado: no alcanza con las tablas, porque las columnas y restricciones nuevas se
+// agregan con ALTER TABLE sobre tablas que ya existían.
+type schemaObjects struct {
+	// Relations son las tablas y los índices
+	Relations []string
+	// Columns son las columnas agregadas con ALTER TABLE, como "tabla.columna"
+	Columns     []string
+	Constraints []string
+}
+
+var schema schemaObjects
+
+// parseSchema busca en schema.sql las tablas, índices, columnas agregadas y restricciones.
+func parseSchema(sql string) schemaObjects {
+	var objects schemaObjects
+	for _, match := range createRelationRe.FindAllStringSubmatch(sql, -1) {
+		objects.Relations = append(objects.Relations, match[1])
+	}
+	for _, match := range addColumnRe.FindAllStringSubmatch(sql, -1) {
+		objects.Columns = append(objects.Columns, match[1]+"."+match[2])
+	}
+	for _, match := range constraintRe.FindAllStringSubmatch(sql, -1) {
+		objects.Constraints = append(objects.Constraints, match[1])
+	}
+	return objects
+}
+
+// CheckSchema verifica que existan todas las tablas, índices, columnas y restricciones de
+// schema.sql (p. ej. que nadie haya borrado una tabla o que la base no sea de una versión vieja).
+func CheckSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	var missing []string
+	err := pool.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(name), '{}') FROM (
+			SELECT r AS name FROM unnest($1::text[]) AS r WHERE to_regclass(r) IS NULL
+			UNION ALL
+			SELECT c FROM unnest($2::text[]) AS c WHERE NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name || '.' || column_name = c)
+			UNION ALL
+			SELECT k FROM unnest($3::text[]) AS k WHERE NOT EXISTS (
+				SELECT 1 FROM pg_constraint
+				WHERE conname = k AND connamespace = to_regnamespace(current_schema()))
+		) AS missing`,
+		schema.Relations, schema.Columns, schema.Constraints,
+	).Scan(&missing)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("faltan objetos del schema: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	sql, err := os.ReadFile("schema/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := parseSchema(string(sql))
+
+	for _, want := range []string{"users", "polls", "organization_members", "idx_polls_user_id", "idx_reports_open_reporter"} {
+		if !slices.Contains(objects.Relations, want) {
+			t.Errorf("falta la relación %s", want)
+		}
+	}
+	for _, want := range []string{"users.totp_enabled", "polls.status", "polls.org_id", "polls.members_only"} {
+		if !slices.Contains(objects.Columns, want) {
+			t.Errorf("falta la columna %s", want)
+		}
+	}
+	for _, want := range []string{"unique_recovery_code", "users_role_check", "unique_poll_title", "organization_members_role_check"} {
+		if !slices.Contains(objects.Constraints, want) {
+			t.Errorf("falta la restricción %s", want)
+		}
+	}
+	// "DROP CONSTRAINT IF EXISTS polls_title_key" no es una restricción que deba existir
+	for _, name := range objects.Constraints {
+		if name == "IF" || name == "polls_title_key" {
+			t.Errorf("restricción inesperada %q", name)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (actor_id, action, target_type, target_id, details)
+VALUES (@actor_id, @action, @target_type, @target_id, @details);
+
+-- name: ListAuditLog :many
+SELECT
+    a.id,
+    a.actor_id,
+    u.username AS actor_username,
+    a.action,
+    a.target_type,
+    a.target_id,
+    a.details,
+    a.created_at
+FROM audit_log a
+LEFT JOIN users u ON u.id = a.actor_id
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT @max_entries;
@@ -0,0 +1,49 @@
+-- name: CreateComment :one
+INSERT INTO comments (poll_id, user_id, parent_id, body)
+VALUES (@poll_id, @user_id, @parent_id, @body)
+RETURNING id;
+
+-- name: ListCommentsByPoll :many
+SELECT
+    c.id,
+    c.poll_id,
+    c.user_id,
+    u.username,
+    c.parent_id,
+    c.body,
+    c.created_at,
+    c.edited_at,
+    c.deleted_at
+FROM comments c
+LEFT JOIN users u ON u.id = c.user_id
+WHERE c.poll_id = @poll_id
+ORDER BY c.created_at ASC, c.id ASC;
+
+-- name: GetCommentByID :one
+SELECT
+    c.id,
+    c.poll_id,
+    c.user_id,
+    c.parent_id,
+    c.body,
+    c.deleted_at,
+    p.user_id AS poll_owner_id,
+    p.org_id AS poll_org_id,
+    p.hidden AS poll_hidden,
+    p.status AS poll_status,
+    p.comments_mode
+FROM comments c
+JOIN polls p ON p.id = c.poll_id
+WHERE c.id = @id;
+
+-- name: UpdateCommentBody :exec
+UPDATE comments
+SET body = @body,
+    edited_at = NOW()
+WHERE id = @id AND deleted_at IS NULL;
+
+-- name: SoftDeleteComment :exec
+UPDATE comments
+SET body = '',
+    deleted_at = NOW()
+WHERE id = @id AND deleted_at IS NULL;
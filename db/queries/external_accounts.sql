@@ -0,0 +1,11 @@
+-- name: GetExternalAccountUser :one
+SELECT ea.user_id, u.locale
+FROM external_accounts ea
+JOIN users u ON u.id = ea.user_id
+WHERE ea.provider = @provider AND ea.team_id = @team_id AND ea.external_user_id = @external_user_id;
+
+-- name: LinkExternalAccount :exec
+INSERT INTO external_accounts (provider, team_id, external_user_id, user_id)
+VALUES (@provider, @team_id, @external_user_id, @user_id)
+ON CONFLICT (provider, team_id, external_user_id)
+DO UPDATE SET user_id = EXCLUDED.user_id, created_at = NOW();
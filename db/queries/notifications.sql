@@ -0,0 +1,106 @@
+-- name: AddNotification :exec
+INSERT INTO notifications (user_id, kind, poll_id, actor_id)
+VALUES (@user_id, @kind, @poll_id, @actor_id)
+ON CONFLICT (user_id, kind, poll_id) WHERE read_at IS NULL
+DO UPDATE SET count = notifications.count + 1,
+    actor_id = EXCLUDED.actor_id,
+    updated_at = NOW(),
+    emailed_at = NULL;
+
+-- name: AddClosedNotifications :many
+INSERT INTO notifications (user_id, kind, poll_id)
+SELECT p.user_id, 'closed', @poll_id
+FROM (
+    SELECT r.user_id FROM results r WHERE r.poll_id = @poll_id
+    UNION
+    SELECT polls.user_id FROM polls WHERE polls.id = @poll_id
+) p
+LEFT JOIN notification_preferences np ON np.user_id = p.user_id
+WHERE COALESCE(np.on_close, TRUE)
+ON CONFLICT (user_id, kind, poll_id) WHERE read_at IS NULL DO NOTHING
+RETURNING user_id;
+
+-- name: ListNotifications :many
+SELECT
+    n.id,
+    n.kind,
+    n.poll_id,
+    p.title AS poll_title,
+    u.username AS actor_username,
+    n.count,
+    n.updated_at,
+    n.read_at
+FROM notifications n
+JOIN polls p ON p.id = n.poll_id
+LEFT JOIN users u ON u.id = n.actor_id
+WHERE n.user_id = @user_id
+ORDER BY n.updated_at DESC
+LIMIT @max_notifications;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = @user_id AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = @id AND user_id = @user_id
+RETURNING poll_id;
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = @user_id AND read_at IS NULL;
+
+-- name: GetNotificationPreferences :one
+SELECT user_id, on_vote, on_close, on_comment, email_digest, last_digest_at
+FROM notification_preferences
+WHERE user_id = @user_id;
+
+-- name: UpsertNotificationPreferences :exec
+INSERT INTO notification_preferences (user_id, on_vote, on_close, on_comment, email_digest)
+VALUES (@user_id, @on_vote, @on_close, @on_comment, @email_digest)
+ON CONFLICT (user_id) DO UPDATE SET on_vote = EXCLUDED.on_vote,
+    on_close = EXCLUDED.on_close,
+    on_comment = EXCLUDED.on_comment,
+    email_digest = EXCLUDED.email_digest;
+
+-- name: ClaimDueDigests :many
+UPDATE notification_preferences np
+SET last_digest_at = NOW()
+FROM users u
+WHERE u.id = np.user_id
+  AND np.user_id IN (
+    SELECT pref.user_id FROM notification_preferences pref
+    WHERE pref.email_digest
+      AND (pref.last_digest_at IS NULL OR pref.last_digest_at <= NOW() - INTERVAL '1 day')
+      AND EXISTS (
+        SELECT 1 FROM notifications n
+        WHERE n.user_id = pref.user_id AND n.read_at IS NULL AND n.emailed_at IS NULL
+      )
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+  )
+RETURNING np.user_id, u.email, u.username, u.locale;
+
+-- name: ListDigestNotifications :many
+SELECT
+    n.id,
+    n.kind,
+    n.poll_id,
+    p.title AS poll_title,
+    u.username AS actor_username,
+    n.count,
+    n.updated_at,
+    n.read_at
+FROM notifications n
+JOIN polls p ON p.id = n.poll_id
+LEFT JOIN users u ON u.id = n.actor_id
+WHERE n.user_id = @user_id AND n.read_at IS NULL AND n.emailed_at IS NULL
+ORDER BY n.updated_at DESC;
+
+-- name: MarkNotificationsEmailed :exec
+UPDATE notifications
+SET emailed_at = NOW()
+WHERE id = ANY(@ids::int[]);
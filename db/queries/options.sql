@@ -28,4 +28,8 @@ WHERE id = @id;
 SELECT id, content, poll_id
 FROM options
 WHERE poll_id = @poll_id
-ORDER BY id ASC;
\ No newline at end of file
+ORDER BY id ASC;
+
+-- name: DeleteOptionsByPollID :exec
+DELETE FROM options
+WHERE poll_id = @poll_id;
@@ -0,0 +1,53 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (name)
+VALUES (@name)
+RETURNING id, name, created_at;
+
+-- name: RenameOrganization :execrows
+UPDATE organizations
+SET name = @name
+WHERE id = @id;
+
+-- name: DeleteOrganization :execrows
+DELETE FROM organizations
+WHERE id = @id;
+
+-- name: ListUserOrganizations :many
+SELECT org.id, org.name, org.created_at, m.role
+FROM organizations org
+JOIN organization_members m ON m.org_id = org.id
+WHERE m.user_id = @user_id
+ORDER BY org.name ASC, org.id ASC;
+
+-- name: GetUserOrganization :one
+SELECT org.id, org.name, org.created_at, m.role
+FROM organizations org
+JOIN organization_members m ON m.org_id = org.id
+WHERE org.id = @org_id AND m.user_id = @user_id;
+
+-- name: GetOrganizationRole :one
+SELECT role
+FROM organization_members
+WHERE org_id = @org_id AND user_id = @user_id;
+
+-- name: ListOrganizationMembers :many
+SELECT m.user_id, u.username, m.role, m.created_at
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = @org_id
+ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, u.username ASC;
+
+-- name: UpsertOrganizationMember :exec
+INSERT INTO organization_members (org_id, user_id, role)
+VALUES (@org_id, @user_id, @role)
+ON CONFLICT (org_id, user_id) DO UPDATE SET role = EXCLUDED.role;
+
+-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE org_id = @org_id AND user_id = @user_id;
+
+-- name: LockOrganizationOwners :many
+SELECT user_id
+FROM organization_members
+WHERE org_id = @org_id AND role = 'owner'
+FOR UPDATE;
@@ -0,0 +1,59 @@
+-- name: CreatePollSeries :one
+INSERT INTO poll_series (user_id, template_id, frequency, weekdays, cron_expr, starts_at, next_run_at)
+VALUES (@user_id, @template_id, @frequency, @weekdays, @cron_expr, @starts_at, @next_run_at)
+RETURNING id;
+
+-- name: ListPollSeries :many
+SELECT s.id, s.user_id, s.template_id, t.name AS template_name, s.frequency, s.weekdays, s.cron_expr,
+    s.starts_at, s.next_run_at, s.active, s.created_at
+FROM poll_series s
+JOIN poll_templates t ON t.id = s.template_id
+WHERE s.user_id = @user_id
+ORDER BY t.name ASC, s.id ASC;
+
+-- name: GetPollSeries :one
+SELECT s.id, s.user_id, s.template_id, t.name AS template_name, s.frequency, s.weekdays, s.cron_expr,
+    s.starts_at, s.next_run_at, s.active, s.created_at
+FROM poll_series s
+JOIN poll_templates t ON t.id = s.template_id
+WHERE s.id = @id AND s.user_id = @user_id;
+
+-- name: SetPollSeriesActive :execrows
+UPDATE poll_series
+SET active = @active, next_run_at = @next_run_at
+WHERE id = @id AND user_id = @user_id;
+
+-- name: DeletePollSeries :execrows
+DELETE FROM poll_series
+WHERE id = @id AND user_id = @user_id;
+
+-- name: ListDueSeries :many
+SELECT s.id, s.user_id, s.template_id, s.frequency, s.weekdays, s.cron_expr, s.starts_at, s.next_run_at
+FROM poll_series s
+WHERE s.active AND s.next_run_at <= NOW()
+ORDER BY s.next_run_at ASC;
+
+-- name: AdvancePollSeries :execrows
+UPDATE poll_series
+SET next_run_at = @next_run_at
+WHERE id = @id AND next_run_at = @previous_run_at;
+
+-- name: FinishPollSeries :execrows
+UPDATE poll_series
+SET active = FALSE
+WHERE id = @id AND active AND next_run_at = @previous_run_at;
+
+-- name: CloseSeriesPolls :execrows
+UPDATE polls
+SET closes_at = NOW()
+WHERE series_id = @series_id AND status = 'published' AND (closes_at IS NULL OR closes_at > NOW());
+
+-- name: GetSeriesResults :many
+SELECT p.id AS poll_id, p.title, p.publish_at, p.closes_at, p.outcome, p.winner_option_id,
+    o.id AS option_id, o.content AS option_content, COUNT(r.id) AS vote_count
+FROM polls p
+JOIN options o ON o.poll_id = p.id
+LEFT JOIN results r ON r.option_id = o.id
+WHERE p.series_id = @series_id
+GROUP BY p.id, o.id
+ORDER BY p.id ASC, o.id ASC;
@@ -0,0 +1,39 @@
+-- name: UpsertPollTemplate :one
+INSERT INTO poll_templates (
+    user_id, name, title, options, results_visibility, closes_in_minutes,
+    quorum_votes, invited_voters, quorum_percent, majority_percent, tie_break, tags
+)
+VALUES (
+    @user_id, @name, @title, @options, @results_visibility, @closes_in_minutes,
+    @quorum_votes, @invited_voters, @quorum_percent, @majority_percent, @tie_break, @tags
+)
+ON CONFLICT (user_id, name) DO UPDATE SET
+    title = EXCLUDED.title,
+    options = EXCLUDED.options,
+    results_visibility = EXCLUDED.results_visibility,
+    closes_in_minutes = EXCLUDED.closes_in_minutes,
+    quorum_votes = EXCLUDED.quorum_votes,
+    invited_voters = EXCLUDED.invited_voters,
+    quorum_percent = EXCLUDED.quorum_percent,
+    majority_percent = EXCLUDED.majority_percent,
+    tie_break = EXCLUDED.tie_break,
+    tags = EXCLUDED.tags
+RETURNING id, user_id, name, title, options, results_visibility, closes_in_minutes,
+    quorum_votes, invited_voters, quorum_percent, majority_percent, tie_break, tags, created_at;
+
+-- name: ListPollTemplates :many
+SELECT id, user_id, name, title, options, results_visibility, closes_in_minutes,
+    quorum_votes, invited_voters, quorum_percent, majority_percent, tie_break, tags, created_at
+FROM poll_templates
+WHERE user_id = @user_id
+ORDER BY name ASC;
+
+-- name: GetPollTemplate :one
+SELECT id, user_id, name, title, options, results_visibility, closes_in_minutes,
+    quorum_votes, invited_voters, quorum_percent, majority_percent, tie_break, tags, created_at
+FROM poll_templates
+WHERE id = @id AND user_id = @user_id;
+
+-- name: DeletePollTemplate :execrows
+DELETE FROM poll_templates
+WHERE id = @id AND user_id = @user_id;
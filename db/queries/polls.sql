@@ -1,17 +1,44 @@
 -- name: CreatePoll :one
-INSERT INTO polls (title, user_id)
-VALUES (@title, @user_id)
-RETURNING id,title, user_id;
+INSERT INTO polls (
+    title, user_id, closes_at, results_visibility,
+    quorum_votes, invited_voters, quorum_percent, majority_percent, tie_break,
+    status, publish_at, series_id, org_id, members_only
+)
+VALUES (
+    @title, @user_id, @closes_at, @results_visibility,
+    @quorum_votes, @invited_voters, @quorum_percent, @majority_percent, @tie_break,
+    @status, @publish_at, @series_id, @org_id, @members_only
+)
+RETURNING id, title, user_id, closes_at, results_visibility, status, publish_at;
 
 -- name: GetPollByID :many
 SELECT 
     polls.id,
     polls.title,
     polls.user_id,
+    polls.hidden,
+    polls.closes_at,
+    polls.results_visibility,
+    polls.quorum_votes,
+    polls.invited_voters,
+    polls.quorum_percent,
+    polls.majority_percent,
+    polls.tie_break,
+    polls.outcome,
+    polls.winner_option_id,
+    polls.decided_at,
+    polls.embed_origins,
+    polls.status,
+    polls.publish_at,
+    polls.series_id,
+    polls.org_id,
+    polls.members_only,
+    organizations.name AS org_name,
     options.id AS option_id,
     options.content AS option_content
 FROM polls
 inner JOIN options ON polls.id = options.poll_id
+LEFT JOIN organizations ON organizations.id = polls.org_id
 WHERE polls.id = @id
 ORDER BY polls.id ASC;
 
@@ -26,6 +53,16 @@ SELECT
 FROM polls p
 JOIN options o ON p.id = o.poll_id
 LEFT JOIN results r ON p.id = r.poll_id AND r.user_id = @user_id
+WHERE NOT p.hidden
+    AND p.status = 'published'
+    AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+        SELECT 1 FROM poll_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.poll_id = p.id AND t.name = sqlc.narg(tag)::text
+    ))
+    AND (sqlc.narg(search)::text IS NULL OR p.title ILIKE '%' || sqlc.narg(search)::text || '%')
+    AND (sqlc.narg(open)::boolean IS NULL
+        OR sqlc.narg(open)::boolean = (p.closes_at IS NULL OR p.closes_at > NOW()))
 ORDER BY p.id ASC;
 
 -- name: UpdatePoll :exec
@@ -42,11 +79,112 @@ SELECT
     p.id AS poll_id,
     p.title,
     p.user_id,
+    p.hidden,
+    p.status,
+    p.publish_at,
+    p.org_id,
+    org.name AS org_name,
     o.id AS option_id,
     o.content AS option_content,
     r.option_id AS user_voted_option_id
 FROM polls p
 JOIN options o ON p.id = o.poll_id
+LEFT JOIN organizations org ON org.id = p.org_id
 LEFT JOIN results r ON p.id = r.poll_id AND r.user_id = @viewer_id
-WHERE p.user_id = @owner_id
-ORDER BY p.id ASC;
\ No newline at end of file
+WHERE p.user_id = @owner_id OR p.org_id = ANY(@org_ids::int[])
+ORDER BY p.id ASC;
+
+-- name: GetPollsByOrgID :many
+SELECT
+    p.id AS poll_id,
+    p.title,
+    p.user_id,
+    p.hidden,
+    p.status,
+    p.publish_at,
+    p.org_id,
+    org.name AS org_name,
+    o.id AS option_id,
+    o.content AS option_content,
+    r.option_id AS user_voted_option_id
+FROM polls p
+JOIN options o ON p.id = o.poll_id
+LEFT JOIN organizations org ON org.id = p.org_id
+LEFT JOIN results r ON p.id = r.poll_id AND r.user_id = @viewer_id
+WHERE p.org_id = @org_id AND (p.status = 'published' OR @can_manage::boolean)
+ORDER BY p.id ASC;
+
+-- name: SetPollHidden :execrows
+UPDATE polls
+SET hidden = @hidden
+WHERE id = @id;
+
+-- name: GetPollsForModeration :many
+SELECT
+    p.id,
+    p.title,
+    p.user_id,
+    u.username,
+    p.hidden,
+    COUNT(r.id) FILTER (WHERE r.status = 'open') AS open_reports
+FROM polls p
+JOIN users u ON u.id = p.user_id
+LEFT JOIN reports r ON r.poll_id = p.id
+GROUP BY p.id, u.username
+ORDER BY open_reports DESC, p.id DESC;
+
+-- name: ListPollsPendingOutcome :many
+SELECT id
+FROM polls
+WHERE outcome IS NULL AND closes_at <= NOW() AND status = 'published'
+ORDER BY closes_at ASC;
+
+-- name: SetPollOutcome :execrows
+UPDATE polls
+SET outcome = @outcome, winner_option_id = @winner_option_id, decided_at = NOW()
+WHERE id = @id AND outcome IS NULL;
+
+-- name: ClosePollNow :execrows
+UPDATE polls
+SET closes_at = NOW()
+WHERE id = @id AND status = 'published' AND (closes_at IS NULL OR closes_at > NOW());
+
+-- name: UpdatePollEmbedOrigins :execrows
+UPDATE polls
+SET embed_origins = @embed_origins
+WHERE id = @id;
+
+-- name: GetPollCommentSettings :one
+SELECT id, user_id, org_id, hidden, status, comments_mode
+FROM polls
+WHERE id = @id;
+
+-- name: SetPollCommentsMode :execrows
+UPDATE polls
+SET comments_mode = @comments_mode
+WHERE id = @id;
+
+-- name: UpdateDraftPoll :execrows
+UPDATE polls
+SET title = @title,
+    closes_at = @closes_at,
+    results_visibility = @results_visibility,
+    quorum_votes = @quorum_votes,
+    invited_voters = @invited_voters,
+    quorum_percent = @quorum_percent,
+    majority_percent = @majority_percent,
+    tie_break = @tie_break,
+    org_id = @org_id,
+    members_only = @members_only
+WHERE id = @id AND status <> 'published';
+
+-- name: SetPollPublication :execrows
+UPDATE polls
+SET status = @status, publish_at = @publish_at
+WHERE id = @id AND status <> 'published';
+
+-- name: PublishDuePolls :many
+UPDATE polls
+SET status = 'published'
+WHERE status = 'scheduled' AND publish_at <= NOW()
+RETURNING id, user_id, title;
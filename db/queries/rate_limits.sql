@@ -0,0 +1,48 @@
+-- name: InsertRateLimitBucket :exec
+INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+VALUES (@key, @tokens, @updated_at)
+ON CONFLICT (key) DO NOTHING;
+
+-- name: GetRateLimitBucketForUpdate :one
+SELECT key, tokens, updated_at
+FROM rate_limit_buckets
+WHERE key = @key
+FOR UPDATE;
+
+-- name: UpsertRateLimitBucket :exec
+INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+VALUES (@key, @tokens, @updated_at)
+ON CONFLICT (key) DO UPDATE
+SET tokens = EXCLUDED.tokens,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE updated_at < @before;
+
+-- name: GetLoginFailure :one
+SELECT key, failures, locked_until, last_failure_at
+FROM login_failures
+WHERE key = @key;
+
+-- name: GetLoginFailureForUpdate :one
+SELECT key, failures, locked_until, last_failure_at
+FROM login_failures
+WHERE key = @key
+FOR UPDATE;
+
+-- name: UpsertLoginFailure :exec
+INSERT INTO login_failures (key, failures, locked_until, last_failure_at)
+VALUES (@key, @failures, @locked_until, @last_failure_at)
+ON CONFLICT (key) DO UPDATE
+SET failures = EXCLUDED.failures,
+    locked_until = EXCLUDED.locked_until,
+    last_failure_at = EXCLUDED.last_failure_at;
+
+-- name: DeleteStaleLoginFailures :exec
+DELETE FROM login_failures
+WHERE last_failure_at < @before AND (locked_until IS NULL OR locked_until < NOW());
+
+-- name: DeleteLoginFailure :exec
+DELETE FROM login_failures
+WHERE key = @key;
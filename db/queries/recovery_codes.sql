@@ -0,0 +1,18 @@
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (user_id, code_hash)
+VALUES (@user_id, @code_hash);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = @user_id;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = NOW()
+WHERE user_id = @user_id AND code_hash = @code_hash AND used_at IS NULL
+RETURNING id;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*)
+FROM recovery_codes
+WHERE user_id = @user_id AND used_at IS NULL;
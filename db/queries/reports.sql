@@ -0,0 +1,45 @@
+-- name: ListReportsByStatus :many
+SELECT
+    r.id,
+    r.poll_id,
+    p.title AS poll_title,
+    r.reporter_id,
+    u.username AS reporter_username,
+    r.reason,
+    r.details,
+    r.status,
+    r.created_at
+FROM reports r
+JOIN polls p ON p.id = r.poll_id
+LEFT JOIN users u ON u.id = r.reporter_id
+WHERE r.status = @status
+ORDER BY r.created_at ASC;
+
+-- name: GetReportByID :one
+SELECT id, poll_id, reporter_id, reason, details, status, created_at, resolved_by, resolved_at
+FROM reports
+WHERE id = @id;
+
+-- name: ResolveReport :exec
+UPDATE reports
+SET status = @status,
+    resolved_by = @resolved_by,
+    resolved_at = NOW()
+WHERE id = @id;
+
+-- name: ResolveOpenReportsByPoll :exec
+UPDATE reports
+SET status = @status,
+    resolved_by = @resolved_by,
+    resolved_at = NOW()
+WHERE poll_id = @poll_id AND status = 'open';
+
+-- name: CreateReport :execrows
+INSERT INTO reports (poll_id, reporter_id, reason, details)
+VALUES (@poll_id, @reporter_id, @reason, @details)
+ON CONFLICT (poll_id, reporter_id) WHERE status = 'open' DO NOTHING;
+
+-- name: CountOpenReportsByPoll :one
+SELECT COUNT(*)
+FROM reports
+WHERE poll_id = @poll_id AND status = 'open';
@@ -0,0 +1,38 @@
+-- name: UpsertTag :one
+INSERT INTO tags (name)
+VALUES (@name)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name;
+
+-- name: AddPollTag :exec
+INSERT INTO poll_tags (poll_id, tag_id)
+VALUES (@poll_id, @tag_id)
+ON CONFLICT DO NOTHING;
+
+-- name: GetTagsByPollIDs :many
+SELECT pt.poll_id, t.name
+FROM poll_tags pt
+JOIN tags t ON t.id = pt.tag_id
+WHERE pt.poll_id = ANY(@poll_ids::int[])
+ORDER BY t.name ASC;
+
+-- name: ListTagCounts :many
+SELECT t.name, COUNT(*) AS poll_count
+FROM tags t
+JOIN poll_tags pt ON pt.tag_id = t.id
+JOIN polls p ON p.id = pt.poll_id
+WHERE NOT p.hidden AND p.status = 'published'
+GROUP BY t.name
+ORDER BY poll_count DESC, t.name ASC
+LIMIT @max_tags;
+
+-- name: SearchTags :many
+SELECT name
+FROM tags
+WHERE name LIKE @prefix::text || '%'
+ORDER BY name ASC
+LIMIT @max_tags;
+
+-- name: DeletePollTags :exec
+DELETE FROM poll_tags
+WHERE poll_id = @poll_id;
@@ -4,7 +4,7 @@ VALUES (@username, @password, @email)
 RETURNING id, username, email;
 
 -- name: GetUserByID :one
-SELECT id, username, email
+SELECT id, username, email, totp_enabled, locale
 FROM users
 WHERE id = @id;
 
@@ -14,13 +14,14 @@ FROM users
 WHERE username = @username;
 
 -- name: GetUserByEmail :one
-SELECT id, username, email, password
+SELECT id, username, email, password, totp_enabled, role, suspended_at, locale
 FROM users
 WHERE email = @email;
 
 -- name: GetAllUsers :many
-SELECT id, username, email
-FROM users;
+SELECT id, username, email, role, suspended_at
+FROM users
+ORDER BY id ASC;
 
 -- name: UpdateUser :one
 UPDATE users
@@ -34,4 +35,46 @@ RETURNING id, username, email;
 -- name: DeleteUser :one
 DELETE FROM users
 WHERE id = @id
-RETURNING username;
\ No newline at end of file
+RETURNING username;
+
+-- name: GetUserAuthByID :one
+SELECT id, password, totp_secret, totp_enabled
+FROM users
+WHERE id = @id;
+
+-- name: SetUserTOTP :exec
+UPDATE users
+SET totp_secret = @totp_secret,
+    totp_enabled = @totp_enabled,
+    totp_last_step = @totp_last_step
+WHERE id = @id;
+
+-- name: AcceptTOTPStep :execrows
+UPDATE users
+SET totp_last_step = @step::bigint
+WHERE id = @id AND (totp_last_step IS NULL OR totp_last_step < @step::bigint);
+
+-- name: GetUserStatus :one
+SELECT role, suspended_at
+FROM users
+WHERE id = @id;
+
+-- name: SetUserRole :execrows
+UPDATE users
+SET role = @role
+WHERE id = @id;
+
+-- name: SetUserSuspendedAt :exec
+UPDATE users
+SET suspended_at = @suspended_at
+WHERE id = @id;
+
+-- name: PromoteUserToAdminByEmail :execrows
+UPDATE users
+SET role = 'admin'
+WHERE email = @email;
+
+-- name: SetUserLocale :exec
+UPDATE users
+SET locale = @locale
+WHERE id = @id;
@@ -0,0 +1,68 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, url, secret, events)
+VALUES (@user_id, @url, @secret, @events)
+RETURNING id, user_id, url, secret, events, created_at;
+
+-- name: ListWebhooksByUser :many
+SELECT id, user_id, url, secret, events, created_at
+FROM webhooks
+WHERE user_id = @user_id
+ORDER BY created_at ASC;
+
+-- name: GetWebhookByID :one
+SELECT id, user_id, url, secret, events, created_at
+FROM webhooks
+WHERE id = @id AND user_id = @user_id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = @id AND user_id = @user_id;
+
+-- name: EnqueueWebhookEvent :execrows
+INSERT INTO webhook_deliveries (webhook_id, event, payload)
+SELECT id, @event::text, @payload::jsonb
+FROM webhooks
+WHERE user_id = @user_id AND @event::text = ANY(events);
+
+-- name: EnqueueWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event, payload)
+VALUES (@webhook_id, @event, @payload)
+RETURNING id;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET attempts = d.attempts + 1,
+    next_attempt_at = @lease_until
+FROM webhooks w
+WHERE w.id = d.webhook_id
+  AND d.id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+  )
+RETURNING d.id, d.event, d.payload, d.attempts, w.url, w.secret;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_deliveries
+SET status = 'delivered',
+    last_status_code = @last_status_code,
+    last_error = '',
+    delivered_at = NOW()
+WHERE id = @id;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET status = @status,
+    last_status_code = @last_status_code,
+    last_error = @last_error,
+    next_attempt_at = @next_attempt_at
+WHERE id = @id;
+
+-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE webhook_id = @webhook_id
+ORDER BY created_at DESC, id DESC
+LIMIT @max_entries;
@@ -6,14 +6,56 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(255) UNIQUE NOT NULL
 );
 
+-- Segundo factor (TOTP) de los usuarios
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+-- Último paso de tiempo TOTP aceptado: un código no se puede volver a usar dentro de su ventana
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+
+-- Códigos de recuperación de un solo uso (se guarda solo el hash SHA-256)
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_recovery_code UNIQUE (user_id, code_hash)
+);
+
+-- Roles y suspensión de usuarios
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+DO $$
+BEGIN
+    ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'moderator', 'admin'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+-- Idioma preferido de la interfaz (NULL: se negocia con Accept-Language)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(5);
+
 -- Tabla Polls
 CREATE TABLE IF NOT EXISTS polls (
     id SERIAL PRIMARY KEY,
-    title VARCHAR(255) UNIQUE NOT NULL,
+    title VARCHAR(255) NOT NULL,
     user_id INTEGER NOT NULL,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+-- Encuestas ocultas por moderación
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Cierre opcional de la votación y cuándo se muestran los resultados
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS closes_at TIMESTAMPTZ;
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS results_visibility VARCHAR(20) NOT NULL DEFAULT 'always';
+DO $$
+BEGIN
+    ALTER TABLE polls ADD CONSTRAINT polls_results_visibility_check CHECK (results_visibility IN ('always', 'after_vote', 'after_close', 'owner_only'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
 -- Tabla Options
 CREATE TABLE IF NOT EXISTS options (
     id SERIAL PRIMARY KEY,
@@ -23,6 +65,65 @@ CREATE TABLE IF NOT EXISTS options (
     CONSTRAINT unique_option UNIQUE (poll_id, content)
 );
 
+-- Reglas de decisión: quórum (votos mínimos o porcentaje de invitados), mayoría y desempate
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS quorum_votes INTEGER;
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS invited_voters INTEGER;
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS quorum_percent INTEGER;
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS majority_percent INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS tie_break VARCHAR(20) NOT NULL DEFAULT 'no_decision';
+DO $$
+BEGIN
+    ALTER TABLE polls ADD CONSTRAINT polls_tie_break_check CHECK (tie_break IN ('no_decision', 'first_option', 'random'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+-- Resultado calculado al cerrar la votación
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS outcome VARCHAR(20);
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS winner_option_id INTEGER REFERENCES options(id) ON DELETE SET NULL;
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS decided_at TIMESTAMPTZ;
+DO $$
+BEGIN
+    ALTER TABLE polls ADD CONSTRAINT polls_outcome_check CHECK (outcome IN ('winner', 'no_quorum', 'no_majority', 'tie'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+CREATE INDEX IF NOT EXISTS idx_polls_pending_outcome ON polls(closes_at) WHERE outcome IS NULL;
+
+-- Orígenes que pueden insertar la encuesta en un iframe (vacío: solo el propio sitio)
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS embed_origins TEXT[] NOT NULL DEFAULT '{}';
+
+-- El título es único por dueño y no global: dos usuarios pueden preguntar lo mismo.
+-- polls_title_key es el nombre que Postgres le dio al UNIQUE original de la columna.
+ALTER TABLE polls DROP CONSTRAINT IF EXISTS polls_title_key;
+DO $$
+BEGIN
+    ALTER TABLE polls ADD CONSTRAINT unique_poll_title UNIQUE (user_id, title);
+EXCEPTION
+    WHEN duplicate_object OR duplicate_table THEN NULL;
+END $$;
+
+-- Comentarios de la encuesta: open (abiertos), locked (se leen pero no se agregan ni editan)
+-- o disabled (ocultos)
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS comments_mode VARCHAR(10) NOT NULL DEFAULT 'open';
+DO $$
+BEGIN
+    ALTER TABLE polls ADD CONSTRAINT polls_comments_mode_check CHECK (comments_mode IN ('open', 'locked', 'disabled'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+-- Publicación: draft (borrador, solo lo ve el dueño), scheduled (se publica sola en
+-- publish_at) o published. Las encuestas que ya existían quedan publicadas
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'published';
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+DO $$
+BEGIN
+    ALTER TABLE polls ADD CONSTRAINT polls_status_check CHECK (status IN ('draft', 'scheduled', 'published'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
 CREATE TABLE IF NOT EXISTS results (    
     id SERIAL PRIMARY KEY,
     poll_id INTEGER NOT NULL,
@@ -34,8 +135,235 @@ CREATE TABLE IF NOT EXISTS results (
     CONSTRAINT unique_result UNIQUE (poll_id, option_id, user_id)
 );
 
+-- Reportes de contenido para la cola de moderación
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    poll_id INTEGER NOT NULL,
+    reporter_id INTEGER,
+    reason VARCHAR(50) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_by INTEGER,
+    resolved_at TIMESTAMPTZ,
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT reports_status_check CHECK (status IN ('open', 'resolved', 'dismissed'))
+);
+
+-- Registro de auditoría de las acciones de administración
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INTEGER NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Rate limiting: token buckets compartidos entre réplicas
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Intentos fallidos de login para el bloqueo progresivo
+CREATE TABLE IF NOT EXISTS login_failures (
+    key VARCHAR(255) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Webhooks: cada usuario suscribe URLs a eventos de sus encuestas
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    events TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Cola de entregas de webhooks; el dispatcher reintenta con backoff exponencial
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+    CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+-- Etiquetas de las encuestas (muchos a muchos). Los nombres se guardan normalizados
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(30) UNIQUE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS poll_tags (
+    poll_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (poll_id, tag_id),
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- Comentarios en hilos. Al borrar uno se vacía el cuerpo pero se conserva la fila, así
+-- sus respuestas siguen colgando de él
+CREATE TABLE IF NOT EXISTS comments (
+    id SERIAL PRIMARY KEY,
+    poll_id INTEGER NOT NULL,
+    user_id INTEGER,
+    parent_id INTEGER,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+-- Notificaciones internas. Los votos y comentarios sin leer de una misma encuesta se
+-- acumulan en una sola fila (count) para no llenar la bandeja
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    poll_id INTEGER NOT NULL,
+    -- actor_id es el último usuario que generó el aviso
+    actor_id INTEGER,
+    count INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ,
+    emailed_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT notifications_kind_check CHECK (kind IN ('vote', 'closed', 'comment'))
+);
+
+-- Qué avisos quiere recibir cada usuario. Sin fila valen los valores por defecto
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER PRIMARY KEY,
+    on_vote BOOLEAN NOT NULL DEFAULT TRUE,
+    on_close BOOLEAN NOT NULL DEFAULT TRUE,
+    on_comment BOOLEAN NOT NULL DEFAULT TRUE,
+    email_digest BOOLEAN NOT NULL DEFAULT FALSE,
+    last_digest_at TIMESTAMPTZ,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Plantillas de encuesta reutilizables. El título puede llevar marcadores como {{date}}
+-- que se reemplazan al crear la encuesta; closes_in_minutes es el plazo de cierre relativo
+CREATE TABLE IF NOT EXISTS poll_templates (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    options TEXT[] NOT NULL,
+    results_visibility VARCHAR(20) NOT NULL DEFAULT 'always',
+    closes_in_minutes INTEGER,
+    quorum_votes INTEGER,
+    invited_voters INTEGER,
+    quorum_percent INTEGER,
+    majority_percent INTEGER NOT NULL DEFAULT 0,
+    tie_break VARCHAR(20) NOT NULL DEFAULT 'no_decision',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT unique_poll_template_name UNIQUE (user_id, name)
+);
+
+-- Series de encuestas recurrentes: cada next_run_at se crea una encuesta nueva a partir de la
+-- plantilla y se cierra la anterior. La hora (y el día del mes) salen de starts_at
+CREATE TABLE IF NOT EXISTS poll_series (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    template_id INTEGER NOT NULL,
+    frequency VARCHAR(10) NOT NULL,
+    weekdays INTEGER[] NOT NULL DEFAULT '{}',
+    cron_expr VARCHAR(100) NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    -- Una plantilla en uso por una serie no se puede borrar
+    FOREIGN KEY (template_id) REFERENCES poll_templates(id) ON DELETE RESTRICT,
+    CONSTRAINT poll_series_frequency_check CHECK (frequency IN ('daily', 'weekly', 'monthly', 'cron'))
+);
+
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS series_id INTEGER REFERENCES poll_series(id) ON DELETE SET NULL;
+
+-- Organizaciones: las encuestas de una organización las pueden administrar sus dueños y
+-- editores, no solo quien las creó
+CREATE TABLE IF NOT EXISTS organizations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    org_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    role VARCHAR(10) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, user_id),
+    FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT organization_members_role_check CHECK (role IN ('owner', 'editor', 'viewer'))
+);
+
+-- Si se borra la organización, sus encuestas vuelven a ser solo de quien las creó
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS org_id INTEGER REFERENCES organizations(id) ON DELETE SET NULL;
+-- members_only: solo los miembros de la organización pueden votar
+ALTER TABLE polls ADD COLUMN IF NOT EXISTS members_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Usuarios de integraciones externas (ej. Slack) vinculados a una cuenta de webpolls
+CREATE TABLE IF NOT EXISTS external_accounts (
+    provider VARCHAR(20) NOT NULL,
+    team_id VARCHAR(50) NOT NULL,
+    external_user_id VARCHAR(50) NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, team_id, external_user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
 -- Índices para mejorar el rendimiento
 CREATE INDEX IF NOT EXISTS idx_polls_user_id ON polls(user_id);
+CREATE INDEX IF NOT EXISTS idx_polls_scheduled ON polls(publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_polls_org_id ON polls(org_id) WHERE org_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_polls_series_id ON polls(series_id) WHERE series_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_poll_series_due ON poll_series(next_run_at) WHERE active;
 CREATE INDEX IF NOT EXISTS idx_options_poll_id ON options(poll_id);
 CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
-CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status);
+-- Un usuario no puede tener dos reportes abiertos sobre la misma encuesta
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_reporter ON reports(poll_id, reporter_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_poll_tags_tag_id ON poll_tags(tag_id);
+CREATE INDEX IF NOT EXISTS idx_comments_poll_id ON comments(poll_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, updated_at);
+-- Una sola notificación sin leer por usuario, tipo y encuesta (ver AddNotification)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id, kind, poll_id) WHERE read_at IS NULL;
@@ -4,16 +4,169 @@
 
 package db
 
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AuditLog struct {
+	ID         int32              `json:"id"`
+	ActorID    pgtype.Int4        `json:"actor_id"`
+	Action     string             `json:"action"`
+	TargetType string             `json:"target_type"`
+	TargetID   int32              `json:"target_id"`
+	Details    string             `json:"details"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type Comment struct {
+	ID        int32              `json:"id"`
+	PollID    int32              `json:"poll_id"`
+	UserID    pgtype.Int4        `json:"user_id"`
+	ParentID  pgtype.Int4        `json:"parent_id"`
+	Body      string             `json:"body"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	EditedAt  pgtype.Timestamptz `json:"edited_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type ExternalAccount struct {
+	Provider       string             `json:"provider"`
+	TeamID         string             `json:"team_id"`
+	ExternalUserID string             `json:"external_user_id"`
+	UserID         int32              `json:"user_id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type LoginFailure struct {
+	Key           string             `json:"key"`
+	Failures      int32              `json:"failures"`
+	LockedUntil   pgtype.Timestamptz `json:"locked_until"`
+	LastFailureAt pgtype.Timestamptz `json:"last_failure_at"`
+}
+
+type Notification struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Kind      string             `json:"kind"`
+	PollID    int32              `json:"poll_id"`
+	ActorID   pgtype.Int4        `json:"actor_id"`
+	Count     int32              `json:"count"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	EmailedAt pgtype.Timestamptz `json:"emailed_at"`
+}
+
+type NotificationPreference struct {
+	UserID       int32              `json:"user_id"`
+	OnVote       bool               `json:"on_vote"`
+	OnClose      bool               `json:"on_close"`
+	OnComment    bool               `json:"on_comment"`
+	EmailDigest  bool               `json:"email_digest"`
+	LastDigestAt pgtype.Timestamptz `json:"last_digest_at"`
+}
+
 type Option struct {
 	ID      int32  `json:"id"`
 	Content string `json:"content"`
 	PollID  int32  `json:"poll_id"`
 }
 
+type Organization struct {
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrganizationMember struct {
+	OrgID     int32              `json:"org_id"`
+	UserID    int32              `json:"user_id"`
+	Role      string             `json:"role"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type Poll struct {
-	ID     int32  `json:"id"`
-	Title  string `json:"title"`
-	UserID int32  `json:"user_id"`
+	ID                int32              `json:"id"`
+	Title             string             `json:"title"`
+	UserID            int32              `json:"user_id"`
+	Hidden            bool               `json:"hidden"`
+	ClosesAt          pgtype.Timestamptz `json:"closes_at"`
+	ResultsVisibility string             `json:"results_visibility"`
+	QuorumVotes       pgtype.Int4        `json:"quorum_votes"`
+	InvitedVoters     pgtype.Int4        `json:"invited_voters"`
+	QuorumPercent     pgtype.Int4        `json:"quorum_percent"`
+	MajorityPercent   int32              `json:"majority_percent"`
+	TieBreak          string             `json:"tie_break"`
+	Outcome           pgtype.Text        `json:"outcome"`
+	WinnerOptionID    pgtype.Int4        `json:"winner_option_id"`
+	DecidedAt         pgtype.Timestamptz `json:"decided_at"`
+	EmbedOrigins      []string           `json:"embed_origins"`
+	CommentsMode      string             `json:"comments_mode"`
+	Status            string             `json:"status"`
+	PublishAt         pgtype.Timestamptz `json:"publish_at"`
+	SeriesID          pgtype.Int4        `json:"series_id"`
+	OrgID             pgtype.Int4        `json:"org_id"`
+	MembersOnly       bool               `json:"members_only"`
+}
+
+type PollSeries struct {
+	ID         int32              `json:"id"`
+	UserID     int32              `json:"user_id"`
+	TemplateID int32              `json:"template_id"`
+	Frequency  string             `json:"frequency"`
+	Weekdays   []int32            `json:"weekdays"`
+	CronExpr   string             `json:"cron_expr"`
+	StartsAt   pgtype.Timestamptz `json:"starts_at"`
+	NextRunAt  pgtype.Timestamptz `json:"next_run_at"`
+	Active     bool               `json:"active"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type PollTag struct {
+	PollID int32 `json:"poll_id"`
+	TagID  int32 `json:"tag_id"`
+}
+
+type PollTemplate struct {
+	ID                int32              `json:"id"`
+	UserID            int32              `json:"user_id"`
+	Name              string             `json:"name"`
+	Title             string             `json:"title"`
+	Options           []string           `json:"options"`
+	ResultsVisibility string             `json:"results_visibility"`
+	ClosesInMinutes   pgtype.Int4        `json:"closes_in_minutes"`
+	QuorumVotes       pgtype.Int4        `json:"quorum_votes"`
+	InvitedVoters     pgtype.Int4        `json:"invited_voters"`
+	QuorumPercent     pgtype.Int4        `json:"quorum_percent"`
+	MajorityPercent   int32              `json:"majority_percent"`
+	TieBreak          string             `json:"tie_break"`
+	Tags              []string           `json:"tags"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string             `json:"key"`
+	Tokens    float64            `json:"tokens"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RecoveryCode struct {
+	ID       int32              `json:"id"`
+	UserID   int32              `json:"user_id"`
+	CodeHash string             `json:"code_hash"`
+	UsedAt   pgtype.Timestamptz `json:"used_at"`
+}
+
+type Report struct {
+	ID         int32              `json:"id"`
+	PollID     int32              `json:"poll_id"`
+	ReporterID pgtype.Int4        `json:"reporter_id"`
+	Reason     string             `json:"reason"`
+	Details    string             `json:"details"`
+	Status     string             `json:"status"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ResolvedBy pgtype.Int4        `json:"resolved_by"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
 }
 
 type Result struct {
@@ -23,9 +176,43 @@ type Result struct {
 	UserID   int32 `json:"user_id"`
 }
 
+type Tag struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
 type User struct {
-	ID       int32  `json:"id"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Email    string `json:"email"`
+	ID           int32              `json:"id"`
+	Username     string             `json:"username"`
+	Password     string             `json:"password"`
+	Email        string             `json:"email"`
+	TotpSecret   pgtype.Text        `json:"totp_secret"`
+	TotpEnabled  bool               `json:"totp_enabled"`
+	TotpLastStep pgtype.Int8        `json:"totp_last_step"`
+	Role         string             `json:"role"`
+	SuspendedAt  pgtype.Timestamptz `json:"suspended_at"`
+	Locale       pgtype.Text        `json:"locale"`
+}
+
+type Webhook struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Url       string             `json:"url"`
+	Secret    string             `json:"secret"`
+	Events    []string           `json:"events"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int32              `json:"id"`
+	WebhookID      int32              `json:"webhook_id"`
+	Event          string             `json:"event"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastStatusCode pgtype.Int4        `json:"last_status_code"`
+	LastError      string             `json:"last_error"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
 }
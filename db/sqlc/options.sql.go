@@ -37,6 +37,16 @@ func (q *Queries) DeleteOption(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteOptionsByPollID = `-- name: DeleteOptionsByPollID :exec
+DELETE FROM options
+WHERE poll_id = $1
+`
+
+func (q *Queries) DeleteOptionsByPollID(ctx context.Context, pollID int32) error {
+	_, err := q.db.Exec(ctx, deleteOptionsByPollID, pollID)
+	return err
+}
+
 const getAllOptions = `-- name: GetAllOptions :many
 SELECT id, content, poll_id
 FROM options
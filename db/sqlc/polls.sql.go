@@ -11,21 +11,88 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const closePollNow = `-- name: ClosePollNow :execrows
+UPDATE polls
+SET closes_at = NOW()
+WHERE id = $1 AND status = 'published' AND (closes_at IS NULL OR closes_at > NOW())
+`
+
+func (q *Queries) ClosePollNow(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, closePollNow, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createPoll = `-- name: CreatePoll :one
-INSERT INTO polls (title, user_id)
-VALUES ($1, $2)
-RETURNING id,title, user_id
+INSERT INTO polls (
+    title, user_id, closes_at, results_visibility,
+    quorum_votes, invited_voters, quorum_percent, majority_percent, tie_break,
+    status, publish_at, series_id, org_id, members_only
+)
+VALUES (
+    $1, $2, $3, $4,
+    $5, $6, $7, $8, $9,
+    $10, $11, $12, $13, $14
+)
+RETURNING id, title, user_id, closes_at, results_visibility, status, publish_at
 `
 
 type CreatePollParams struct {
-	Title  string `json:"title"`
-	UserID int32  `json:"user_id"`
+	Title             string             `json:"title"`
+	UserID            int32              `json:"user_id"`
+	ClosesAt          pgtype.Timestamptz `json:"closes_at"`
+	ResultsVisibility string             `json:"results_visibility"`
+	QuorumVotes       pgtype.Int4        `json:"quorum_votes"`
+	InvitedVoters     pgtype.Int4        `json:"invited_voters"`
+	QuorumPercent     pgtype.Int4        `json:"quorum_percent"`
+	MajorityPercent   int32              `json:"majority_percent"`
+	TieBreak          string             `json:"tie_break"`
+	Status            string             `json:"status"`
+	PublishAt         pgtype.Timestamptz `json:"publish_at"`
+	SeriesID          pgtype.Int4        `json:"series_id"`
+	OrgID             pgtype.Int4        `json:"org_id"`
+	MembersOnly       bool               `json:"members_only"`
+}
+
+type CreatePollRow struct {
+	ID                int32              `json:"id"`
+	Title             string             `json:"title"`
+	UserID            int32              `json:"user_id"`
+	ClosesAt          pgtype.Timestamptz `json:"closes_at"`
+	ResultsVisibility string             `json:"results_visibility"`
+	Status            string             `json:"status"`
+	PublishAt         pgtype.Timestamptz `json:"publish_at"`
 }
 
-func (q *Queries) CreatePoll(ctx context.Context, arg CreatePollParams) (Poll, error) {
-	row := q.db.QueryRow(ctx, createPoll, arg.Title, arg.UserID)
-	var i Poll
-	err := row.Scan(&i.ID, &i.Title, &i.UserID)
+func (q *Queries) CreatePoll(ctx context.Context, arg CreatePollParams) (CreatePollRow, error) {
+	row := q.db.QueryRow(ctx, createPoll,
+		arg.Title,
+		arg.UserID,
+		arg.ClosesAt,
+		arg.ResultsVisibility,
+		arg.QuorumVotes,
+		arg.InvitedVoters,
+		arg.QuorumPercent,
+		arg.MajorityPercent,
+		arg.TieBreak,
+		arg.Status,
+		arg.PublishAt,
+		arg.SeriesID,
+		arg.OrgID,
+		arg.MembersOnly,
+	)
+	var i CreatePollRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.UserID,
+		&i.ClosesAt,
+		&i.ResultsVisibility,
+		&i.Status,
+		&i.PublishAt,
+	)
 	return i, err
 }
 
@@ -50,9 +117,26 @@ SELECT
 FROM polls p
 JOIN options o ON p.id = o.poll_id
 LEFT JOIN results r ON p.id = r.poll_id AND r.user_id = $1
+WHERE NOT p.hidden
+    AND p.status = 'published'
+    AND ($2::text IS NULL OR EXISTS (
+        SELECT 1 FROM poll_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.poll_id = p.id AND t.name = $2::text
+    ))
+    AND ($3::text IS NULL OR p.title ILIKE '%' || $3::text || '%')
+    AND ($4::boolean IS NULL
+        OR $4::boolean = (p.closes_at IS NULL OR p.closes_at > NOW()))
 ORDER BY p.id ASC
 `
 
+type GetAllPollsParams struct {
+	UserID int32       `json:"user_id"`
+	Tag    pgtype.Text `json:"tag"`
+	Search pgtype.Text `json:"search"`
+	Open   pgtype.Bool `json:"open"`
+}
+
 type GetAllPollsRow struct {
 	PollID            int32       `json:"poll_id"`
 	Title             string      `json:"title"`
@@ -62,8 +146,13 @@ type GetAllPollsRow struct {
 	UserVotedOptionID pgtype.Int4 `json:"user_voted_option_id"`
 }
 
-func (q *Queries) GetAllPolls(ctx context.Context, userID int32) ([]GetAllPollsRow, error) {
-	rows, err := q.db.Query(ctx, getAllPolls, userID)
+func (q *Queries) GetAllPolls(ctx context.Context, arg GetAllPollsParams) ([]GetAllPollsRow, error) {
+	rows, err := q.db.Query(ctx, getAllPolls,
+		arg.UserID,
+		arg.Tag,
+		arg.Search,
+		arg.Open,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -94,20 +183,57 @@ SELECT
     polls.id,
     polls.title,
     polls.user_id,
+    polls.hidden,
+    polls.closes_at,
+    polls.results_visibility,
+    polls.quorum_votes,
+    polls.invited_voters,
+    polls.quorum_percent,
+    polls.majority_percent,
+    polls.tie_break,
+    polls.outcome,
+    polls.winner_option_id,
+    polls.decided_at,
+    polls.embed_origins,
+    polls.status,
+    polls.publish_at,
+    polls.series_id,
+    polls.org_id,
+    polls.members_only,
+    organizations.name AS org_name,
     options.id AS option_id,
     options.content AS option_content
 FROM polls
 inner JOIN options ON polls.id = options.poll_id
+LEFT JOIN organizations ON organizations.id = polls.org_id
 WHERE polls.id = $1
 ORDER BY polls.id ASC
 `
 
 type GetPollByIDRow struct {
-	ID            int32  `json:"id"`
-	Title         string `json:"title"`
-	UserID        int32  `json:"user_id"`
-	OptionID      int32  `json:"option_id"`
-	OptionContent string `json:"option_content"`
+	ID                int32              `json:"id"`
+	Title             string             `json:"title"`
+	UserID            int32              `json:"user_id"`
+	Hidden            bool               `json:"hidden"`
+	ClosesAt          pgtype.Timestamptz `json:"closes_at"`
+	ResultsVisibility string             `json:"results_visibility"`
+	QuorumVotes       pgtype.Int4        `json:"quorum_votes"`
+	InvitedVoters     pgtype.Int4        `json:"invited_voters"`
+	QuorumPercent     pgtype.Int4        `json:"quorum_percent"`
+	MajorityPercent   int32              `json:"majority_percent"`
+	TieBreak          string             `json:"tie_break"`
+	Outcome           pgtype.Text        `json:"outcome"`
+	WinnerOptionID    pgtype.Int4        `json:"winner_option_id"`
+	DecidedAt         pgtype.Timestamptz `json:"decided_at"`
+	EmbedOrigins      []string           `json:"embed_origins"`
+	Status            string             `json:"status"`
+	PublishAt         pgtype.Timestamptz `json:"publish_at"`
+	SeriesID          pgtype.Int4        `json:"series_id"`
+	OrgID             pgtype.Int4        `json:"org_id"`
+	MembersOnly       bool               `json:"members_only"`
+	OrgName           pgtype.Text        `json:"org_name"`
+	OptionID          int32              `json:"option_id"`
+	OptionContent     string             `json:"option_content"`
 }
 
 func (q *Queries) GetPollByID(ctx context.Context, id int32) ([]GetPollByIDRow, error) {
@@ -123,6 +249,24 @@ func (q *Queries) GetPollByID(ctx context.Context, id int32) ([]GetPollByIDRow,
 			&i.ID,
 			&i.Title,
 			&i.UserID,
+			&i.Hidden,
+			&i.ClosesAt,
+			&i.ResultsVisibility,
+			&i.QuorumVotes,
+			&i.InvitedVoters,
+			&i.QuorumPercent,
+			&i.MajorityPercent,
+			&i.TieBreak,
+			&i.Outcome,
+			&i.WinnerOptionID,
+			&i.DecidedAt,
+			&i.EmbedOrigins,
+			&i.Status,
+			&i.PublishAt,
+			&i.SeriesID,
+			&i.OrgID,
+			&i.MembersOnly,
+			&i.OrgName,
 			&i.OptionID,
 			&i.OptionContent,
 		); err != nil {
@@ -136,37 +280,151 @@ func (q *Queries) GetPollByID(ctx context.Context, id int32) ([]GetPollByIDRow,
 	return items, nil
 }
 
+const getPollCommentSettings = `-- name: GetPollCommentSettings :one
+SELECT id, user_id, org_id, hidden, status, comments_mode
+FROM polls
+WHERE id = $1
+`
+
+type GetPollCommentSettingsRow struct {
+	ID           int32       `json:"id"`
+	UserID       int32       `json:"user_id"`
+	OrgID        pgtype.Int4 `json:"org_id"`
+	Hidden       bool        `json:"hidden"`
+	Status       string      `json:"status"`
+	CommentsMode string      `json:"comments_mode"`
+}
+
+func (q *Queries) GetPollCommentSettings(ctx context.Context, id int32) (GetPollCommentSettingsRow, error) {
+	row := q.db.QueryRow(ctx, getPollCommentSettings, id)
+	var i GetPollCommentSettingsRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Hidden,
+		&i.Status,
+		&i.CommentsMode,
+	)
+	return i, err
+}
+
+const getPollsByOrgID = `-- name: GetPollsByOrgID :many
+SELECT
+    p.id AS poll_id,
+    p.title,
+    p.user_id,
+    p.hidden,
+    p.status,
+    p.publish_at,
+    p.org_id,
+    org.name AS org_name,
+    o.id AS option_id,
+    o.content AS option_content,
+    r.option_id AS user_voted_option_id
+FROM polls p
+JOIN options o ON p.id = o.poll_id
+LEFT JOIN organizations org ON org.id = p.org_id
+LEFT JOIN results r ON p.id = r.poll_id AND r.user_id = $1
+WHERE p.org_id = $2 AND (p.status = 'published' OR $3::boolean)
+ORDER BY p.id ASC
+`
+
+type GetPollsByOrgIDParams struct {
+	ViewerID  int32       `json:"viewer_id"`
+	OrgID     pgtype.Int4 `json:"org_id"`
+	CanManage bool        `json:"can_manage"`
+}
+
+type GetPollsByOrgIDRow struct {
+	PollID            int32              `json:"poll_id"`
+	Title             string             `json:"title"`
+	UserID            int32              `json:"user_id"`
+	Hidden            bool               `json:"hidden"`
+	Status            string             `json:"status"`
+	PublishAt         pgtype.Timestamptz `json:"publish_at"`
+	OrgID             pgtype.Int4        `json:"org_id"`
+	OrgName           pgtype.Text        `json:"org_name"`
+	OptionID          int32              `json:"option_id"`
+	OptionContent     string             `json:"option_content"`
+	UserVotedOptionID pgtype.Int4        `json:"user_voted_option_id"`
+}
+
+func (q *Queries) GetPollsByOrgID(ctx context.Context, arg GetPollsByOrgIDParams) ([]GetPollsByOrgIDRow, error) {
+	rows, err := q.db.Query(ctx, getPollsByOrgID, arg.ViewerID, arg.OrgID, arg.CanManage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPollsByOrgIDRow
+	for rows.Next() {
+		var i GetPollsByOrgIDRow
+		if err := rows.Scan(
+			&i.PollID,
+			&i.Title,
+			&i.UserID,
+			&i.Hidden,
+			&i.Status,
+			&i.PublishAt,
+			&i.OrgID,
+			&i.OrgName,
+			&i.OptionID,
+			&i.OptionContent,
+			&i.UserVotedOptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPollsByUserID = `-- name: GetPollsByUserID :many
 SELECT
     p.id AS poll_id,
     p.title,
     p.user_id,
+    p.hidden,
+    p.status,
+    p.publish_at,
+    p.org_id,
+    org.name AS org_name,
     o.id AS option_id,
     o.content AS option_content,
     r.option_id AS user_voted_option_id
 FROM polls p
 JOIN options o ON p.id = o.poll_id
+LEFT JOIN organizations org ON org.id = p.org_id
 LEFT JOIN results r ON p.id = r.poll_id AND r.user_id = $1
-WHERE p.user_id = $2
+WHERE p.user_id = $2 OR p.org_id = ANY($3::int[])
 ORDER BY p.id ASC
 `
 
 type GetPollsByUserIDParams struct {
-	ViewerID int32 `json:"viewer_id"`
-	OwnerID  int32 `json:"owner_id"`
+	ViewerID int32   `json:"viewer_id"`
+	OwnerID  int32   `json:"owner_id"`
+	OrgIds   []int32 `json:"org_ids"`
 }
 
 type GetPollsByUserIDRow struct {
-	PollID            int32       `json:"poll_id"`
-	Title             string      `json:"title"`
-	UserID            int32       `json:"user_id"`
-	OptionID          int32       `json:"option_id"`
-	OptionContent     string      `json:"option_content"`
-	UserVotedOptionID pgtype.Int4 `json:"user_voted_option_id"`
+	PollID            int32              `json:"poll_id"`
+	Title             string             `json:"title"`
+	UserID            int32              `json:"user_id"`
+	Hidden            bool               `json:"hidden"`
+	Status            string             `json:"status"`
+	PublishAt         pgtype.Timestamptz `json:"publish_at"`
+	OrgID             pgtype.Int4        `json:"org_id"`
+	OrgName           pgtype.Text        `json:"org_name"`
+	OptionID          int32              `json:"option_id"`
+	OptionContent     string             `json:"option_content"`
+	UserVotedOptionID pgtype.Int4        `json:"user_voted_option_id"`
 }
 
 func (q *Queries) GetPollsByUserID(ctx context.Context, arg GetPollsByUserIDParams) ([]GetPollsByUserIDRow, error) {
-	rows, err := q.db.Query(ctx, getPollsByUserID, arg.ViewerID, arg.OwnerID)
+	rows, err := q.db.Query(ctx, getPollsByUserID, arg.ViewerID, arg.OwnerID, arg.OrgIds)
 	if err != nil {
 		return nil, err
 	}
@@ -178,6 +436,11 @@ func (q *Queries) GetPollsByUserID(ctx context.Context, arg GetPollsByUserIDPara
 			&i.PollID,
 			&i.Title,
 			&i.UserID,
+			&i.Hidden,
+			&i.Status,
+			&i.PublishAt,
+			&i.OrgID,
+			&i.OrgName,
 			&i.OptionID,
 			&i.OptionContent,
 			&i.UserVotedOptionID,
@@ -192,6 +455,244 @@ func (q *Queries) GetPollsByUserID(ctx context.Context, arg GetPollsByUserIDPara
 	return items, nil
 }
 
+const getPollsForModeration = `-- name: GetPollsForModeration :many
+SELECT
+    p.id,
+    p.title,
+    p.user_id,
+    u.username,
+    p.hidden,
+    COUNT(r.id) FILTER (WHERE r.status = 'open') AS open_reports
+FROM polls p
+JOIN users u ON u.id = p.user_id
+LEFT JOIN reports r ON r.poll_id = p.id
+GROUP BY p.id, u.username
+ORDER BY open_reports DESC, p.id DESC
+`
+
+type GetPollsForModerationRow struct {
+	ID          int32  `json:"id"`
+	Title       string `json:"title"`
+	UserID      int32  `json:"user_id"`
+	Username    string `json:"username"`
+	Hidden      bool   `json:"hidden"`
+	OpenReports int64  `json:"open_reports"`
+}
+
+func (q *Queries) GetPollsForModeration(ctx context.Context) ([]GetPollsForModerationRow, error) {
+	rows, err := q.db.Query(ctx, getPollsForModeration)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPollsForModerationRow
+	for rows.Next() {
+		var i GetPollsForModerationRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.UserID,
+			&i.Username,
+			&i.Hidden,
+			&i.OpenReports,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPollsPendingOutcome = `-- name: ListPollsPendingOutcome :many
+SELECT id
+FROM polls
+WHERE outcome IS NULL AND closes_at <= NOW() AND status = 'published'
+ORDER BY closes_at ASC
+`
+
+func (q *Queries) ListPollsPendingOutcome(ctx context.Context) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listPollsPendingOutcome)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDuePolls = `-- name: PublishDuePolls :many
+UPDATE polls
+SET status = 'published'
+WHERE status = 'scheduled' AND publish_at <= NOW()
+RETURNING id, user_id, title
+`
+
+type PublishDuePollsRow struct {
+	ID     int32  `json:"id"`
+	UserID int32  `json:"user_id"`
+	Title  string `json:"title"`
+}
+
+func (q *Queries) PublishDuePolls(ctx context.Context) ([]PublishDuePollsRow, error) {
+	rows, err := q.db.Query(ctx, publishDuePolls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PublishDuePollsRow
+	for rows.Next() {
+		var i PublishDuePollsRow
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPollCommentsMode = `-- name: SetPollCommentsMode :execrows
+UPDATE polls
+SET comments_mode = $1
+WHERE id = $2
+`
+
+type SetPollCommentsModeParams struct {
+	CommentsMode string `json:"comments_mode"`
+	ID           int32  `json:"id"`
+}
+
+func (q *Queries) SetPollCommentsMode(ctx context.Context, arg SetPollCommentsModeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPollCommentsMode, arg.CommentsMode, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPollHidden = `-- name: SetPollHidden :execrows
+UPDATE polls
+SET hidden = $1
+WHERE id = $2
+`
+
+type SetPollHiddenParams struct {
+	Hidden bool  `json:"hidden"`
+	ID     int32 `json:"id"`
+}
+
+func (q *Queries) SetPollHidden(ctx context.Context, arg SetPollHiddenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPollHidden, arg.Hidden, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPollOutcome = `-- name: SetPollOutcome :execrows
+UPDATE polls
+SET outcome = $1, winner_option_id = $2, decided_at = NOW()
+WHERE id = $3 AND outcome IS NULL
+`
+
+type SetPollOutcomeParams struct {
+	Outcome        pgtype.Text `json:"outcome"`
+	WinnerOptionID pgtype.Int4 `json:"winner_option_id"`
+	ID             int32       `json:"id"`
+}
+
+func (q *Queries) SetPollOutcome(ctx context.Context, arg SetPollOutcomeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPollOutcome, arg.Outcome, arg.WinnerOptionID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPollPublication = `-- name: SetPollPublication :execrows
+UPDATE polls
+SET status = $1, publish_at = $2
+WHERE id = $3 AND status <> 'published'
+`
+
+type SetPollPublicationParams struct {
+	Status    string             `json:"status"`
+	PublishAt pgtype.Timestamptz `json:"publish_at"`
+	ID        int32              `json:"id"`
+}
+
+func (q *Queries) SetPollPublication(ctx context.Context, arg SetPollPublicationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPollPublication, arg.Status, arg.PublishAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateDraftPoll = `-- name: UpdateDraftPoll :execrows
+UPDATE polls
+SET title = $1,
+    closes_at = $2,
+    results_visibility = $3,
+    quorum_votes = $4,
+    invited_voters = $5,
+    quorum_percent = $6,
+    majority_percent = $7,
+    tie_break = $8,
+    org_id = $9,
+    members_only = $10
+WHERE id = $11 AND status <> 'published'
+`
+
+type UpdateDraftPollParams struct {
+	Title             string             `json:"title"`
+	ClosesAt          pgtype.Timestamptz `json:"closes_at"`
+	ResultsVisibility string             `json:"results_visibility"`
+	QuorumVotes       pgtype.Int4        `json:"quorum_votes"`
+	InvitedVoters     pgtype.Int4        `json:"invited_voters"`
+	QuorumPercent     pgtype.Int4        `json:"quorum_percent"`
+	MajorityPercent   int32              `json:"majority_percent"`
+	TieBreak          string             `json:"tie_break"`
+	OrgID             pgtype.Int4        `json:"org_id"`
+	MembersOnly       bool               `json:"members_only"`
+	ID                int32              `json:"id"`
+}
+
+func (q *Queries) UpdateDraftPoll(ctx context.Context, arg UpdateDraftPollParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateDraftPoll,
+		arg.Title,
+		arg.ClosesAt,
+		arg.ResultsVisibility,
+		arg.QuorumVotes,
+		arg.InvitedVoters,
+		arg.QuorumPercent,
+		arg.MajorityPercent,
+		arg.TieBreak,
+		arg.OrgID,
+		arg.MembersOnly,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updatePoll = `-- name: UpdatePoll :exec
 UPDATE polls
 SET title = $1
@@ -207,3 +708,22 @@ func (q *Queries) UpdatePoll(ctx context.Context, arg UpdatePollParams) error {
 	_, err := q.db.Exec(ctx, updatePoll, arg.Title, arg.ID)
 	return err
 }
+
+const updatePollEmbedOrigins = `-- name: UpdatePollEmbedOrigins :execrows
+UPDATE polls
+SET embed_origins = $1
+WHERE id = $2
+`
+
+type UpdatePollEmbedOriginsParams struct {
+	EmbedOrigins []string `json:"embed_origins"`
+	ID           int32    `json:"id"`
+}
+
+func (q *Queries) UpdatePollEmbedOrigins(ctx context.Context, arg UpdatePollEmbedOriginsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePollEmbedOrigins, arg.EmbedOrigins, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -11,6 +11,25 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acceptTOTPStep = `-- name: AcceptTOTPStep :execrows
+UPDATE users
+SET totp_last_step = $1::bigint
+WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1::bigint)
+`
+
+type AcceptTOTPStepParams struct {
+	Step int64 `json:"step"`
+	ID   int32 `json:"id"`
+}
+
+func (q *Queries) AcceptTOTPStep(ctx context.Context, arg AcceptTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acceptTOTPStep, arg.Step, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (username,password, email)
 VALUES ($1, $2, $3)
@@ -50,14 +69,17 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) (string, error) {
 }
 
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, username, email
+SELECT id, username, email, role, suspended_at
 FROM users
+ORDER BY id ASC
 `
 
 type GetAllUsersRow struct {
-	ID       int32  `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	ID          int32              `json:"id"`
+	Username    string             `json:"username"`
+	Email       string             `json:"email"`
+	Role        string             `json:"role"`
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
 }
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
@@ -69,7 +91,13 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 	var items []GetAllUsersRow
 	for rows.Next() {
 		var i GetAllUsersRow
-		if err := rows.Scan(&i.ID, &i.Username, &i.Email); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.Role,
+			&i.SuspendedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -80,17 +108,46 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 	return items, nil
 }
 
+const getUserAuthByID = `-- name: GetUserAuthByID :one
+SELECT id, password, totp_secret, totp_enabled
+FROM users
+WHERE id = $1
+`
+
+type GetUserAuthByIDRow struct {
+	ID          int32       `json:"id"`
+	Password    string      `json:"password"`
+	TotpSecret  pgtype.Text `json:"totp_secret"`
+	TotpEnabled bool        `json:"totp_enabled"`
+}
+
+func (q *Queries) GetUserAuthByID(ctx context.Context, id int32) (GetUserAuthByIDRow, error) {
+	row := q.db.QueryRow(ctx, getUserAuthByID, id)
+	var i GetUserAuthByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Password,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password
+SELECT id, username, email, password, totp_enabled, role, suspended_at, locale
 FROM users
 WHERE email = $1
 `
 
 type GetUserByEmailRow struct {
-	ID       int32  `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	ID          int32              `json:"id"`
+	Username    string             `json:"username"`
+	Email       string             `json:"email"`
+	Password    string             `json:"password"`
+	TotpEnabled bool               `json:"totp_enabled"`
+	Role        string             `json:"role"`
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
+	Locale      pgtype.Text        `json:"locale"`
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -101,26 +158,38 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.Username,
 		&i.Email,
 		&i.Password,
+		&i.TotpEnabled,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email
+SELECT id, username, email, totp_enabled, locale
 FROM users
 WHERE id = $1
 `
 
 type GetUserByIDRow struct {
-	ID       int32  `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	ID          int32       `json:"id"`
+	Username    string      `json:"username"`
+	Email       string      `json:"email"`
+	TotpEnabled bool        `json:"totp_enabled"`
+	Locale      pgtype.Text `json:"locale"`
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id int32) (GetUserByIDRow, error) {
 	row := q.db.QueryRow(ctx, getUserByID, id)
 	var i GetUserByIDRow
-	err := row.Scan(&i.ID, &i.Username, &i.Email)
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.TotpEnabled,
+		&i.Locale,
+	)
 	return i, err
 }
 
@@ -143,6 +212,114 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (GetUs
 	return i, err
 }
 
+const getUserStatus = `-- name: GetUserStatus :one
+SELECT role, suspended_at
+FROM users
+WHERE id = $1
+`
+
+type GetUserStatusRow struct {
+	Role        string             `json:"role"`
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
+}
+
+func (q *Queries) GetUserStatus(ctx context.Context, id int32) (GetUserStatusRow, error) {
+	row := q.db.QueryRow(ctx, getUserStatus, id)
+	var i GetUserStatusRow
+	err := row.Scan(&i.Role, &i.SuspendedAt)
+	return i, err
+}
+
+const promoteUserToAdminByEmail = `-- name: PromoteUserToAdminByEmail :execrows
+UPDATE users
+SET role = 'admin'
+WHERE email = $1
+`
+
+func (q *Queries) PromoteUserToAdminByEmail(ctx context.Context, email string) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteUserToAdminByEmail, email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserLocale = `-- name: SetUserLocale :exec
+UPDATE users
+SET locale = $1
+WHERE id = $2
+`
+
+type SetUserLocaleParams struct {
+	Locale pgtype.Text `json:"locale"`
+	ID     int32       `json:"id"`
+}
+
+func (q *Queries) SetUserLocale(ctx context.Context, arg SetUserLocaleParams) error {
+	_, err := q.db.Exec(ctx, setUserLocale, arg.Locale, arg.ID)
+	return err
+}
+
+const setUserRole = `-- name: SetUserRole :execrows
+UPDATE users
+SET role = $1
+WHERE id = $2
+`
+
+type SetUserRoleParams struct {
+	Role string `json:"role"`
+	ID   int32  `json:"id"`
+}
+
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserRole, arg.Role, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserSuspendedAt = `-- name: SetUserSuspendedAt :exec
+UPDATE users
+SET suspended_at = $1
+WHERE id = $2
+`
+
+type SetUserSuspendedAtParams struct {
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
+	ID          int32              `json:"id"`
+}
+
+func (q *Queries) SetUserSuspendedAt(ctx context.Context, arg SetUserSuspendedAtParams) error {
+	_, err := q.db.Exec(ctx, setUserSuspendedAt, arg.SuspendedAt, arg.ID)
+	return err
+}
+
+const setUserTOTP = `-- name: SetUserTOTP :exec
+UPDATE users
+SET totp_secret = $1,
+    totp_enabled = $2,
+    totp_last_step = $3
+WHERE id = $4
+`
+
+type SetUserTOTPParams struct {
+	TotpSecret   pgtype.Text `json:"totp_secret"`
+	TotpEnabled  bool        `json:"totp_enabled"`
+	TotpLastStep pgtype.Int8 `json:"totp_last_step"`
+	ID           int32       `json:"id"`
+}
+
+func (q *Queries) SetUserTOTP(ctx context.Context, arg SetUserTOTPParams) error {
+	_, err := q.db.Exec(ctx, setUserTOTP,
+		arg.TotpSecret,
+		arg.TotpEnabled,
+		arg.TotpLastStep,
+		arg.ID,
+	)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET
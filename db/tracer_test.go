@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"-- name: GetPollByID :many\nSELECT * FROM polls WHERE id = $1", "GetPollByID"},
+		{"  \n-- name: Vote :exec\nINSERT INTO results", "Vote"},
+		{"select 1", "SELECT"},
+		{"-- name: ", "--"},
+		{"", "query"},
+	}
+	for _, tt := range tests {
+		if got := queryName(tt.sql); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tests := []struct {
+		name   string
+		err    error
+		status codes.Code
+	}{
+		{"sin error", nil, codes.Unset},
+		// Que no haya filas no es un error de la base
+		{"sin filas", pgx.ErrNoRows, codes.Unset},
+		{"error", errors.New("conexión perdida"), codes.Error},
+	}
+	var tracer queryTracer
+	for _, tt := range tests {
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "-- name: GetPollByID :many\nSELECT 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: tt.err})
+
+		spans := recorder.Ended()
+		span := spans[len(spans)-1]
+		if span.Name() != "GetPollByID" {
+			t.Errorf("%s: span = %q, want GetPollByID", tt.name, span.Name())
+		}
+		if span.Status().Code != tt.status {
+			t.Errorf("%s: status = %v, want %v", tt.name, span.Status().Code, tt.status)
+		}
+	}
+}
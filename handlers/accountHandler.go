@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// accountHandler maneja la configuración de la cuenta del usuario logueado.
+type accountHandler struct {
+	twoFactor *services.TwoFactorService
+}
+
+// NewAccountHandler inyecta TwoFactorService
+func NewAccountHandler(twoFactor *services.TwoFactorService) *accountHandler {
+	return &accountHandler{twoFactor: twoFactor}
+}
+
+func (h *accountHandler) GetSecurity(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	status, err := h.twoFactor.Status(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting 2FA status", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_security"))
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.AccountSecurity(status).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.AccountSecurity(status), i18n.T(r.Context(), "title.security"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// SetupTwoFactor genera un secreto nuevo y lo guarda en la sesión hasta que el usuario lo confirme.
+func (h *accountHandler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	username, _ := r.Context().Value(middleware.UsernameKey).(string)
+
+	enrollment, err := h.twoFactor.BeginEnrollment(r.Context(), username)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error generating TOTP secret", "error", err)
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "twofactor.setup_failed"))
+		return
+	}
+
+	session := utils.GetSession(r)
+	session.Values["pending_totp_secret"] = enrollment.Secret
+	utils.SaveSession(w, r, session)
+
+	views.TwoFactorEnrollment(enrollment).Render(r.Context(), w)
+}
+
+func (h *accountHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+	username, _ := r.Context().Value(middleware.UsernameKey).(string)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	session := utils.GetSession(r)
+	secret, ok := session.Values["pending_totp_secret"].(string)
+	if !ok || secret == "" {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "twofactor.setup_expired"))
+		return
+	}
+
+	codes, err := h.twoFactor.Enable(r.Context(), userID, secret, r.FormValue("code"))
+	if err != nil {
+		// Volvemos a mostrar el mismo QR para que el usuario reintente
+		enrollment, enrollErr := h.twoFactor.Enrollment(username, secret)
+		if enrollErr != nil {
+			respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "twofactor.enable_failed"))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		views.TwoFactorEnrollment(enrollment).Render(r.Context(), w)
+		components.Toast(i18n.Message(r.Context(), err), true).Render(r.Context(), w)
+		return
+	}
+
+	delete(session.Values, "pending_totp_secret")
+	utils.SaveSession(w, r, session)
+
+	views.RecoveryCodes(codes).Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "twofactor.enabled_toast"), false).Render(r.Context(), w)
+}
+
+func (h *accountHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	if err := h.twoFactor.Disable(r.Context(), userID, r.FormValue("password")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	status, err := h.twoFactor.Status(r.Context(), userID)
+	if err != nil {
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_security"))
+		return
+	}
+
+	views.TwoFactorPanel(status).Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "twofactor.disabled_toast"), false).Render(r.Context(), w)
+}
+
+func (h *accountHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(r.Context(), userID, r.FormValue("password"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	views.RecoveryCodes(codes).Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "twofactor.codes_regenerated"), false).Render(r.Context(), w)
+}
+
+// respondToastError muestra el error como toast sin reemplazar el contenido actual.
+func respondToastError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	w.Header().Set("HX-Reswap", "none")
+	w.WriteHeader(code)
+	components.Toast(message, true).Render(r.Context(), w)
+}
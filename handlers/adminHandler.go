@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+
+	"github.com/a-h/templ"
+)
+
+// adminHandler maneja el panel de administración y moderación (/admin).
+type adminHandler struct {
+	service *services.AdminService
+}
+
+// NewAdminHandler inyecta AdminService
+func NewAdminHandler(service *services.AdminService) *adminHandler {
+	return &adminHandler{service: service}
+}
+
+func (h *adminHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	table, err := h.usersTable(r)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting users", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_users"))
+		return
+	}
+	h.renderPage(w, r, "users", table)
+}
+
+func (h *adminHandler) GetPolls(w http.ResponseWriter, r *http.Request) {
+	polls, err := h.service.ListPolls(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting polls for moderation", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_polls"))
+		return
+	}
+	h.renderPage(w, r, "polls", views.AdminPollsTable(polls))
+}
+
+func (h *adminHandler) GetReports(w http.ResponseWriter, r *http.Request) {
+	status := reportStatus(r)
+	reports, err := h.service.ListReports(r.Context(), status)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting reports", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_reports"))
+		return
+	}
+	h.renderPage(w, r, "reports", views.AdminReportsTable(reports, status))
+}
+
+func (h *adminHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.service.ListAuditLog(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting audit log", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_audit"))
+		return
+	}
+	h.renderPage(w, r, "audit", views.AdminAuditTable(entries))
+}
+
+func (h *adminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.setSuspended(w, r, true, i18n.T(r.Context(), "admin.user_suspended"))
+}
+
+func (h *adminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.setSuspended(w, r, false, i18n.T(r.Context(), "admin.user_reactivated"))
+}
+
+func (h *adminHandler) setSuspended(w http.ResponseWriter, r *http.Request, suspended bool, message string) {
+	userID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_user_id"))
+		return
+	}
+
+	if err := h.service.SetUserSuspended(r.Context(), actor(r), userID, suspended); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	table, err := h.usersTable(r)
+	if err != nil {
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_users"))
+		return
+	}
+	table.Render(r.Context(), w)
+	components.Toast(message, false).Render(r.Context(), w)
+}
+
+func (h *adminHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_user_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	if err := h.service.SetUserRole(r.Context(), actor(r), userID, r.FormValue("role")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	table, err := h.usersTable(r)
+	if err != nil {
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_users"))
+		return
+	}
+	table.Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "admin.role_updated"), false).Render(r.Context(), w)
+}
+
+func (h *adminHandler) HidePoll(w http.ResponseWriter, r *http.Request) {
+	h.setPollHidden(w, r, true, i18n.T(r.Context(), "admin.poll_hidden"))
+}
+
+func (h *adminHandler) UnhidePoll(w http.ResponseWriter, r *http.Request) {
+	h.setPollHidden(w, r, false, i18n.T(r.Context(), "admin.poll_visible"))
+}
+
+func (h *adminHandler) setPollHidden(w http.ResponseWriter, r *http.Request, hidden bool, message string) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+
+	if err := h.service.SetPollHidden(r.Context(), actor(r), pollID, hidden); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.renderPollsTable(w, r, message)
+}
+
+func (h *adminHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+
+	if err := h.service.DeletePoll(r.Context(), actor(r), pollID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.renderPollsTable(w, r, i18n.T(r.Context(), "admin.poll_deleted"))
+}
+
+func (h *adminHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	reportID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_report_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	if err := h.service.ResolveReport(r.Context(), actor(r), reportID, r.FormValue("action")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	reports, err := h.service.ListReports(r.Context(), services.ReportOpen)
+	if err != nil {
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_reports"))
+		return
+	}
+	views.AdminReportsTable(reports, services.ReportOpen).Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "admin.report_reviewed"), false).Render(r.Context(), w)
+}
+
+func (h *adminHandler) usersTable(r *http.Request) (templ.Component, error) {
+	users, err := h.service.ListUsers(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	current := actor(r)
+	return views.AdminUsersTable(users, current.ID, current.Role == services.RoleAdmin), nil
+}
+
+func (h *adminHandler) renderPollsTable(w http.ResponseWriter, r *http.Request, message string) {
+	polls, err := h.service.ListPolls(r.Context())
+	if err != nil {
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_polls"))
+		return
+	}
+	views.AdminPollsTable(polls).Render(r.Context(), w)
+	components.Toast(message, false).Render(r.Context(), w)
+}
+
+func (h *adminHandler) renderPage(w http.ResponseWriter, r *http.Request, tab string, table templ.Component) {
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.AdminPage(tab, table).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err := views.Layout(views.AdminPage(tab, table), i18n.T(r.Context(), "title.admin"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// actor arma el Actor de la auditoría a partir del usuario autenticado.
+func actor(r *http.Request) services.Actor {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int32)
+	return services.Actor{ID: userID, Role: middleware.Role(r.Context())}
+}
+
+func reportStatus(r *http.Request) string {
+	switch status := r.URL.Query().Get("status"); status {
+	case services.ReportResolved, services.ReportDismissed:
+		return status
+	default:
+		return services.ReportOpen
+	}
+}
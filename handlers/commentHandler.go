@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// commentHandler maneja los comentarios del detalle de una encuesta. Cada cambio se
+// anuncia por SSE para que los demás visitantes recarguen la sección.
+type commentHandler struct {
+	service *services.CommentService
+	sse     *services.SSEBroker
+}
+
+// NewCommentHandler inyecta CommentService y el broker SSE
+func NewCommentHandler(service *services.CommentService, sse *services.SSEBroker) *commentHandler {
+	return &commentHandler{service: service, sse: sse}
+}
+
+func (h *commentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+
+	var userID *int32
+	if val := r.Context().Value(middleware.UserIDKey); val != nil {
+		uid := val.(int32)
+		userID = &uid
+	}
+	h.respondThread(w, r, pollID, userID)
+}
+
+func (h *commentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+	parentID, err := optionalInt32(r.FormValue("parent_id"))
+	if err != nil {
+		respondError(w, r, services.InvalidField("parent_id", "comments.invalid_parent"))
+		return
+	}
+
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if _, err := h.service.CreateComment(r.Context(), pollID, userID, parentID, r.FormValue("body")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.publishUpdate(r.Context(), pollID)
+	h.respondThread(w, r, pollID, &userID)
+}
+
+func (h *commentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	commentID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "comments.invalid_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	pollID, err := h.service.UpdateComment(r.Context(), commentID, userID, r.FormValue("body"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.publishUpdate(r.Context(), pollID)
+	h.respondThread(w, r, pollID, &userID)
+}
+
+func (h *commentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	commentID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "comments.invalid_id"))
+		return
+	}
+
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	pollID, err := h.service.DeleteComment(r.Context(), commentID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.publishUpdate(r.Context(), pollID)
+	h.respondThread(w, r, pollID, &userID)
+}
+
+// SetCommentsMode abre, bloquea o desactiva los comentarios (solo el dueño).
+func (h *commentHandler) SetCommentsMode(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.service.SetCommentsMode(r.Context(), pollID, userID, r.FormValue("mode")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.publishUpdate(r.Context(), pollID)
+	h.respondThread(w, r, pollID, &userID)
+}
+
+// respondThread responde con la sección de comentarios actualizada (HTMX) o con el árbol en JSON.
+func (h *commentHandler) respondThread(w http.ResponseWriter, r *http.Request, pollID int32, userID *int32) {
+	thread, err := h.service.GetThread(r.Context(), pollID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		if err := views.CommentsSection(thread, userID != nil).Render(r.Context(), w); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, thread, "")
+}
+
+// publishUpdate avisa por SSE que cambiaron los comentarios. El evento no lleva datos: cada
+// cliente pide la sección de nuevo y la recibe con sus propios permisos.
+func (h *commentHandler) publishUpdate(ctx context.Context, pollID int32) {
+	h.sse.Publish(ctx, fmt.Sprintf("comments_update_%d", pollID), []byte("{}"))
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// Tamaño por defecto del iframe que devuelve oEmbed.
+const (
+	embedWidth         = 480
+	embedHeight        = 420
+	embedCompactHeight = 300
+)
+
+// embedPollPath reconoce las URLs de encuestas que acepta oEmbed.
+var embedPollPath = regexp.MustCompile(`^(?:/embed)?/polls/(\d+)/?$`)
+
+// embedHandler sirve la versión embebible de las encuestas, su descubrimiento oEmbed y
+// la configuración de los sitios que pueden insertarlas.
+type embedHandler struct {
+	service *services.PollService
+	baseURL string
+}
+
+// NewEmbedHandler inyecta PollService y la URL pública con la que se arman los iframes
+func NewEmbedHandler(service *services.PollService, baseURL string) *embedHandler {
+	return &embedHandler{service: service, baseURL: baseURL}
+}
+
+// Embed dibuja la encuesta sin navegación para un iframe. Siempre se muestra como a un
+// visitante anónimo: para votar se abre la encuesta en webpolls.
+func (h *embedHandler) Embed(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, i18n.T(r.Context(), "error.invalid_poll_id"), http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.service.GetPollByID(r.Context(), id, nil)
+	if err != nil {
+		if errors.Is(err, services.ErrPollNotFound) {
+			http.Error(w, i18n.T(r.Context(), "error.poll_not_found"), http.StatusNotFound)
+		} else {
+			slog.ErrorContext(r.Context(), "error getting poll for embed", "poll_id", id, "error", err)
+			http.Error(w, i18n.T(r.Context(), "error.load_poll"), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	query := r.URL.Query()
+	opts := views.PollViewOptions{
+		HideTitle: queryFlag(query.Get("hide_title")),
+		Compact:   queryFlag(query.Get("compact")),
+		Embed:     true,
+	}
+	theme := views.EmbedThemeDark
+	if query.Get("theme") == views.EmbedThemeLight {
+		theme = views.EmbedThemeLight
+	}
+
+	w.Header().Set("Content-Security-Policy", services.FrameAncestors(poll.EmbedOrigins))
+
+	if r.Header.Get("HX-Request") == "true" {
+		views.PollDetailContentWithOptions(poll, false, opts).Render(r.Context(), w)
+		return
+	}
+
+	refreshURL := embedURL("", poll.ID, theme, opts)
+	if err := views.PollEmbed(poll, opts, theme, refreshURL).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// oEmbedResponse es la respuesta de tipo rich de la especificación oEmbed 1.0.
+type oEmbedResponse struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	Title        string `json:"title"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// OEmbed resuelve la URL de una encuesta al iframe que la inserta.
+func (h *embedHandler) OEmbed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "json" {
+		http.Error(w, i18n.T(r.Context(), "embed.json_only"), http.StatusNotImplemented)
+		return
+	}
+
+	target, err := url.Parse(query.Get("url"))
+	if err != nil || !strings.HasPrefix(target.String(), h.baseURL+"/") {
+		http.Error(w, i18n.T(r.Context(), "embed.unknown_url"), http.StatusNotFound)
+		return
+	}
+	match := embedPollPath.FindStringSubmatch(target.Path)
+	if match == nil {
+		http.Error(w, i18n.T(r.Context(), "embed.unknown_url"), http.StatusNotFound)
+		return
+	}
+	id, err := utils.ConvertTo32(match[1])
+	if err != nil {
+		http.Error(w, i18n.T(r.Context(), "embed.unknown_url"), http.StatusNotFound)
+		return
+	}
+
+	poll, err := h.service.GetPollByID(r.Context(), id, nil)
+	if err != nil {
+		if errors.Is(err, services.ErrPollNotFound) {
+			http.Error(w, i18n.T(r.Context(), "error.poll_not_found"), http.StatusNotFound)
+		} else {
+			slog.ErrorContext(r.Context(), "error getting poll for oembed", "poll_id", id, "error", err)
+			http.Error(w, i18n.T(r.Context(), "error.load_poll"), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Las opciones de la URL pedida pasan al iframe
+	targetQuery := target.Query()
+	opts := views.PollViewOptions{
+		HideTitle: queryFlag(targetQuery.Get("hide_title")),
+		Compact:   queryFlag(targetQuery.Get("compact")),
+	}
+	theme := views.EmbedThemeDark
+	if targetQuery.Get("theme") == views.EmbedThemeLight {
+		theme = views.EmbedThemeLight
+	}
+
+	width, height := embedWidth, embedHeight
+	if opts.Compact {
+		height = embedCompactHeight
+	}
+	if max, err := strconv.Atoi(query.Get("maxwidth")); err == nil && max > 0 && max < width {
+		width = max
+	}
+	if max, err := strconv.Atoi(query.Get("maxheight")); err == nil && max > 0 && max < height {
+		height = max
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(oEmbedResponse{
+		Version:      "1.0",
+		Type:         "rich",
+		ProviderName: "webpolls",
+		ProviderURL:  h.baseURL,
+		Title:        poll.Title,
+		HTML:         embedIframe(embedURL(h.baseURL, poll.ID, theme, opts), poll.Title, width, height),
+		Width:        width,
+		Height:       height,
+	})
+}
+
+// GetSettings devuelve el panel del dueño con el código para insertar la encuesta.
+func (h *embedHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+
+	poll, err := h.service.GetPollByID(r.Context(), id, &userID)
+	if err != nil || !poll.IsOwner {
+		respondToastError(w, r, http.StatusNotFound, i18n.T(r.Context(), "error.poll_not_found"))
+		return
+	}
+
+	h.renderSettings(w, r, poll.ID, poll.Title, poll.EmbedOrigins)
+}
+
+// UpdateOrigins guarda los sitios autorizados, uno por línea.
+func (h *embedHandler) UpdateOrigins(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	origins, err := h.service.SetEmbedOrigins(r.Context(), id, userID, strings.Fields(r.FormValue("origins")))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	poll, err := h.service.GetPollByID(r.Context(), id, &userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting poll", "poll_id", id, "error", err)
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_poll"))
+		return
+	}
+
+	h.renderSettings(w, r, poll.ID, poll.Title, origins)
+	components.Toast(i18n.T(r.Context(), "embed.origins_saved"), false).Render(r.Context(), w)
+}
+
+func (h *embedHandler) renderSettings(w http.ResponseWriter, r *http.Request, pollID int32, title string, origins []string) {
+	snippet := embedIframe(embedURL(h.baseURL, pollID, views.EmbedThemeDark, views.PollViewOptions{}), title, embedWidth, embedHeight)
+	views.PollEmbedSettings(pollID, snippet, origins).Render(r.Context(), w)
+}
+
+// embedURL arma la URL del iframe con las opciones que difieren de las por defecto.
+func embedURL(baseURL string, pollID int32, theme string, opts views.PollViewOptions) string {
+	params := url.Values{}
+	if theme == views.EmbedThemeLight {
+		params.Set("theme", theme)
+	}
+	if opts.HideTitle {
+		params.Set("hide_title", "1")
+	}
+	if opts.Compact {
+		params.Set("compact", "1")
+	}
+
+	u := fmt.Sprintf("%s/embed/polls/%d", baseURL, pollID)
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	return u
+}
+
+// oEmbedURL es el endpoint oEmbed de una encuesta, para anunciarlo en su página.
+func oEmbedURL(baseURL string, pollID int32) string {
+	return baseURL + "/oembed?format=json&url=" + url.QueryEscape(fmt.Sprintf("%s/polls/%d", baseURL, pollID))
+}
+
+func embedIframe(src, title string, width, height int) string {
+	return fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" style="border:0" loading="lazy"></iframe>`,
+		html.EscapeString(src), width, height, html.EscapeString(title))
+}
+
+// queryFlag interpreta los parámetros booleanos de la query (1, true, yes).
+func queryFlag(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+	"webpolls/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// readyTimeout limita lo que puede tardar el chequeo de la base en /readyz.
+const readyTimeout = 2 * time.Second
+
+// healthHandler responde las sondas de liveness y readiness.
+type healthHandler struct {
+	db       *pgxpool.Pool
+	draining atomic.Bool
+}
+
+// NewHealthHandler inyecta el pool de la base para el chequeo de readiness
+func NewHealthHandler(db *pgxpool.Pool) *healthHandler {
+	return &healthHandler{db: db}
+}
+
+// SetDraining hace que /readyz falle para que el balanceador deje de enviar tráfico durante el apagado.
+func (h *healthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Healthz indica que el proceso está vivo; no depende de la base.
+func (h *healthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	RespondWithData(w, http.StatusOK, map[string]string{"status": "ok"}, "")
+}
+
+// Readyz indica si la instancia puede recibir tráfico: no se está apagando, la base
+// responde y el schema está aplicado.
+func (h *healthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		RespondWithError(w, http.StatusServiceUnavailable, "el servidor se está apagando")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	// El detalle de los errores queda en el log: /readyz es público y no debe mostrar
+	// direcciones ni nombres de la base
+	if err := h.db.Ping(ctx); err != nil {
+		slog.WarnContext(ctx, "readiness: database unavailable", "error", err)
+		RespondWithError(w, http.StatusServiceUnavailable, "base de datos no disponible")
+		return
+	}
+	if err := db.CheckSchema(ctx, h.db); err != nil {
+		slog.WarnContext(ctx, "readiness: schema check failed", "error", err)
+		RespondWithError(w, http.StatusServiceUnavailable, "schema de la base incompleto")
+		return
+	}
+
+	RespondWithData(w, http.StatusOK, map[string]string{"status": "ready"}, "")
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"webpolls/i18n"
 	"webpolls/services"
 	"webpolls/utils"
 	"webpolls/views"
@@ -25,7 +26,7 @@ func (h *homeHandler) GetHome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := views.Layout(views.Home(), "Webpolls", utils.IsAuthenticated(r)).Render(r.Context(), w)
+	err := views.Layout(views.Home(), i18n.T(r.Context(), "title.home"), utils.IsAuthenticated(r)).Render(r.Context(), w)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// notificationHandler maneja la bandeja de notificaciones del usuario, el contador de la
+// campana y las preferencias de aviso.
+type notificationHandler struct {
+	service *services.NotificationService
+}
+
+// NewNotificationHandler inyecta NotificationService
+func NewNotificationHandler(service *services.NotificationService) *notificationHandler {
+	return &notificationHandler{service: service}
+}
+
+func (h *notificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	notifications, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	prefs, err := h.service.GetPreferences(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	page := views.Notifications(notifications, prefs, h.service.DigestEnabled())
+	if r.Header.Get("HX-Request") == "true" {
+		if err := page.Render(r.Context(), w); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(page, i18n.T(r.Context(), "title.notifications"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// GetUnreadCount dibuja el contador de la campana; se pide al cargar y con cada evento SSE.
+func (h *notificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	count, err := h.service.UnreadCount(r.Context(), userID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "error counting unread notifications", "user_id", userID, "error", err)
+		count = 0
+	}
+
+	if r.Header.Get("HX-Request") != "true" {
+		RespondWithData(w, http.StatusOK, map[string]int64{"unread": count}, "")
+		return
+	}
+	components.NotificationCount(count).Render(r.Context(), w)
+}
+
+// MarkRead marca la notificación como leída y lleva a su encuesta.
+func (h *notificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "notification.invalid_id"))
+		return
+	}
+
+	pollID, err := h.service.MarkRead(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", fmt.Sprintf("/polls/%d", pollID))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, map[string]int32{"poll_id": pollID}, "")
+}
+
+func (h *notificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.service.MarkAllRead(r.Context(), userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	notifications, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	views.NotificationList(notifications).Render(r.Context(), w)
+}
+
+func (h *notificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	// Los checkbox desmarcados no se envían, así que la ausencia es false
+	prefs := services.NotificationPreferences{
+		OnVote:      r.FormValue("on_vote") != "",
+		OnClose:     r.FormValue("on_close") != "",
+		OnComment:   r.FormValue("on_comment") != "",
+		EmailDigest: r.FormValue("email_digest") != "",
+	}
+	if err := h.service.SetPreferences(r.Context(), userID, prefs); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Reswap", "none")
+	components.Toast(i18n.T(r.Context(), "notification.preferences_saved"), false).Render(r.Context(), w)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// orgHandler maneja las organizaciones, sus miembros y la página con sus encuestas.
+type orgHandler struct {
+	orgs  *services.OrganizationService
+	polls *services.PollService
+}
+
+// NewOrgHandler inyecta OrganizationService y PollService (para las encuestas de cada organización)
+func NewOrgHandler(orgs *services.OrganizationService, polls *services.PollService) *orgHandler {
+	return &orgHandler{orgs: orgs, polls: polls}
+}
+
+func (h *orgHandler) GetOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	orgs, err := h.orgs.ListOrganizations(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.Organizations(orgs).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.Organizations(orgs), i18n.T(r.Context(), "title.orgs"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *orgHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	org, err := h.orgs.CreateOrganization(r.Context(), userID, r.FormValue("name"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	message := i18n.T(r.Context(), "org.created", org.Name)
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		orgs, err := h.orgs.ListOrganizations(r.Context(), userID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		views.OrganizationList(orgs).Render(r.Context(), w)
+		components.Toast(message, false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusCreated, org, message)
+}
+
+// GetOrganization muestra los miembros y las encuestas de la organización (solo para miembros).
+func (h *orgHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "org.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	org, members, err := h.orgs.GetOrganization(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	polls, err := h.polls.GetPollsByOrg(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.OrganizationDetail(org, members, polls, userID).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.OrganizationDetail(org, members, polls, userID), i18n.T(r.Context(), "title.org", org.Name), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *orgHandler) RenameOrganization(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "org.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	org, err := h.orgs.RenameOrganization(r.Context(), id, userID, r.FormValue("name"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		views.OrganizationHeader(org).Render(r.Context(), w)
+		components.Toast(i18n.T(r.Context(), "org.renamed"), false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, org, i18n.T(r.Context(), "org.renamed"))
+}
+
+func (h *orgHandler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "org.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.orgs.DeleteOrganization(r.Context(), id, userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/orgs")
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, nil, i18n.T(r.Context(), "org.deleted"))
+}
+
+// SetMember agrega un miembro por nombre de usuario o le cambia el rol.
+func (h *orgHandler) SetMember(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "org.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.orgs.SetMember(r.Context(), id, userID, r.FormValue("username"), r.FormValue("role")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.renderMembers(w, r, id, userID, i18n.T(r.Context(), "org.member_saved"))
+}
+
+// RemoveMember saca a un miembro; si es quien pide, se va de la organización.
+func (h *orgHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "org.invalid_id"))
+		return
+	}
+	memberID, err := utils.ConvertTo32(r.PathValue("user_id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "org.member_not_found"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.orgs.RemoveMember(r.Context(), id, userID, memberID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if memberID == userID {
+		//WEB
+		if r.Header.Get("HX-Request") == "true" {
+			w.Header().Set("HX-Redirect", "/orgs")
+			return
+		}
+
+		//API
+		RespondWithData(w, http.StatusOK, nil, i18n.T(r.Context(), "org.left"))
+		return
+	}
+	h.renderMembers(w, r, id, userID, i18n.T(r.Context(), "org.member_removed"))
+}
+
+// renderMembers dibuja la lista de miembros (HTMX) o la devuelve en JSON, con el aviso message.
+func (h *orgHandler) renderMembers(w http.ResponseWriter, r *http.Request, orgID, userID int32, message string) {
+	// Se vuelve a leer el rol de quien pide: un dueño puede haberse cambiado el suyo
+	org, members, err := h.orgs.GetOrganization(r.Context(), orgID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		views.MemberList(org, members, userID).Render(r.Context(), w)
+		components.Toast(message, false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, members, message)
+}
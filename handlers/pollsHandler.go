@@ -1,23 +1,28 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 	"webpolls/components"
+	"webpolls/i18n"
 	"webpolls/middleware"
 	"webpolls/services"
 	"webpolls/utils"
 	"webpolls/views"
-
-	"github.com/jackc/pgx/v5"
 )
 
 // PollHandler ahora depende de PollService
 type PollHandler struct {
 	service *services.PollService
 	sse     *services.SSEBroker
+	// BaseURL es la URL pública del sitio; con ella se anuncia el endpoint oEmbed de cada encuesta
+	BaseURL string
 }
 
 // NewPollHandler ahora inyecta PollService y SSEBroker
@@ -26,52 +31,132 @@ func NewPollHandler(service *services.PollService, sse *services.SSEBroker) *Pol
 }
 
 func (h *PollHandler) CreatePoll(w http.ResponseWriter, r *http.Request) {
-	var req services.PollRequest
-
-	if err := r.ParseForm(); err != nil {
-		w.Header().Set("HX-Reswap", "none")
-		w.WriteHeader(http.StatusBadRequest)
-		components.Toast("Cuerpo forma invalido", true).Render(r.Context(), w)
-		return
-	}
-	var options []services.OptionRequest
-	formOptions := r.Form["options"]
-	for _, opt := range formOptions {
-		options = append(options, services.OptionRequest{Content: opt})
-	}
 	userId := r.Context().Value(middleware.UserIDKey).(int32)
 
-	req = services.PollRequest{
-		Question: r.FormValue("question"),
-		UserID:   userId,
-		Options:  options,
+	req, err := pollRequestFromForm(r)
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.Message(r.Context(), err))
+		return
 	}
+	req.UserID = userId
 
-	_, err := h.service.CreatePoll(r.Context(), req)
+	created, err := h.service.CreatePoll(r.Context(), req)
 	if err != nil {
-		w.Header().Set("HX-Reswap", "none")
-		w.WriteHeader(http.StatusBadRequest)
-		components.Toast(err.Error(), true).Render(r.Context(), w)
+		respondError(w, r, err)
 		return
 	}
 
 	//se llama a esto para traer todas las polls y mandarlas al renderizado
 	polls, err := h.service.GetPollsByUser(r.Context(), userId, userId)
 	if err != nil {
-		w.Header().Set("HX-Reswap", "none")
-		w.WriteHeader(http.StatusInternalServerError)
-		components.Toast(err.Error(), true).Render(r.Context(), w)
+		respondError(w, r, err)
 		return
 	}
 
 	err = views.PollList(polls, true).Render(r.Context(), w)
 	if err != nil {
-		w.Header().Set("HX-Reswap", "none")
-		w.WriteHeader(http.StatusInternalServerError)
-		components.Toast(err.Error(), true).Render(r.Context(), w)
+		respondError(w, r, err)
 		return
 	}
-	components.Toast("Encuesta creada correctamente", false).Render(r.Context(), w)
+	components.Toast(publicationMessage(r.Context(), created.Status, created.PublishAt), false).Render(r.Context(), w)
+}
+
+// publicationMessage es el aviso que se muestra después de guardar o publicar una encuesta.
+func publicationMessage(ctx context.Context, status string, publishAt *time.Time) string {
+	switch status {
+	case services.PollDraft:
+		return i18n.T(ctx, "poll.draft_saved")
+	case services.PollScheduled:
+		return i18n.T(ctx, "poll.scheduled", i18n.DateTime(ctx, *publishAt))
+	default:
+		return i18n.T(ctx, "poll.created")
+	}
+}
+
+// pollRequestFromForm lee el formulario de creación de encuestas (también lo usan las
+// plantillas). Solo falla si un campo no se puede convertir; el resto lo valida el servicio.
+func pollRequestFromForm(r *http.Request) (services.PollRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return services.PollRequest{}, i18n.NewError("error.invalid_form")
+	}
+	var options []services.OptionRequest
+	formOptions := r.Form["options"]
+	for _, opt := range formOptions {
+		options = append(options, services.OptionRequest{Content: opt})
+	}
+
+	closesAt, err := formDateTime(r, "closes_at")
+	if err != nil {
+		return services.PollRequest{}, i18n.NewError("poll.invalid_closes_at")
+	}
+	publishAt, err := formDateTime(r, "publish_at")
+	if err != nil {
+		return services.PollRequest{}, i18n.NewError("poll.invalid_publish_at")
+	}
+
+	// Reglas de decisión: todos los campos son opcionales
+	rules := services.DecisionRules{TieBreak: r.FormValue("tie_break")}
+	var majority *int32
+	var ruleErrs []error
+	for name, dst := range map[string]**int32{
+		"quorum_votes":     &rules.QuorumVotes,
+		"invited_voters":   &rules.InvitedVoters,
+		"quorum_percent":   &rules.QuorumPercent,
+		"majority_percent": &majority,
+	} {
+		n, err := optionalInt32(r.FormValue(name))
+		ruleErrs = append(ruleErrs, err)
+		*dst = n
+	}
+	if errors.Join(ruleErrs...) != nil {
+		return services.PollRequest{}, i18n.NewError("poll.rules_not_integer")
+	}
+	if majority != nil {
+		rules.MajorityPercent = *majority
+	}
+	orgID, err := optionalInt32(r.FormValue("org_id"))
+	if err != nil {
+		return services.PollRequest{}, i18n.NewError("org.invalid_id")
+	}
+
+	return services.PollRequest{
+		Question:          r.FormValue("question"),
+		Options:           options,
+		ClosesAt:          closesAt,
+		ResultsVisibility: r.FormValue("results_visibility"),
+		Rules:             rules,
+		Tags:              strings.Split(r.FormValue("tags"), ","),
+		// Lo manda el botón "Guardar borrador" (name="draft")
+		Draft:       r.FormValue("draft") != "",
+		PublishAt:   publishAt,
+		OrgID:       orgID,
+		MembersOnly: r.FormValue("members_only") != "",
+	}, nil
+}
+
+// formDateTime lee un campo opcional de un input datetime-local, que llega sin zona horaria.
+func formDateTime(r *http.Request, name string) (*time.Time, error) {
+	value := r.FormValue(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// optionalInt32 convierte un campo numérico opcional del formulario; vacío es nil.
+func optionalInt32(value string) (*int32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	n, err := utils.ConvertTo32(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
 }
 
 func (h *PollHandler) GetMyPolls(w http.ResponseWriter, r *http.Request) {
@@ -79,13 +164,23 @@ func (h *PollHandler) GetMyPolls(w http.ResponseWriter, r *http.Request) {
 
 	polls, err := h.service.GetPollsByUser(r.Context(), userId, userId)
 	if err != nil {
-		log.Printf("Error getting user polls: %v", err)
-		RespondWithError(w, http.StatusInternalServerError, "No se pudieron obtener las encuestas")
+		slog.ErrorContext(r.Context(), "error getting user polls", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_polls"))
+		return
+	}
+	templates, err := h.service.ListTemplates(r.Context(), userId)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	orgs, err := h.service.EditableOrgs(r.Context(), userId)
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
 	if r.Header.Get("HX-Request") == "true" {
-		err := views.MyPolls(polls).Render(r.Context(), w)
+		err := views.MyPolls(polls, templates, orgs).Render(r.Context(), w)
 		if err != nil {
 			RespondWithError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -93,7 +188,7 @@ func (h *PollHandler) GetMyPolls(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = views.Layout(views.MyPolls(polls), "Mis Polls - Webpolls", utils.IsAuthenticated(r)).Render(r.Context(), w)
+	err = views.Layout(views.MyPolls(polls, templates, orgs), i18n.T(r.Context(), "title.my_polls"), utils.IsAuthenticated(r)).Render(r.Context(), w)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -104,13 +199,14 @@ func (h *PollHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := utils.ConvertTo32(idStr)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de poll invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
 		return
 	}
 
-	err = h.service.DeletePoll(r.Context(), id)
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+	err = h.service.DeletePoll(r.Context(), id, userID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, r, err)
 		return
 	}
 
@@ -121,14 +217,14 @@ func (h *PollHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//API
-	RespondWithData(w, http.StatusOK, nil, "Encuesta eliminada correctamente")
+	RespondWithData(w, http.StatusOK, nil, i18n.T(r.Context(), "poll.deleted"))
 }
 
 func (h *PollHandler) GetPollPage(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := utils.ConvertTo32(idStr)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de encuesta invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
 		return
 	}
 
@@ -140,12 +236,7 @@ func (h *PollHandler) GetPollPage(w http.ResponseWriter, r *http.Request) {
 
 	poll, err := h.service.GetPollByID(r.Context(), id, userID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			RespondWithError(w, http.StatusNotFound, "Encuesta no encontrada")
-		} else {
-			log.Println("DB error:", err)
-			RespondWithError(w, http.StatusInternalServerError, "Error al obtener encuesta")
-		}
+		respondError(w, r, err)
 		return
 	}
 
@@ -154,29 +245,230 @@ func (h *PollHandler) GetPollPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = views.Layout(views.PollDetail(poll, userID != nil), "Encuesta - Webpolls", userID != nil).Render(r.Context(), w)
+	head := views.OEmbedDiscovery(oEmbedURL(h.BaseURL, poll.ID), poll.Title)
+	err = views.LayoutWithHead(views.PollDetail(poll, userID != nil), head, i18n.T(r.Context(), "title.poll"), userID != nil).Render(r.Context(), w)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 }
 
+// ClosePoll cierra la votación antes de su fecha (solo el dueño) y anuncia el resultado.
+func (h *PollHandler) ClosePoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if _, err := h.service.ClosePoll(r.Context(), pollID, userID); err != nil {
+		slog.WarnContext(r.Context(), "error closing poll", "poll_id", pollID, "error", err)
+		respondError(w, r, err)
+		return
+	}
+	h.PublishPollUpdate(r.Context(), pollID)
+
+	poll, err := h.service.GetPollByID(r.Context(), pollID, &userID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_updated"))
+		return
+	}
+	views.PollDetailContent(poll, true).Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "poll.closed"), false).Render(r.Context(), w)
+}
+
+// EditDraft devuelve el formulario de edición de una encuesta que todavía no se publicó.
+func (h *PollHandler) EditDraft(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	draft, err := h.service.GetDraft(r.Context(), pollID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		orgs, err := h.service.EditableOrgs(r.Context(), userID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		views.DraftForm(pollID, *draft, orgs).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, draft, "")
+}
+
+// UpdateDraft guarda los cambios de un borrador y vuelve a dibujar la lista de encuestas.
+func (h *PollHandler) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	req, err := pollRequestFromForm(r)
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.Message(r.Context(), err))
+		return
+	}
+	req.UserID = userID
+
+	if err := h.service.UpdateDraft(r.Context(), pollID, userID, req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//API
+	if r.Header.Get("HX-Request") != "true" {
+		RespondWithData(w, http.StatusOK, nil, i18n.T(r.Context(), "poll.draft_saved"))
+		return
+	}
+
+	//WEB
+	polls, err := h.service.GetPollsByUser(r.Context(), userID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	views.PollList(polls, true).Render(r.Context(), w)
+	components.Toast(i18n.T(r.Context(), "poll.draft_saved"), false).Render(r.Context(), w)
+}
+
+// PublishPoll publica un borrador ahora o, si llega publish_at en el futuro, lo programa.
+func (h *PollHandler) PublishPoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+	publishAt, err := formDateTime(r, "publish_at")
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "poll.invalid_publish_at"))
+		return
+	}
+
+	status, err := h.service.PublishPoll(r.Context(), pollID, userID, publishAt)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	poll, err := h.service.GetPollByID(r.Context(), pollID, &userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	message := publicationMessage(r.Context(), status, poll.PublishAt)
+	if status == services.PollPublished {
+		message = i18n.T(r.Context(), "poll.published")
+	}
+
+	//API
+	if r.Header.Get("HX-Request") != "true" {
+		RespondWithData(w, http.StatusOK, poll, message)
+		return
+	}
+
+	//WEB
+	// Publicada cambia toda la página (votos, cierre), así que se recarga
+	if status == services.PollPublished {
+		w.Header().Set("HX-Refresh", "true")
+		return
+	}
+	views.PollPublishPanel(poll).Render(r.Context(), w)
+	components.Toast(message, false).Render(r.Context(), w)
+}
+
+// UnschedulePoll cancela la publicación programada; la encuesta vuelve a ser borrador.
+func (h *PollHandler) UnschedulePoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.service.UnschedulePoll(r.Context(), pollID, userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	poll, err := h.service.GetPollByID(r.Context(), pollID, &userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		views.PollPublishPanel(poll).Render(r.Context(), w)
+		components.Toast(i18n.T(r.Context(), "poll.unscheduled"), false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, poll, i18n.T(r.Context(), "poll.unscheduled"))
+}
+
+// PreviewPoll muestra la encuesta como la verá quien vote, antes de publicarla.
+func (h *PollHandler) PreviewPoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	poll, err := h.service.PreviewPoll(r.Context(), pollID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		views.PollPreview(poll).Render(r.Context(), w)
+		return
+	}
+
+	err = views.Layout(views.PollPreview(poll), i18n.T(r.Context(), "title.poll_preview"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	pollID, err := utils.ConvertTo32(idStr)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de encuesta invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
 		return
 	}
 
 	if err := r.ParseForm(); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Formulario inválido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
 		return
 	}
 	optionIDStr := r.FormValue("option_id")
 	optionID, err := utils.ConvertTo32(optionIDStr)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de opción inválido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_option_id"))
 		return
 	}
 
@@ -184,14 +476,14 @@ func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
 	if val := r.Context().Value(middleware.UserIDKey); val != nil {
 		userID = val.(int32)
 	} else {
-		RespondWithError(w, http.StatusUnauthorized, "Debes iniciar sesión para votar")
+		RespondWithError(w, http.StatusUnauthorized, i18n.T(r.Context(), "poll.login_required"))
 		return
 	}
 
 	err = h.service.Vote(r.Context(), pollID, optionID, userID)
 	if err != nil {
-		log.Printf("Error voting: %v", err)
-		RespondWithError(w, http.StatusInternalServerError, "Error al registrar voto")
+		slog.WarnContext(r.Context(), "error voting", "error", err)
+		respondError(w, r, err)
 		return
 	}
 
@@ -238,9 +530,22 @@ func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
 	// Client: hx-trigger="sse:poll_update_123"
 	//
 	// Broadcast update via SSE
-	// Fetch updated poll data for broadcast (generic, no user specific data needed for stats)
-	updatedPoll, err := h.service.GetPollByID(r.Context(), pollID, nil)
-	if err == nil {
+	h.PublishPollUpdate(r.Context(), pollID)
+
+	poll, err := h.service.GetPollByID(r.Context(), pollID, &userID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_updated"))
+		return
+	}
+
+	views.PollDetailContent(poll, true).Render(r.Context(), w)
+}
+
+// PublishPollUpdate avisa por SSE que la encuesta cambió (un voto o el cierre con su resultado).
+func (h *PollHandler) PublishPollUpdate(ctx context.Context, pollID int32) {
+	// Se arma como lo vería un anónimo: si la política oculta los resultados, el evento va sin conteos
+	updatedPoll, err := h.service.GetPollByID(ctx, pollID, nil)
+	if err == nil && updatedPoll.ResultsVisible {
 		// Create a simple struct for the payload
 		type PollStats struct {
 			TotalVotes int64 `json:"total_votes"`
@@ -249,10 +554,12 @@ func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
 				VoteCount  int64   `json:"vote_count"`
 				Percentage float64 `json:"percentage"`
 			} `json:"options"`
+			Outcome *services.PollOutcome `json:"outcome,omitempty"`
 		}
 
 		stats := PollStats{
 			TotalVotes: updatedPoll.TotalVotes,
+			Outcome:    updatedPoll.Outcome,
 		}
 		for _, opt := range updatedPoll.Options {
 			stats.Options = append(stats.Options, struct {
@@ -267,22 +574,20 @@ func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
 		}
 
 		jsonData, _ := json.Marshal(stats)
-		h.sse.Broadcast([]byte(fmt.Sprintf("event: poll_update_%d\ndata: %s\n\n", pollID, jsonData)))
+		h.sse.Publish(ctx, fmt.Sprintf("poll_update_%d", pollID), jsonData)
 	} else {
-		// Fallback if fetch fails
-		h.sse.Broadcast([]byte(fmt.Sprintf("event: poll_update_%d\ndata: {}\n\n", pollID)))
-	}
-
-	poll, err := h.service.GetPollByID(r.Context(), pollID, &userID)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, "Error al obtener datos actualizados")
-		return
+		// Fallback if fetch fails or results are hidden
+		h.sse.Publish(ctx, fmt.Sprintf("poll_update_%d", pollID), []byte("{}"))
 	}
-
-	views.PollDetailContent(poll, true).Render(r.Context(), w)
 }
 
+// SSE abre el stream de eventos. Con sesión iniciada también llegan los avisos del usuario
+// (por ejemplo, la cantidad de notificaciones sin leer).
 func (h *PollHandler) SSE(w http.ResponseWriter, r *http.Request) {
+	if userID, ok := r.Context().Value(middleware.UserIDKey).(int32); ok {
+		h.sse.ServeUser(w, r, userID)
+		return
+	}
 	h.sse.ServeHTTP(w, r)
 }
 
@@ -293,15 +598,23 @@ func (h *PollHandler) GetPolls(w http.ResponseWriter, r *http.Request) {
 		userID = val.(int32)
 	}
 
-	polls, err := h.service.GetPolls(r.Context(), userID)
+	query := r.URL.Query()
+	filter := services.PollFilter{
+		ViewerID: userID,
+		Tag:      services.NormalizeTag(query.Get("tag")),
+		Search:   query.Get("q"),
+		Status:   query.Get("status"),
+	}
+
+	polls, err := h.service.GetPolls(r.Context(), filter)
 	if err != nil {
-		log.Printf("Error getting polls: %v", err) // Agregar log
-		RespondWithError(w, http.StatusInternalServerError, "No se pudieron obtener las encuestas")
+		slog.ErrorContext(r.Context(), "error getting polls", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_polls"))
 		return
 	}
 
 	if r.Header.Get("HX-Request") == "true" {
-		err := views.Polls(polls).Render(r.Context(), w)
+		err := views.Polls(polls, filter).Render(r.Context(), w)
 		if err != nil {
 			RespondWithError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -309,7 +622,7 @@ func (h *PollHandler) GetPolls(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = views.Layout(views.Polls(polls), "Webpolls - Polls", utils.IsAuthenticated(r)).Render(r.Context(), w)
+	err = views.Layout(views.Polls(polls, filter), i18n.T(r.Context(), "title.polls"), utils.IsAuthenticated(r)).Render(r.Context(), w)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -320,29 +633,32 @@ func (h *PollHandler) UpdateOption(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := utils.ConvertTo32(idStr)
 	if err != nil {
-		log.Printf("Error converting option ID: %v", err)
-		RespondWithError(w, http.StatusBadRequest, "Id de opción inválido")
+		slog.WarnContext(r.Context(), "error converting option ID", "error", err)
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_option_id"))
 		return
 	}
 
 	var req services.OptionResponse
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Error decoding JSON: %v", err)
-		RespondWithError(w, http.StatusBadRequest, "JSON inválido")
+		slog.WarnContext(r.Context(), "error decoding JSON", "error", err)
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_json"))
 		return
 	}
 
-	log.Printf("Updating option %d with content=%v", id, req.Content)
+	slog.DebugContext(r.Context(), "updating option", "option_id", id)
 
-	data, err := h.service.UpdateOption(r.Context(), req)
+	// El id de la ruta manda sobre el del cuerpo
+	req.ID = id
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+	data, err := h.service.UpdateOption(r.Context(), req, userID)
 	if err != nil {
-		log.Printf("Error updating option: %v", err)
-		RespondWithError(w, http.StatusInternalServerError, "Error al actualizar opción")
+		slog.WarnContext(r.Context(), "error updating option", "error", err)
+		respondError(w, r, err)
 		return
 	}
 
-	log.Printf("Option %d updated successfully to content=%v", id, req.Content)
-	RespondWithData(w, http.StatusOK, data, "Opción actualizada correctamente")
+	slog.DebugContext(r.Context(), "option updated", "option_id", id)
+	RespondWithData(w, http.StatusOK, data, i18n.T(r.Context(), "poll.option_updated"))
 }
 
 func (h *PollHandler) DeleteOption(w http.ResponseWriter, r *http.Request) {
@@ -350,26 +666,27 @@ func (h *PollHandler) DeleteOption(w http.ResponseWriter, r *http.Request) {
 	poll_idStr := r.PathValue("poll_id")
 	id, err := utils.ConvertTo32(idStr)
 	if err != nil {
-		log.Printf("Error converting option ID: %v", err)
-		RespondWithError(w, http.StatusBadRequest, "Id de opción inválido")
+		slog.WarnContext(r.Context(), "error converting option ID", "error", err)
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_option_id"))
 		return
 	}
 	poll_id, err := utils.ConvertTo32(poll_idStr)
 	if err != nil {
-		log.Printf("Error converting poll ID: %v", err)
-		RespondWithError(w, http.StatusBadRequest, "Id de encuesta inválido")
+		slog.WarnContext(r.Context(), "error converting poll ID", "error", err)
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
 		return
 	}
 
-	err = h.service.DeleteOption(r.Context(), id, poll_id)
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+	err = h.service.DeleteOption(r.Context(), id, poll_id, userID)
 	if err != nil {
-		log.Printf("Error deleting option: %v", err)
-		RespondWithError(w, http.StatusInternalServerError, "Error al eliminar opción")
+		slog.WarnContext(r.Context(), "error deleting option", "error", err)
+		respondError(w, r, err)
 		return
 	}
 
-	log.Printf("Option %d deleted successfully", id)
-	RespondWithData(w, http.StatusOK, nil, "Opción eliminada correctamente")
+	slog.DebugContext(r.Context(), "option deleted", "option_id", id)
+	RespondWithData(w, http.StatusOK, nil, i18n.T(r.Context(), "poll.option_deleted"))
 }
 
 func (h *PollHandler) GetPollOptionInput(w http.ResponseWriter, r *http.Request) {
@@ -379,9 +696,9 @@ func (h *PollHandler) GetPollOptionInput(w http.ResponseWriter, r *http.Request)
 	if count >= 4 {
 		w.Header().Set("HX-Reswap", "none")
 		w.WriteHeader(http.StatusBadRequest)
-		components.Toast("Máximo 4 opciones permitidas", true).Render(r.Context(), w)
+		components.Toast(i18n.T(r.Context(), "poll.max_options_toast"), true).Render(r.Context(), w)
 		return
 	}
 
-	views.PollOptionInput().Render(r.Context(), w)
+	views.PollOptionInput("").Render(r.Context(), w)
 }
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+)
+
+// reportHandler recibe los reportes de contenido de los usuarios.
+type reportHandler struct {
+	service *services.ReportService
+}
+
+// NewReportHandler inyecta ReportService
+func NewReportHandler(service *services.ReportService) *reportHandler {
+	return &reportHandler{service: service}
+}
+
+func (h *reportHandler) ReportPoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	hidden, err := h.service.ReportPoll(r.Context(), pollID, userID, r.FormValue("reason"), r.FormValue("details"))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if hidden {
+		slog.InfoContext(r.Context(), "poll auto-hidden after reports", "poll_id", pollID)
+	}
+
+	// El formulario se cierra en el cliente (ver PollReportForm), solo mostramos el toast
+	w.Header().Set("HX-Reswap", "none")
+	components.Toast(i18n.T(r.Context(), "report.thanks"), false).Render(r.Context(), w)
+}
@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"webpolls/i18n"
+	"webpolls/services"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // ApiResponse es la estructura estándar para todas las respuestas de la API.
@@ -11,6 +16,8 @@ type ApiResponse struct {
 	Data    interface{} `json:"data"`
 	Error   string      `json:"error,omitempty"`
 	Message string      `json:"message,omitempty"`
+	// Fields trae el detalle de las validaciones: campo -> mensaje
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // RespondWithError envía una respuesta de error JSON estandarizada.
@@ -21,7 +28,68 @@ func RespondWithError(w http.ResponseWriter, code int, message string) {
 	payload := ApiResponse{Error: message, Data: nil}
 	err := json.NewEncoder(w).Encode(payload)
 	if err != nil {
-		log.Printf("Error al codificar respuesta JSON: %v", err)
+		slog.Error("error al codificar respuesta JSON", "error", err)
+	}
+}
+
+// respondError es el único lugar donde un error de servicio se convierte en respuesta:
+// el tipo del error de dominio decide el status, y según quién pregunte se responde con
+// un toast (HTMX) o con el envelope JSON. Los errores sin tipo se registran y se ocultan
+// detrás de un 500 genérico.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	message := i18n.T(r.Context(), "error.internal")
+	var fields map[string]string
+
+	var domain *services.DomainError
+	switch {
+	case errors.As(err, &domain):
+		status = statusForKind(domain.Kind)
+		message = i18n.Message(r.Context(), domain)
+		if len(domain.Fields) > 0 {
+			fields = make(map[string]string, len(domain.Fields))
+			for _, field := range domain.Fields {
+				// Si un campo tiene varios problemas se informa el primero
+				if _, ok := fields[field.Field]; !ok {
+					fields[field.Field] = i18n.Message(r.Context(), field.Err)
+				}
+			}
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		status = http.StatusNotFound
+		message = i18n.T(r.Context(), "error.not_found")
+	default:
+		slog.ErrorContext(r.Context(), "unexpected service error", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		respondToastError(w, r, status, message)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	payload := ApiResponse{Error: message, Fields: fields, Data: nil}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		slog.Error("error al codificar respuesta JSON", "error", err)
+	}
+}
+
+// statusForKind traduce el tipo de error de dominio al status HTTP.
+func statusForKind(kind services.ErrorKind) int {
+	switch kind {
+	case services.KindNotFound:
+		return http.StatusNotFound
+	case services.KindForbidden:
+		return http.StatusForbidden
+	case services.KindConflict:
+		return http.StatusConflict
+	case services.KindUnauthorized:
+		return http.StatusUnauthorized
+	case services.KindValidation:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
 	}
 }
 
@@ -33,6 +101,6 @@ func RespondWithData(w http.ResponseWriter, code int, dataPayload interface{}, m
 	payload := ApiResponse{Data: dataPayload, Message: message}
 	err := json.NewEncoder(w).Encode(payload)
 	if err != nil {
-		log.Printf("Error al codificar respuesta JSON: %v", err)
+		slog.Error("error al codificar respuesta JSON", "error", err)
 	}
 }
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webpolls/i18n"
+	"webpolls/services"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		locale  string
+		status  int
+		message string
+		fields  map[string]string
+	}{
+		{"no encontrado", services.ErrPollNotFound, i18n.EN, http.StatusNotFound, "poll not found", nil},
+		{"envuelto", fmt.Errorf("votando: %w", services.Forbidden("poll.not_owner")), i18n.ES, http.StatusForbidden, i18n.Translate(i18n.ES, "poll.not_owner"), nil},
+		{"conflicto", services.Conflict("poll.voting_closed"), i18n.EN, http.StatusConflict, i18n.Translate(i18n.EN, "poll.voting_closed"), nil},
+		{"no autorizado", services.Unauthorized("twofactor.invalid_code"), i18n.EN, http.StatusUnauthorized, i18n.Translate(i18n.EN, "twofactor.invalid_code"), nil},
+		{
+			"validación con campos", services.InvalidField("question", "poll.question_required"), i18n.EN, http.StatusBadRequest,
+			"the question can't be empty", map[string]string{"question": "the question can't be empty"},
+		},
+		{"sin filas", pgx.ErrNoRows, i18n.EN, http.StatusNotFound, "Not found", nil},
+		// Los errores sin tipo no se muestran: pueden traer detalles internos
+		{"error interno", errors.New("dial tcp 10.0.0.5:5432: connection refused"), i18n.EN, http.StatusInternalServerError, "Internal server error", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/polls/1/vote", nil)
+			r = r.WithContext(i18n.WithLocale(r.Context(), tt.locale))
+			rec := httptest.NewRecorder()
+			respondError(rec, r, tt.err)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			var body ApiResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("respuesta no es JSON: %v (%s)", err, rec.Body)
+			}
+			if body.Error != tt.message {
+				t.Errorf("error = %q, want %q", body.Error, tt.message)
+			}
+			if len(body.Fields) != len(tt.fields) {
+				t.Errorf("fields = %v, want %v", body.Fields, tt.fields)
+			}
+			for field, message := range tt.fields {
+				if body.Fields[field] != message {
+					t.Errorf("fields[%s] = %q, want %q", field, body.Fields[field], message)
+				}
+			}
+		})
+	}
+}
+
+// Desde HTMX el error se muestra como toast y no reemplaza el contenido.
+func TestRespondErrorHTMX(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/polls/1/vote", nil)
+	r.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+	respondError(rec, r, services.Conflict("poll.voting_closed"))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec.Header().Get("HX-Reswap") != "none" {
+		t.Errorf("HX-Reswap = %q, want none", rec.Header().Get("HX-Reswap"))
+	}
+	if !strings.Contains(rec.Body.String(), i18n.Translate(i18n.Default, "poll.voting_closed")) {
+		t.Errorf("el toast no tiene el mensaje: %s", rec.Body)
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// seriesHandler maneja las series de encuestas recurrentes.
+type seriesHandler struct {
+	service *services.PollService
+}
+
+// NewSeriesHandler inyecta PollService
+func NewSeriesHandler(service *services.PollService) *seriesHandler {
+	return &seriesHandler{service: service}
+}
+
+func (h *seriesHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	series, err := h.service.ListSeries(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	// Las series se crean a partir de una plantilla
+	templates, err := h.service.ListTemplates(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.Series(series, templates).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.Series(series, templates), i18n.T(r.Context(), "title.series"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *seriesHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+	templateID, err := utils.ConvertTo32(r.FormValue("template_id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "template.invalid_id"))
+		return
+	}
+	rule := services.Recurrence{
+		Frequency: r.FormValue("frequency"),
+		Cron:      r.FormValue("cron"),
+	}
+	for _, value := range r.Form["weekdays"] {
+		day, err := utils.ConvertTo32(value)
+		if err != nil {
+			respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "series.invalid_weekday"))
+			return
+		}
+		rule.Weekdays = append(rule.Weekdays, day)
+	}
+	startsAt, err := formDateTime(r, "starts_at")
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "series.invalid_starts_at"))
+		return
+	}
+	if startsAt != nil {
+		rule.StartsAt = *startsAt
+	}
+
+	series, err := h.service.CreateSeries(r.Context(), userID, templateID, rule)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.renderList(w, r, userID, i18n.T(r.Context(), "series.created", i18n.DateTime(r.Context(), series.NextRunAt)))
+}
+
+// GetSeriesPage muestra todas las encuestas de la serie y cómo cambian sus resultados.
+func (h *seriesHandler) GetSeriesPage(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "series.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	report, err := h.service.GetSeriesReport(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.SeriesDetail(report).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.SeriesDetail(report), i18n.T(r.Context(), "title.series_detail", report.Series.Name), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *seriesHandler) PauseSeries(w http.ResponseWriter, r *http.Request) {
+	h.setActive(w, r, false, "series.paused")
+}
+
+func (h *seriesHandler) ResumeSeries(w http.ResponseWriter, r *http.Request) {
+	h.setActive(w, r, true, "series.resumed")
+}
+
+func (h *seriesHandler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "series.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.service.DeleteSeries(r.Context(), id, userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.renderList(w, r, userID, i18n.T(r.Context(), "series.deleted"))
+}
+
+func (h *seriesHandler) setActive(w http.ResponseWriter, r *http.Request, active bool, message string) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "series.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if _, err := h.service.SetSeriesActive(r.Context(), id, userID, active); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.renderList(w, r, userID, i18n.T(r.Context(), message))
+}
+
+// renderList dibuja la lista de series (HTMX) o la devuelve en JSON, con el aviso message.
+func (h *seriesHandler) renderList(w http.ResponseWriter, r *http.Request, userID int32, message string) {
+	series, err := h.service.ListSeries(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		views.SeriesList(series).Render(r.Context(), w)
+		components.Toast(message, false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, series, message)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// maxSlackBody limita el cuerpo de las peticiones de Slack antes de verificar la firma.
+const maxSlackBody = 64 << 10
+
+// slackHandler recibe el slash command y las interacciones de Slack, y la página para
+// vincular un usuario de Slack con la cuenta de webpolls.
+type slackHandler struct {
+	service *services.SlackService
+	// client responde a las interacciones en su response_url
+	client *http.Client
+}
+
+// NewSlackHandler inyecta SlackService
+func NewSlackHandler(service *services.SlackService) *slackHandler {
+	return &slackHandler{service: service, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// VerifySignature rechaza las peticiones que no vienen firmadas por Slack. Deja el cuerpo
+// intacto para que el handler lo vuelva a leer.
+func (h *slackHandler) VerifySignature(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackBody))
+		if err != nil {
+			http.Error(w, "cuerpo inválido", http.StatusBadRequest)
+			return
+		}
+
+		err = h.service.VerifySignature(r.Header.Get(services.SlackTimestampHeader), r.Header.Get(services.SlackSignatureHeader), body)
+		if err != nil {
+			slog.WarnContext(r.Context(), "rejected slack request", "error", err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Command atiende el slash command. Slack publica la respuesta en el canal.
+func (h *slackHandler) Command(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "formulario inválido", http.StatusBadRequest)
+		return
+	}
+
+	msg, err := h.service.HandleCommand(r.Context(), services.SlashCommand{
+		Command:  r.PostFormValue("command"),
+		Text:     r.PostFormValue("text"),
+		TeamID:   r.PostFormValue("team_id"),
+		UserID:   r.PostFormValue("user_id"),
+		UserName: r.PostFormValue("user_name"),
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error handling slack command", "error", err)
+		// Sin cuenta resuelta no hay idioma del usuario: el de la petición es el de Slack
+		msg = &services.SlackMessage{ResponseType: "ephemeral", Text: i18n.Translate(i18n.Default, "slack.error")}
+	}
+	writeSlackMessage(w, msg)
+}
+
+// slackInteraction es la parte que usamos del payload de block_actions.
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID     string `json:"id"`
+		TeamID string `json:"team_id"`
+	} `json:"user"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// Interaction atiende los botones de voto. Slack espera un 200 rápido; el mensaje
+// actualizado se envía después a la response_url.
+func (h *slackHandler) Interaction(w http.ResponseWriter, r *http.Request) {
+	var payload slackInteraction
+	if err := json.Unmarshal([]byte(r.PostFormValue("payload")), &payload); err != nil {
+		http.Error(w, "payload inválido", http.StatusBadRequest)
+		return
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	teamID := payload.Team.ID
+	if teamID == "" {
+		teamID = payload.User.TeamID
+	}
+	msg, err := h.service.HandleAction(r.Context(), services.SlackAction{
+		TeamID:      teamID,
+		UserID:      payload.User.ID,
+		ActionID:    payload.Actions[0].ActionID,
+		Value:       payload.Actions[0].Value,
+		ResponseURL: payload.ResponseURL,
+	})
+	if err != nil {
+		slog.WarnContext(r.Context(), "error handling slack action", "error", err)
+		msg = &services.SlackMessage{ResponseType: "ephemeral", Text: i18n.Translate(i18n.Default, "slack.action_failed")}
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if !strings.HasPrefix(payload.ResponseURL, services.SlackResponseURLPrefix) {
+		slog.WarnContext(r.Context(), "ignoring slack response_url", "url", payload.ResponseURL)
+		return
+	}
+	go h.respond(context.WithoutCancel(r.Context()), payload.ResponseURL, msg)
+}
+
+// respond publica el mensaje en la response_url de la interacción.
+func (h *slackHandler) respond(ctx context.Context, responseURL string, msg *services.SlackMessage) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		slog.ErrorContext(ctx, "error encoding slack message", "error", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		slog.ErrorContext(ctx, "error building slack response", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		slog.WarnContext(ctx, "error posting slack response", "error", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		slog.WarnContext(ctx, "slack rejected response", "status", resp.StatusCode)
+	}
+}
+
+// GetLink muestra la confirmación para vincular el usuario de Slack del enlace.
+func (h *slackHandler) GetLink(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	teamID, externalUserID, err := h.service.ParseLinkToken(token)
+
+	content := views.SlackLink(token, teamID, externalUserID, err)
+	if err := views.Layout(content, i18n.T(r.Context(), "title.slack_link"), utils.IsAuthenticated(r)).Render(r.Context(), w); err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *slackHandler) PostLink(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	if err := h.service.LinkAccount(r.Context(), userID, r.FormValue("token")); err != nil {
+		slog.WarnContext(r.Context(), "error linking slack account", "error", err)
+		respondError(w, r, err)
+		return
+	}
+
+	views.SlackLinked().Render(r.Context(), w)
+}
+
+func writeSlackMessage(w http.ResponseWriter, msg *services.SlackMessage) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(msg)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+	"webpolls/services"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Las peticiones de testdata/slack están firmadas con este secreto y este timestamp, como
+// las mandaría Slack.
+const (
+	slackTestSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	slackTestTimestamp = "1531420618"
+)
+
+// slackTestDB responde a las consultas de SlackService sin base de datos: la cuenta
+// vinculada (o no) y ninguna encuesta.
+type slackTestDB struct {
+	linked bool
+	err    error
+}
+
+func (d *slackTestDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("escritura inesperada")
+}
+
+func (d *slackTestDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return &emptyRows{}, nil
+}
+
+func (d *slackTestDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return slackTestRow{d}
+}
+
+type slackTestRow struct{ db *slackTestDB }
+
+func (r slackTestRow) Scan(dest ...any) error {
+	switch {
+	case r.db.err != nil:
+		return r.db.err
+	case !r.db.linked:
+		return pgx.ErrNoRows
+	}
+	*dest[0].(*int32) = 7
+	return nil
+}
+
+type emptyRows struct{}
+
+func (*emptyRows) Close()                                       {}
+func (*emptyRows) Err() error                                   { return nil }
+func (*emptyRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (*emptyRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (*emptyRows) Next() bool                                   { return false }
+func (*emptyRows) Scan(...any) error                            { return nil }
+func (*emptyRows) Values() ([]any, error)                       { return nil, nil }
+func (*emptyRows) RawValues() [][]byte                          { return nil }
+func (*emptyRows) Conn() *pgx.Conn                              { return nil }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// newTestSlackHandler arma el handler con la hora fija en la de las peticiones grabadas. Los
+// mensajes que se mandarían a la response_url llegan por el canal.
+func newTestSlackHandler(t *testing.T, accounts *slackTestDB) (*slackHandler, <-chan services.SlackMessage) {
+	t.Helper()
+	queries := db.New(accounts)
+	service := services.NewSlackService(queries, services.NewPollService(queries, nil), slackTestSecret, "https://polls.example.com")
+	service.Now = func() time.Time { return time.Unix(1531420618, 0).Add(time.Minute) }
+
+	responses := make(chan services.SlackMessage, 1)
+	h := NewSlackHandler(service)
+	h.client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var msg services.SlackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("respuesta a Slack inválida: %v", err)
+		}
+		responses <- msg
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	})}
+	return h, responses
+}
+
+func slackRequest(t *testing.T, path, fixture, signature string) *http.Request {
+	t.Helper()
+	body, err := os.ReadFile("testdata/slack/" + fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set(services.SlackTimestampHeader, slackTestTimestamp)
+	r.Header.Set(services.SlackSignatureHeader, signature)
+	return r
+}
+
+func TestSlackCommand(t *testing.T) {
+	es := func(key string, args ...any) string { return i18n.Translate(i18n.Default, key, args...) }
+	tests := []struct {
+		name      string
+		fixture   string
+		signature string
+		db        *slackTestDB
+		status    int
+		text      string
+	}{
+		{
+			name:      "ayuda",
+			fixture:   "command_help.txt",
+			signature: "v0=ec54aa02fefc8c1934b41e295cc86085abdd06e2a4acfdb3451898b8953f0053",
+			db:        &slackTestDB{},
+			status:    http.StatusOK,
+			text:      es("slack.usage", "/poll"),
+		},
+		{
+			name:      "usuario sin vincular",
+			fixture:   "command.txt",
+			signature: "v0=ed6bcc091e51652799aa0b7fec01c8928b6ba2c7c720d8e11bfe6e6e8046ff0e",
+			db:        &slackTestDB{},
+			status:    http.StatusOK,
+			text:      es("slack.link_prompt"),
+		},
+		{
+			name:      "error de dominio traducido",
+			fixture:   "command_one_option.txt",
+			signature: "v0=c07b83156323cd10513551010152d985ffa863217969b753c8bcfecb7ba818f2",
+			db:        &slackTestDB{linked: true},
+			status:    http.StatusOK,
+			text:      es("slack.create_failed", es("poll.min_options")),
+		},
+		{
+			name:      "error interno genérico",
+			fixture:   "command.txt",
+			signature: "v0=ed6bcc091e51652799aa0b7fec01c8928b6ba2c7c720d8e11bfe6e6e8046ff0e",
+			db:        &slackTestDB{err: errors.New("conexión con 10.0.0.5 perdida")},
+			status:    http.StatusOK,
+			text:      es("slack.error"),
+		},
+		{
+			name:      "firma inválida",
+			fixture:   "command.txt",
+			signature: "v0=ec54aa02fefc8c1934b41e295cc86085abdd06e2a4acfdb3451898b8953f0053",
+			db:        &slackTestDB{},
+			status:    http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestSlackHandler(t, tt.db)
+			rec := httptest.NewRecorder()
+			h.VerifySignature(http.HandlerFunc(h.Command)).ServeHTTP(rec, slackRequest(t, "/integrations/slack/commands", tt.fixture, tt.signature))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var msg services.SlackMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &msg); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(msg.Text, tt.text) {
+				t.Errorf("text = %q, want %q", msg.Text, tt.text)
+			}
+			if msg.ResponseType != "ephemeral" {
+				t.Errorf("response_type = %q, want ephemeral", msg.ResponseType)
+			}
+		})
+	}
+}
+
+// El enlace de vinculación lleva el equipo y el usuario del comando, y vence según Now.
+func TestSlackCommandLinkPrompt(t *testing.T) {
+	h, _ := newTestSlackHandler(t, &slackTestDB{})
+	rec := httptest.NewRecorder()
+	h.VerifySignature(http.HandlerFunc(h.Command)).ServeHTTP(rec, slackRequest(t, "/integrations/slack/commands", "command.txt", "v0=ed6bcc091e51652799aa0b7fec01c8928b6ba2c7c720d8e11bfe6e6e8046ff0e"))
+
+	var msg services.SlackMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Blocks) != 2 || len(msg.Blocks[1].Elements) != 1 {
+		t.Fatalf("blocks = %+v, want texto y botón", msg.Blocks)
+	}
+	link, err := url.Parse(msg.Blocks[1].Elements[0].URL)
+	if err != nil || !strings.HasPrefix(link.String(), "https://polls.example.com/integrations/slack/link?") {
+		t.Fatalf("url = %q", msg.Blocks[1].Elements[0].URL)
+	}
+	teamID, userID, err := h.service.ParseLinkToken(link.Query().Get("token"))
+	if err != nil || teamID != "T0001" || userID != "U2147483697" {
+		t.Fatalf("ParseLinkToken = (%q, %q, %v), want T0001 y U2147483697", teamID, userID, err)
+	}
+
+	h.service.Now = func() time.Time { return time.Unix(1531420618, 0).Add(time.Hour) }
+	if _, _, err := h.service.ParseLinkToken(link.Query().Get("token")); err == nil {
+		t.Error("el enlace no venció")
+	}
+}
+
+func TestSlackRejectsStaleRequest(t *testing.T) {
+	h, _ := newTestSlackHandler(t, &slackTestDB{})
+	h.service.Now = func() time.Time { return time.Unix(1531420618, 0).Add(10 * time.Minute) }
+
+	rec := httptest.NewRecorder()
+	h.VerifySignature(http.HandlerFunc(h.Command)).ServeHTTP(rec, slackRequest(t, "/integrations/slack/commands", "command_help.txt", "v0=ec54aa02fefc8c1934b41e295cc86085abdd06e2a4acfdb3451898b8953f0053"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestSlackBlockActions(t *testing.T) {
+	es := func(key string, args ...any) string { return i18n.Translate(i18n.Default, key, args...) }
+	tests := []struct {
+		name      string
+		fixture   string
+		signature string
+		db        *slackTestDB
+		text      string
+	}{
+		{
+			name:      "usuario sin vincular",
+			fixture:   "block_actions_vote.txt",
+			signature: "v0=45a064c7c45e386da6e4a1f9dbb0be59b920c74372a6272299014da3c436092d",
+			db:        &slackTestDB{},
+			text:      es("slack.link_prompt"),
+		},
+		{
+			name:      "voto rechazado",
+			fixture:   "block_actions_vote.txt",
+			signature: "v0=45a064c7c45e386da6e4a1f9dbb0be59b920c74372a6272299014da3c436092d",
+			db:        &slackTestDB{linked: true},
+			text:      es("slack.vote_failed", es("poll.unavailable")),
+		},
+		{
+			name:      "acción desconocida",
+			fixture:   "block_actions_unknown.txt",
+			signature: "v0=de279058964c175bf87e2ca681b4d7825800ac39c78fc977d39ccfa2fb7537d4",
+			db:        &slackTestDB{linked: true},
+			text:      es("slack.action_failed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, responses := newTestSlackHandler(t, tt.db)
+			rec := httptest.NewRecorder()
+			h.VerifySignature(http.HandlerFunc(h.Interaction)).ServeHTTP(rec, slackRequest(t, "/integrations/slack/interactions", tt.fixture, tt.signature))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, http.StatusOK, rec.Body)
+			}
+
+			select {
+			case msg := <-responses:
+				if msg.Text != tt.text {
+					t.Errorf("text = %q, want %q", msg.Text, tt.text)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("no se respondió a la response_url")
+			}
+		})
+	}
+}
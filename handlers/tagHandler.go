@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"webpolls/i18n"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// tagHandler muestra la nube de etiquetas y el autocompletado del formulario de encuestas.
+type tagHandler struct {
+	service *services.PollService
+}
+
+// NewTagHandler inyecta PollService
+func NewTagHandler(service *services.PollService) *tagHandler {
+	return &tagHandler{service: service}
+}
+
+func (h *tagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.ListTags(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		if err := views.TagsPage(tags).Render(r.Context(), w); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.TagsPage(tags), i18n.T(r.Context(), "title.tags"), utils.IsAuthenticated(r)).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// SuggestTags completa la última etiqueta que se está escribiendo en el campo "tags".
+func (h *tagHandler) SuggestTags(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("tags")
+	prefix, partial := "", value
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		prefix, partial = value[:i+1]+" ", value[i+1:]
+	}
+
+	tags, err := h.service.SuggestTags(r.Context(), partial)
+	if err != nil {
+		slog.WarnContext(r.Context(), "error suggesting tags", "error", err)
+		tags = nil
+	}
+	views.TagSuggestions(prefix, tags).Render(r.Context(), w)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// templatesChangedEvent lo dispara la respuesta (HX-Trigger) cuando se guarda o borra una
+// plantilla, para que el selector del formulario se actualice.
+const templatesChangedEvent = "templatesChanged"
+
+// templateHandler maneja las plantillas de encuesta y el formulario de creación
+// precompletado (desde una plantilla o duplicando una encuesta).
+type templateHandler struct {
+	service *services.PollService
+}
+
+// NewTemplateHandler inyecta PollService
+func NewTemplateHandler(service *services.PollService) *templateHandler {
+	return &templateHandler{service: service}
+}
+
+// GetPollForm devuelve el formulario de creación, vacío o completado con ?template_id.
+func (h *templateHandler) GetPollForm(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	var draft services.PollRequest
+	var selected int32
+	if value := r.URL.Query().Get("template_id"); value != "" {
+		id, err := utils.ConvertTo32(value)
+		if err != nil {
+			respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "template.invalid_id"))
+			return
+		}
+		template, err := h.service.GetTemplate(r.Context(), id, userID)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		draft = *template.Draft(r.Context(), time.Now())
+		selected = id
+	}
+	h.respondForm(w, r, userID, draft, selected)
+}
+
+// DuplicatePoll devuelve el formulario con una copia de la encuesta para crear otra.
+func (h *templateHandler) DuplicatePoll(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	draft, err := h.service.DuplicateDraft(r.Context(), pollID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.respondForm(w, r, userID, *draft, 0)
+}
+
+func (h *templateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	templates, err := h.service.ListTemplates(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		selected, _ := utils.ConvertTo32(r.URL.Query().Get("selected"))
+		views.PollTemplatePicker(templates, selected).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, templates, "")
+}
+
+// CreateTemplate guarda el formulario de creación como plantilla. El título puede llevar
+// marcadores como {{date}}.
+func (h *templateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	req, err := pollRequestFromForm(r)
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.Message(r.Context(), err))
+		return
+	}
+
+	template, err := h.service.SaveTemplate(r.Context(), userID, r.FormValue("template_name"), req)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.respondSaved(w, r, template)
+}
+
+// SavePollAsTemplate guarda una encuesta propia como plantilla.
+func (h *templateHandler) SavePollAsTemplate(w http.ResponseWriter, r *http.Request) {
+	pollID, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_poll_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	template, err := h.service.SavePollAsTemplate(r.Context(), pollID, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	h.respondSaved(w, r, template)
+}
+
+func (h *templateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "template.invalid_id"))
+		return
+	}
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := h.service.DeleteTemplate(r.Context(), id, userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", templatesChangedEvent)
+		w.Header().Set("HX-Reswap", "none")
+		components.Toast(i18n.T(r.Context(), "template.deleted"), false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, nil, i18n.T(r.Context(), "template.deleted"))
+}
+
+// respondForm dibuja el formulario de creación con el borrador (HTMX) o devuelve el borrador en JSON.
+func (h *templateHandler) respondForm(w http.ResponseWriter, r *http.Request, userID int32, draft services.PollRequest, selected int32) {
+	//API
+	if r.Header.Get("HX-Request") != "true" {
+		RespondWithData(w, http.StatusOK, draft, "")
+		return
+	}
+
+	//WEB
+	templates, err := h.service.ListTemplates(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	orgs, err := h.service.EditableOrgs(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if err := views.PollForm(draft, templates, selected, orgs).Render(r.Context(), w); err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (h *templateHandler) respondSaved(w http.ResponseWriter, r *http.Request, template *services.PollTemplate) {
+	message := i18n.T(r.Context(), "template.saved", template.Name)
+
+	//WEB
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Trigger", templatesChangedEvent)
+		w.Header().Set("HX-Reswap", "none")
+		components.Toast(message, false).Render(r.Context(), w)
+		return
+	}
+
+	//API
+	RespondWithData(w, http.StatusOK, template, message)
+}
@@ -2,24 +2,28 @@ package handlers
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"time"
 
 	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
 	"webpolls/services"
 	"webpolls/utils"
 	"webpolls/views"
-
-	"github.com/jackc/pgx/v5"
 )
 
-// userHandler ahora depende de UserService
+// userHandler ahora depende de UserService y TwoFactorService
 type userHandler struct {
-	service *services.UserService
+	service   *services.UserService
+	twoFactor *services.TwoFactorService
 }
 
-// NewUserHandler ahora inyecta UserService
-func NewUserHandler(service *services.UserService) *userHandler {
-	return &userHandler{service: service}
+// NewUserHandler ahora inyecta UserService y TwoFactorService
+func NewUserHandler(service *services.UserService, twoFactor *services.TwoFactorService) *userHandler {
+	return &userHandler{service: service, twoFactor: twoFactor}
 }
 
 func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
@@ -28,7 +32,7 @@ func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		w.Header().Set("HX-Reswap", "none")
 		w.WriteHeader(http.StatusBadRequest)
-		components.Toast("Invalid form data", true).Render(r.Context(), w)
+		components.Toast(i18n.T(r.Context(), "error.invalid_form"), true).Render(r.Context(), w)
 		return
 	}
 	req = services.UserRequest{
@@ -39,9 +43,7 @@ func (h *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	_, err := h.service.CreateUser(r.Context(), req)
 	if err != nil {
-		w.Header().Set("HX-Reswap", "none")
-		w.WriteHeader(http.StatusBadRequest)
-		components.Toast(err.Error(), true).Render(r.Context(), w)
+		respondError(w, r, err)
 		return
 	}
 
@@ -54,13 +56,13 @@ func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	userID, err := utils.ConvertTo32(id)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de usuario invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_user_id"))
 		return
 	}
 
 	deletedUsername, err := h.service.DeleteUser(r.Context(), userID)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, r, err)
 		return
 	}
 
@@ -71,55 +73,47 @@ func (h *userHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//API
-	RespondWithData(w, http.StatusOK, map[string]string{"username": deletedUsername}, "Usuario eliminado correctamente")
+	RespondWithData(w, http.StatusOK, map[string]string{"username": deletedUsername}, i18n.T(r.Context(), "user.deleted"))
 }
 
 func (h *userHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	userID, err := utils.ConvertTo32(id)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de usuario invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_user_id"))
 		return
 	}
 
 	user, err := h.service.GetUserByID(r.Context(), userID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			RespondWithError(w, http.StatusNotFound, "Usuario no encontrado")
-		} else {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
-		}
+		respondError(w, r, err)
 		return
 	}
 
-	RespondWithData(w, http.StatusOK, user, "Usuario obtenido correctamente")
+	RespondWithData(w, http.StatusOK, user, i18n.T(r.Context(), "user.fetched"))
 }
 
 func (h *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	userID, err := utils.ConvertTo32(id)
 	if err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Id de usuario invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_user_id"))
 		return
 	}
 
 	var req services.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Payload de actualizacion invalido")
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_payload"))
 		return
 	}
 
 	user, err := h.service.UpdateUser(r.Context(), userID, req)
 	if err != nil {
-		if err.Error() == "usuario no encontrado" {
-			RespondWithError(w, http.StatusNotFound, err.Error())
-		} else {
-			RespondWithError(w, http.StatusBadRequest, err.Error())
-		}
+		respondError(w, r, err)
 		return
 	}
 
-	RespondWithData(w, http.StatusOK, user, "Usuario actualizado correctamente")
+	RespondWithData(w, http.StatusOK, user, i18n.T(r.Context(), "user.updated"))
 }
 
 func (h *userHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
@@ -129,7 +123,7 @@ func (h *userHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := views.AuthLayout(views.Login(), "Iniciar Sesión - Webpolls").Render(r.Context(), w)
+	err := views.AuthLayout(views.Login(), i18n.T(r.Context(), "title.login")).Render(r.Context(), w)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -140,7 +134,7 @@ func (h *userHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		w.Header().Set("HX-Reswap", "none")
 		w.WriteHeader(http.StatusBadRequest)
-		components.Toast("Invalid form data", true).Render(r.Context(), w)
+		components.Toast(i18n.T(r.Context(), "error.invalid_form"), true).Render(r.Context(), w)
 		return
 	}
 
@@ -149,30 +143,162 @@ func (h *userHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.Authenticate(r.Context(), email, password)
 	if err != nil {
-		w.Header().Set("HX-Reswap", "none")
-		w.WriteHeader(http.StatusUnauthorized)
-		components.Toast(err.Error(), true).Render(r.Context(), w)
+		services.RecordLogin(false)
+		respondError(w, r, err)
 		return
 	}
 
-	// Crear sesión
 	session := utils.GetSession(r)
+
+	// Con 2FA activado la contraseña no alcanza: dejamos al usuario pendiente del segundo paso
+	if user.TwoFactorEnabled {
+		session.Values["pending_2fa_user_id"] = user.Id
+		session.Values["pending_2fa_username"] = user.Username
+		session.Values["pending_2fa_locale"] = user.Locale
+		session.Values["pending_2fa_expires"] = time.Now().Add(services.PendingTwoFactorTTL).Unix()
+		utils.SaveSession(w, r, session)
+
+		w.Header().Set("HX-Redirect", "/login/2fa")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Crear sesión
+	services.RecordLogin(true)
 	session.Values["authenticated"] = true
 	session.Values["user_id"] = user.Id
 	session.Values["username"] = user.Username
 	utils.SaveSession(w, r, session)
+	setLocaleCookie(w, user.Locale)
 
 	// Redirigir al home usando HTMX
 	w.Header().Set("HX-Redirect", "/")
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h *userHandler) GetTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	if _, ok := pendingTwoFactorUser(r); !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	err := views.AuthLayout(views.TwoFactorLogin(), i18n.T(r.Context(), "title.two_factor")).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+func (h *userHandler) PostTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.Header().Set("HX-Reswap", "none")
+		w.WriteHeader(http.StatusBadRequest)
+		components.Toast(i18n.T(r.Context(), "error.invalid_form"), true).Render(r.Context(), w)
+		return
+	}
+
+	userID, ok := pendingTwoFactorUser(r)
+	if !ok {
+		w.Header().Set("HX-Redirect", "/login")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.twoFactor.Verify(r.Context(), userID, r.FormValue("code")); err != nil {
+		services.RecordLogin(false)
+		respondError(w, r, err)
+		return
+	}
+
+	session := utils.GetSession(r)
+	username := session.Values["pending_2fa_username"]
+	locale, _ := session.Values["pending_2fa_locale"].(string)
+	delete(session.Values, "pending_2fa_user_id")
+	delete(session.Values, "pending_2fa_username")
+	delete(session.Values, "pending_2fa_expires")
+	delete(session.Values, "pending_2fa_locale")
+
+	// Crear sesión
+	services.RecordLogin(true)
+	session.Values["authenticated"] = true
+	session.Values["user_id"] = userID
+	session.Values["username"] = username
+	utils.SaveSession(w, r, session)
+	setLocaleCookie(w, locale)
+
+	w.Header().Set("HX-Redirect", "/")
+	w.WriteHeader(http.StatusOK)
+}
+
+// pendingTwoFactorUser devuelve el usuario que ya pasó la contraseña y espera el segundo paso.
+func pendingTwoFactorUser(r *http.Request) (int32, bool) {
+	session := utils.GetSession(r)
+	userID, ok := session.Values["pending_2fa_user_id"].(int32)
+	if !ok {
+		return 0, false
+	}
+	expires, _ := session.Values["pending_2fa_expires"].(int64)
+	if time.Now().Unix() > expires {
+		return 0, false
+	}
+	return userID, true
+}
+
+// SetLocale cambia el idioma de la interfaz. La cookie aplica a cualquier visitante; si hay
+// sesión, además se guarda como preferencia para los próximos inicios de sesión.
+func (h *userHandler) SetLocale(w http.ResponseWriter, r *http.Request) {
+	locale := r.FormValue("locale")
+	if !i18n.IsSupported(locale) {
+		respondToastError(w, r, http.StatusBadRequest, i18n.Message(r.Context(), i18n.NewError("locale.unsupported", locale)))
+		return
+	}
+
+	setLocaleCookie(w, locale)
+	if userID, ok := r.Context().Value(middleware.UserIDKey).(int32); ok {
+		if err := h.service.SetLocale(r.Context(), userID, locale); err != nil {
+			slog.WarnContext(r.Context(), "error saving user locale", "user_id", userID, "error", err)
+		}
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Refresh", "true")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	target := "/"
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Host == r.Host && referer.Path != "" {
+		target = referer.RequestURI()
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// setLocaleCookie deja fijado el idioma; no hace nada si el usuario nunca eligió uno.
+func setLocaleCookie(w http.ResponseWriter, locale string) {
+	if !i18n.IsSupported(locale) {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     i18n.CookieName,
+		Value:    locale,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		Secure:   utils.Store.Options.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
 func (h *userHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	session := utils.GetSession(r)
 	session.Values["authenticated"] = false
 	session.Options.MaxAge = -1
 	utils.SaveSession(w, r, session)
 
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/login")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
 	http.Redirect(w, r, "/login", http.StatusSeeOther)
 }
 
@@ -183,7 +309,7 @@ func (h *userHandler) GetRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := views.AuthLayout(views.Register(), "Registrarse - Webpolls").Render(r.Context(), w)
+	err := views.AuthLayout(views.Register(), i18n.T(r.Context(), "title.register")).Render(r.Context(), w)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"webpolls/components"
+	"webpolls/i18n"
+	"webpolls/middleware"
+	"webpolls/services"
+	"webpolls/utils"
+	"webpolls/views"
+)
+
+// webhookHandler maneja los webhooks del usuario logueado y su registro de entregas.
+type webhookHandler struct {
+	service *services.WebhookService
+}
+
+// NewWebhookHandler inyecta WebhookService
+func NewWebhookHandler(service *services.WebhookService) *webhookHandler {
+	return &webhookHandler{service: service}
+}
+
+func (h *webhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	webhooks, err := h.service.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listing webhooks", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_webhooks"))
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.Webhooks(webhooks).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	err = views.Layout(views.Webhooks(webhooks), i18n.T(r.Context(), "title.webhooks"), true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+func (h *webhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	if err := r.ParseForm(); err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_form"))
+		return
+	}
+
+	if _, err := h.service.CreateWebhook(r.Context(), userID, r.FormValue("url"), r.Form["events"]); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	h.renderList(w, r, userID, i18n.T(r.Context(), "webhook.created"))
+}
+
+func (h *webhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_webhook_id"))
+		return
+	}
+
+	if err := h.service.DeleteWebhook(r.Context(), id, userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	h.renderList(w, r, userID, i18n.T(r.Context(), "webhook.deleted"))
+}
+
+// SendTestEvent encola un ping; el resultado se ve en el registro de entregas.
+func (h *webhookHandler) SendTestEvent(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		respondToastError(w, r, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_webhook_id"))
+		return
+	}
+
+	if err := h.service.SendTestEvent(r.Context(), id, userID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("HX-Reswap", "none")
+	components.Toast(i18n.T(r.Context(), "webhook.test_queued"), false).Render(r.Context(), w)
+}
+
+func (h *webhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(int32)
+
+	id, err := utils.ConvertTo32(r.PathValue("id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, i18n.T(r.Context(), "error.invalid_webhook_id"))
+		return
+	}
+
+	webhook, deliveries, err := h.service.ListDeliveries(r.Context(), id, userID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err := views.WebhookDeliveries(webhook, deliveries).Render(r.Context(), w)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	title := i18n.T(r.Context(), "title.webhook_deliveries", webhook.URL)
+	err = views.Layout(views.WebhookDeliveries(webhook, deliveries), title, true).Render(r.Context(), w)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// renderList vuelve a dibujar la lista de webhooks con un toast de confirmación.
+func (h *webhookHandler) renderList(w http.ResponseWriter, r *http.Request, userID int32, message string) {
+	webhooks, err := h.service.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error listing webhooks", "error", err)
+		respondToastError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.load_webhooks"))
+		return
+	}
+
+	views.WebhookList(webhooks).Render(r.Context(), w)
+	components.Toast(message, false).Render(r.Context(), w)
+}
@@ -0,0 +1,620 @@
+package i18n
+
+// en es el catálogo en inglés.
+var en = map[string]string{
+	"account.security_title": "Account security",
+
+	"admin.cannot_suspend_self":          "you can't suspend your own account",
+	"admin.staff_suspend_requires_admin": "only an administrator can suspend another staff member",
+	"admin.role_change_requires_admin":   "only an administrator can change roles",
+	"admin.cannot_change_own_role":       "you can't change your own role",
+	"admin.invalid_role":                 "invalid role",
+	"admin.invalid_action":               "invalid action",
+	"admin.title":                        "Administration",
+	"admin.tab_users":                    "Users",
+	"admin.tab_polls":                    "Polls",
+	"admin.tab_reports":                  "Reports",
+	"admin.tab_audit":                    "Audit",
+	"admin.col_user":                     "User",
+	"admin.col_email":                    "Email",
+	"admin.col_role":                     "Role",
+	"admin.col_status":                   "Status",
+	"admin.col_actions":                  "Actions",
+	"admin.suspended":                    "Suspended",
+	"admin.active":                       "Active",
+	"admin.unsuspend":                    "Reactivate",
+	"admin.suspend":                      "Suspend",
+	"admin.suspend_confirm":              "Suspend %s?",
+	"admin.col_poll":                     "Poll",
+	"admin.col_author":                   "Author",
+	"admin.col_open_reports":             "Open reports",
+	"admin.no_polls":                     "There are no polls.",
+	"admin.hidden":                       "Hidden",
+	"admin.visible":                      "Visible",
+	"admin.unhide":                       "Show",
+	"admin.hide":                         "Hide",
+	"admin.delete":                       "Delete",
+	"admin.delete_poll_confirm":          "Delete the poll \"%s\"? This can't be undone.",
+	"admin.reports_open":                 "Open",
+	"admin.reports_resolved":             "Resolved",
+	"admin.reports_dismissed":            "Dismissed",
+	"admin.col_date":                     "Date",
+	"admin.col_reason":                   "Reason",
+	"admin.col_reporter":                 "Reported by",
+	"admin.no_reports":                   "There are no reports.",
+	"admin.dismiss":                      "Dismiss",
+	"admin.hide_poll":                    "Hide poll",
+	"admin.delete_poll":                  "Delete poll",
+	"admin.col_actor":                    "Actor",
+	"admin.col_action":                   "Action",
+	"admin.col_target":                   "Target",
+	"admin.col_details":                  "Details",
+	"admin.no_audit":                     "No actions have been recorded yet.",
+	"admin.system_actor":                 "System",
+	"admin.role_updated":                 "Role updated",
+	"admin.report_reviewed":              "Report reviewed",
+	"admin.user_suspended":               "User suspended",
+	"admin.user_reactivated":             "User reactivated",
+	"admin.poll_hidden":                  "Poll hidden",
+	"admin.poll_visible":                 "Poll visible again",
+	"admin.poll_deleted":                 "Poll deleted",
+
+	"auth.wrong_password":      "wrong password",
+	"auth.account_suspended":   "your account is suspended",
+	"auth.invalid_credentials": "invalid credentials",
+
+	"comments.title":            "Comments",
+	"comments.not_found":        "comment not found",
+	"comments.invalid_id":       "invalid comment ID",
+	"comments.invalid_parent":   "the comment you are replying to does not exist",
+	"comments.body_required":    "the comment cannot be empty",
+	"comments.body_too_long":    "the comment cannot exceed %d characters",
+	"comments.blocked_words":    "the comment contains words that are not allowed",
+	"comments.not_author":       "only the author or the poll owner can do that",
+	"comments.locked":           "comments on this poll are locked",
+	"comments.disabled":         "comments on this poll are disabled",
+	"comments.invalid_mode":     "invalid comments mode",
+	"comments.mode":             "Comments:",
+	"comments.mode_open":        "Open",
+	"comments.mode_locked":      "Locked",
+	"comments.mode_disabled":    "Disabled",
+	"comments.locked_notice":    "Comments are locked: you can read them but not add new ones.",
+	"comments.disabled_notice":  "Comments are disabled and hidden from everyone else.",
+	"comments.login_to_comment": "to comment",
+	"comments.empty":            "No comments yet.",
+	"comments.placeholder":      "Write a comment…",
+	"comments.markdown_help":    "Supports **bold**, *italic*, `code` and links.",
+	"comments.submit":           "Comment",
+	"comments.reply":            "Reply",
+	"comments.edit":             "Edit",
+	"comments.save":             "Save",
+	"comments.delete":           "Delete",
+	"comments.delete_confirm":   "Delete this comment?",
+	"comments.deleted":          "Comment deleted.",
+	"comments.edited":           "(edited)",
+	"comments.anonymous":        "Deleted user",
+
+	"embed.too_many_origins":    "you can allow at most %d sites",
+	"embed.invalid_origin":      "%q is not a valid origin (e.g. https://example.com)",
+	"embed.origin_has_path":     "%q must be only scheme and host, without a path",
+	"embed.settings_toggle":     "Embed in another page",
+	"embed.vote_link":           "Vote on webpolls",
+	"embed.footer":              "A webpolls poll",
+	"embed.snippet":             "Embed code",
+	"embed.snippet_help":        "Add ?theme=light, hide_title=1 or compact=1 to the iframe URL to adjust it.",
+	"embed.origins":             "Allowed sites",
+	"embed.origins_placeholder": "https://example.com",
+	"embed.origins_help":        "One per line. Only those sites can show the poll in an iframe; use * to allow any.",
+	"embed.save_origins":        "Save sites",
+	"embed.json_only":           "Only the json format is supported",
+	"embed.unknown_url":         "Unrecognized URL",
+	"embed.origins_saved":       "Allowed sites saved",
+
+	"error.internal":           "Internal server error",
+	"error.csrf":               "Your session expired or the form is invalid. Reload the page and try again.",
+	"error.forbidden":          "You don't have permission to do this",
+	"error.too_many_requests":  "Too many attempts. Try again in %s.",
+	"error.load_security":      "Couldn't load the security settings",
+	"error.invalid_form":       "Invalid form",
+	"error.load_users":         "Couldn't load the users",
+	"error.load_polls":         "Couldn't load the polls",
+	"error.load_reports":       "Couldn't load the reports",
+	"error.load_audit":         "Couldn't load the audit log",
+	"error.invalid_user_id":    "Invalid user id",
+	"error.invalid_poll_id":    "Invalid poll id",
+	"error.invalid_report_id":  "Invalid report id",
+	"error.not_found":          "Not found",
+	"error.poll_not_found":     "Poll not found",
+	"error.load_poll":          "Couldn't load the poll",
+	"error.load_updated":       "Couldn't load the updated data",
+	"error.invalid_option_id":  "Invalid option id",
+	"error.vote_failed":        "Couldn't record the vote",
+	"error.invalid_json":       "Invalid JSON",
+	"error.update_option":      "Couldn't update the option",
+	"error.delete_option":      "Couldn't delete the option",
+	"error.user_not_found":     "User not found",
+	"error.invalid_payload":    "Invalid update payload",
+	"error.load_webhooks":      "Couldn't load the webhooks",
+	"error.invalid_webhook_id": "Invalid webhook id",
+
+	"form.email":                "Email",
+	"form.password":             "Password",
+	"form.username":             "Username",
+	"form.username_placeholder": "Username",
+	"form.current_password":     "Current password",
+
+	"format.date":             "Jan 2, 2006",
+	"format.datetime":         "Jan 2, 2006 15:04",
+	"format.datetime_seconds": "Jan 2, 2006 15:04:05",
+
+	"home.headline_1": "Your opinion counts,",
+	"home.headline_2": "make it matter.",
+	"home.lead":       "Create dynamic polls, share them with your audience and watch the results in real time. The modern platform for collective decisions.",
+	"home.cta":        "Get started",
+
+	"locale.unsupported": "unsupported language %q",
+
+	"login.title":           "Log in",
+	"login.no_account":      "Don't have an account?",
+	"login.register_link":   "Sign up here",
+	"login.remember_me":     "Remember me",
+	"login.forgot_password": "Forgot your password?",
+	"login.submit":          "Log in",
+
+	"meta.description": "Create polls and vote",
+
+	"nav.polls":         "Polls",
+	"nav.my_polls":      "My Polls",
+	"nav.security":      "Security",
+	"nav.webhooks":      "Webhooks",
+	"nav.admin":         "Admin",
+	"nav.logout":        "Log out",
+	"nav.login":         "Log in",
+	"nav.register":      "Sign up",
+	"nav.toggle_menu":   "Toggle menu",
+	"nav.language":      "Language",
+	"nav.notifications": "Notifications",
+	"nav.series":        "Series",
+	"nav.orgs":          "Organizations",
+
+	"notification.title":             "Notifications",
+	"notification.empty":             "You have no notifications.",
+	"notification.unread":            "Unread",
+	"notification.mark_all_read":     "Mark all as read",
+	"notification.not_found":         "notification not found",
+	"notification.invalid_id":        "Invalid notification ID",
+	"notification.preferences":       "Preferences",
+	"notification.preferences_help":  "Choose what you want to hear about.",
+	"notification.pref_vote":         "Votes on my polls",
+	"notification.pref_close":        "Polls I created or voted in closing",
+	"notification.pref_comment":      "Comments and replies",
+	"notification.pref_digest":       "Daily email digest",
+	"notification.save_preferences":  "Save preferences",
+	"notification.preferences_saved": "Preferences saved",
+	"notification.someone":           "Someone",
+	"notification.vote":              "%s voted on \"%s\"",
+	"notification.votes":             "%s and %d others voted on \"%s\"",
+	"notification.comment":           "%s commented on \"%s\"",
+	"notification.comments":          "%s and %d others commented on \"%s\"",
+	"notification.closed":            "Voting on \"%s\" has closed",
+	"notification.digest_subject":    "You have %d new notifications on Webpolls",
+	"notification.digest_greeting":   "Hi %s, here's what happened since your last digest:",
+	"notification.digest_footer":     "See all your notifications:",
+
+	"option.content_required": "the option can't be empty",
+	"option.blocked_words":    "the option contains words that aren't allowed",
+	"option.not_found":        "option not found",
+	"option.duplicate":        "the option \"%s\" is repeated",
+
+	"org.not_found":        "the organization doesn't exist or you aren't a member",
+	"org.invalid_id":       "invalid organization ID",
+	"org.invalid_role":     "invalid role",
+	"org.user_not_found":   "user %s doesn't exist",
+	"org.member_not_found": "not a member of the organization",
+	"org.last_owner":       "the organization must have at least one owner",
+	"org.owner_required":   "only the organization's owners can do this",
+	"org.name_required":    "the name is required",
+	"org.name_too_long":    "the name can't be longer than %d characters",
+	"org.created":          "Organization %s created",
+	"org.renamed":          "Name updated",
+	"org.deleted":          "Organization deleted",
+	"org.member_saved":     "Member saved",
+	"org.member_removed":   "Member removed",
+	"org.left":             "You left the organization",
+	"org.title":            "Organizations",
+	"org.new":              "New organization",
+	"org.help":             "An organization's owners and editors manage its polls together.",
+	"org.name":             "Name",
+	"org.name_placeholder": "My team",
+	"org.create":           "Create",
+	"org.empty":            "You aren't a member of any organization yet.",
+	"org.back":             "Back to organizations",
+	"org.members":          "Members",
+	"org.username":         "Username",
+	"org.role":             "Role",
+	"org.role_help":        "Owner: manages members and polls. Editor: creates and manages polls. Viewer: sees and votes.",
+	"org.add_member":       "Add or change role",
+	"org.delete":           "Delete organization",
+	"org.delete_confirm":   "Delete the organization? Its polls stay as polls of whoever created them.",
+	"org.polls":            "Organization polls",
+	"org.polls_help":       "Create polls for the organization by choosing it in",
+	"org.rename":           "Rename",
+	"org.your_role":        "Your role: %s",
+	"org.leave":            "Leave",
+	"org.leave_confirm":    "Leave the organization?",
+	"org.remove":           "Remove",
+	"org.remove_confirm":   "Remove %s from the organization?",
+
+	"org_role.owner":  "Owner",
+	"org_role.editor": "Editor",
+	"org_role.viewer": "Viewer",
+
+	"outcome.winner":      "Winner: %s",
+	"outcome.decided_on":  "Decided on %s",
+	"outcome.no_decision": "No decision: %s",
+	"outcome.no_quorum":   "the quorum wasn't reached",
+	"outcome.no_majority": "no option exceeded the required majority",
+	"outcome.tie":         "there was a tie",
+
+	"poll.not_found_or_not_owner":     "the poll doesn't exist or isn't yours",
+	"poll.still_open":                 "voting is still open",
+	"poll.close_not_allowed":          "the poll doesn't exist, isn't yours or is already closed",
+	"poll.question_required":          "the question can't be empty",
+	"poll.min_options":                "there must be at least 2 options",
+	"poll.max_options":                "there can be at most 4 options",
+	"poll.invalid_results_visibility": "invalid results visibility",
+	"poll.closes_at_past":             "the closing date must be in the future",
+	"poll.blocked_words":              "the poll contains words that aren't allowed",
+	"poll.unavailable":                "the poll isn't available",
+	"poll.voting_closed":              "voting is closed",
+	"poll.min_options_remaining":      "the poll must keep at least 2 options",
+	"poll.not_found":                  "poll not found",
+	"poll.back":                       "Back to polls",
+	"poll.total_votes":                "Total votes:",
+	"poll.closed":                     "Voting closed",
+	"poll.closes_on":                  "Closes on %s",
+	"poll.computing_outcome":          "Computing the outcome…",
+	"poll.your_vote":                  "Your vote",
+	"poll.option_votes":               "%d votes (%.1f%%)",
+	"poll.change_vote":                "Change vote",
+	"poll.close":                      "Close voting",
+	"poll.close_confirm":              "Close voting now? The outcome will be computed and it can't be reopened.",
+	"poll.login_link":                 "Log in",
+	"poll.login_to_vote":              "to vote.",
+	"poll.invalid_closes_at":          "Invalid closing date",
+	"poll.rules_not_integer":          "Decision rules must be whole numbers",
+	"poll.created":                    "Poll created successfully",
+	"poll.deleted":                    "Poll deleted successfully",
+	"poll.login_required":             "You must log in to vote",
+	"poll.option_updated":             "Option updated successfully",
+	"poll.option_deleted":             "Option deleted successfully",
+	"poll.max_options_toast":          "At most 4 options allowed",
+	"poll.not_owner":                  "you can't manage this poll",
+	"poll.title_taken":                "you already have a poll with that question",
+	"poll.copy_title":                 "%s (copy)",
+	"poll.already_published":          "the poll is already published",
+	"poll.not_scheduled":              "the poll has no scheduled publication",
+	"poll.not_published":              "the poll has not been published yet",
+	"poll.closes_before_publish":      "the closing date must be after the publication date",
+	"poll.invalid_publish_at":         "Invalid publication date",
+	"poll.draft_saved":                "Draft saved",
+	"poll.scheduled":                  "Poll scheduled for %s",
+	"poll.published":                  "Poll published",
+	"poll.unscheduled":                "Scheduled publication cancelled",
+	"poll.members_only":               "Only members of %s can vote",
+	"poll.members_only_notice":        "Poll for members of %s only",
+	"poll.members_only_without_org":   "members only requires choosing an organization",
+	"poll.org_not_allowed":            "you can't create polls in that organization",
+
+	"polls.system_title":            "All polls",
+	"polls.mine_title":              "My polls",
+	"polls.list_title":              "Poll list",
+	"polls.create_title":            "Create poll",
+	"polls.create_help":             "Add a new poll.",
+	"polls.question_placeholder":    "Question?",
+	"polls.question":                "Question",
+	"polls.add_option":              "Add option",
+	"polls.results_visibility":      "Show results",
+	"polls.closes_at":               "Voting closes (optional)",
+	"polls.rules":                   "Decision rules (optional)",
+	"polls.quorum_votes":            "Quorum (minimum votes)",
+	"polls.invited_voters":          "Invited voters",
+	"polls.quorum_percent":          "Quorum (% of invited)",
+	"polls.majority_percent":        "Majority (% to exceed)",
+	"polls.majority_placeholder":    "0 = most voted",
+	"polls.tie_break":               "On a tie",
+	"polls.create":                  "Create poll",
+	"polls.option":                  "Option",
+	"polls.option_placeholder":      "Option...",
+	"polls.empty":                   "There are no polls yet.",
+	"polls.voted":                   "Voted",
+	"polls.delete":                  "Delete poll",
+	"polls.tag_title":               "Polls tagged #%s",
+	"polls.clear_tag":               "Clear tag filter",
+	"polls.browse_tags":             "Browse tags",
+	"polls.search_placeholder":      "Search polls…",
+	"polls.status_all":              "All",
+	"polls.status_open":             "Open",
+	"polls.status_closed":           "Closed",
+	"polls.tags":                    "Tags",
+	"polls.tags_placeholder":        "sports, food",
+	"polls.tags_help":               "Comma separated, up to 5.",
+	"polls.duplicate":               "Duplicate",
+	"polls.save_as_template":        "Save as template",
+	"polls.template_help":           "You can use {{date}}, {{week}}, {{month}} and {{year}} in the title: they're filled in when the poll is created.",
+	"polls.from_template":           "Start from a template",
+	"polls.blank_form":              "Blank poll",
+	"polls.template_delete":         "Delete template",
+	"polls.template_delete_confirm": "Delete this template?",
+	"polls.save_draft":              "Save draft",
+	"polls.publish_at":              "Publish on (optional)",
+	"polls.publish_at_help":         "Leave empty to publish it right away",
+	"polls.draft_badge":             "Draft",
+	"polls.scheduled_badge":         "Publishes on %s",
+	"polls.edit_draft":              "Edit draft",
+	"polls.edit_draft_title":        "Edit draft",
+	"polls.edit_draft_help":         "Nobody else can see it until you publish it",
+	"polls.cancel_edit":             "Cancel",
+	"polls.org":                     "Organization",
+	"polls.personal":                "Personal (no organization)",
+	"polls.members_only":            "Only organization members can vote",
+
+	"publish.draft":          "Draft: only you can see it",
+	"publish.scheduled_for":  "Publishes automatically on %s",
+	"publish.preview":        "Preview",
+	"publish.now":            "Publish now",
+	"publish.now_confirm":    "Publish the poll now? It can't be edited afterwards.",
+	"publish.schedule_at":    "Schedule for",
+	"publish.schedule":       "Schedule",
+	"publish.unschedule":     "Cancel schedule",
+	"publish.back_to_draft":  "Back to draft",
+	"publish.preview_banner": "Preview: this is how voters will see the poll",
+
+	"register.title":       "Create account",
+	"register.has_account": "Already have an account?",
+	"register.login_link":  "Log in here",
+	"register.submit":      "Sign up",
+
+	"report.already_reviewed": "the report was already reviewed",
+	"report.invalid_reason":   "invalid report reason",
+	"report.details_too_long": "details can't exceed %d characters",
+	"report.own_poll":         "you can't report your own poll",
+	"report.duplicate":        "you already reported this poll",
+	"report.toggle":           "Report this poll",
+	"report.reason":           "Reason",
+	"report.details":          "Details (optional)",
+	"report.submit":           "Send report",
+	"report.thanks":           "Thanks, a moderator will review the poll",
+
+	"report_reason.spam":       "Spam",
+	"report_reason.offensive":  "Offensive content",
+	"report_reason.misleading": "Misleading",
+	"report_reason.filtered":   "Automatic filter",
+	"report_reason.other":      "Other",
+
+	"results.after_vote":         "After voting",
+	"results.after_close":        "When voting closes",
+	"results.owner_only":         "Only me",
+	"results.always":             "Always",
+	"results.hidden_after_vote":  "Results are shown after you vote.",
+	"results.hidden_after_close": "Results are shown when voting closes.",
+	"results.hidden_owner_only":  "Only the poll's creator can see the results.",
+
+	"role.admin":     "Administrator",
+	"role.moderator": "Moderator",
+	"role.user":      "User",
+
+	"rules.invalid_tie_break":       "invalid tie-break policy",
+	"rules.quorum_min":              "the quorum must be at least 1 vote",
+	"rules.quorum_percent_range":    "the quorum must be between 1% and 100%",
+	"rules.invited_voters_required": "enter how many voters are invited to use a percentage quorum",
+	"rules.majority_range":          "the majority must be between 0% and 99%",
+
+	"series.not_found":          "series not found",
+	"series.invalid_id":         "Invalid series ID",
+	"series.starts_at_required": "set when the series starts",
+	"series.invalid_starts_at":  "Invalid start date",
+	"series.weekdays_required":  "choose at least one weekday",
+	"series.invalid_weekday":    "invalid weekday",
+	"series.invalid_cron":       "the cron expression must have five valid fields: minute hour day month weekday",
+	"series.cron_never":         "the cron expression never matches",
+	"series.invalid_frequency":  "invalid frequency",
+	"series.never_runs":         "the series has no future occurrence",
+	"series.instance_title":     "%s (%s)",
+	"series.created":            "Series created: the first poll will be created on %s",
+	"series.paused":             "Series paused",
+	"series.resumed":            "Series resumed",
+	"series.deleted":            "Series deleted",
+	"series.title":              "Recurring series",
+	"series.new":                "New series",
+	"series.help":               "Each time it runs, a new poll is created from the template and the previous one is closed.",
+	"series.no_templates":       "To create a series, first save a template in",
+	"series.template":           "Template",
+	"series.frequency":          "Frequency",
+	"series.starts_at":          "Starts on",
+	"series.weekdays":           "Days (weekly only)",
+	"series.cron":               "Cron expression (cron only)",
+	"series.cron_help":          "minute hour day month weekday, in server time",
+	"series.create":             "Create series",
+	"series.empty":              "You don't have any recurring series yet",
+	"series.active":             "Active",
+	"series.paused_badge":       "Paused",
+	"series.next_run":           "Next poll: %s",
+	"series.view":               "View polls and trend",
+	"series.pause":              "Pause",
+	"series.resume":             "Resume",
+	"series.delete":             "Delete",
+	"series.delete_confirm":     "Delete the series? Polls already created are kept.",
+	"series.back":               "Back to series",
+	"series.no_instances":       "The series hasn't created any polls yet",
+	"series.trend":              "Results per poll",
+	"series.instance":           "Poll",
+	"series.votes":              "Votes",
+	"series.open":               "open",
+	"series.part_of":            "Part of a recurring series",
+	"series.frequency_daily":    "Daily",
+	"series.frequency_weekly":   "Weekly",
+	"series.frequency_monthly":  "Monthly",
+	"series.frequency_cron":     "Cron",
+	"series.rule_daily":         "Every day at %s",
+	"series.rule_weekly":        "Every week (%s) at %s",
+	"series.rule_monthly":       "On day %d of every month at %s",
+	"series.rule_cron":          "Cron: %s",
+
+	"slack.invalid_link":   "the link is invalid or has expired",
+	"slack.link_title":     "Link Slack",
+	"slack.link_retry":     "Use the command in Slack again to get a new link.",
+	"slack.link_intro":     "You're about to link the Slack user %s (team %s) to your webpolls account.",
+	"slack.link_help":      "Polls you create and votes you cast from Slack will be in your name.",
+	"slack.link_submit":    "Link account",
+	"slack.linked":         "Done, your Slack user is linked. You can go back to Slack.",
+	"slack.create_failed":  "Couldn't create the poll: %s",
+	"slack.vote_failed":    "Couldn't record your vote: %s",
+	"slack.usage":          "Usage: `%s \"Question?\" \"Option 1\" \"Option 2\"` (2 to 4 options, each in quotes)",
+	"slack.link_prompt":    "First link your Slack user to your webpolls account.",
+	"slack.view_poll":      "View on webpolls",
+	"slack.votes":          "%d votes",
+	"slack.invalid_vote":   "invalid vote value",
+	"slack.unknown_action": "unknown Slack action: %q",
+	"slack.error":          "Something went wrong, please try again.",
+	"slack.action_failed":  "Couldn't process the action.",
+
+	"tag.too_long": "the tag \"%s\" is longer than %d characters",
+	"tag.invalid":  "the tag \"%s\" can only contain letters, numbers and hyphens",
+	"tag.too_many": "at most %d tags per poll",
+
+	"tags.title":      "Tags",
+	"tags.help":       "The most used poll tags. Pick one to see its polls.",
+	"tags.empty":      "No tagged polls yet",
+	"tags.poll_count": "%d polls",
+
+	"template.not_found":  "template not found",
+	"template.invalid_id": "Invalid template ID",
+	"template.saved":      "Template \"%s\" saved",
+	"template.deleted":    "Template deleted",
+	"template.in_use":     "the template is used by a recurring series; delete the series first",
+
+	"tie_break.first_option": "First option wins",
+	"tie_break.random":       "Random draw among tied options",
+	"tie_break.no_decision":  "No decision",
+
+	"time.one_second": "1 second",
+	"time.seconds":    "%d seconds",
+	"time.one_minute": "1 minute",
+	"time.minutes":    "%d minutes",
+
+	"title.security":           "Security - Webpolls",
+	"title.admin":              "Administration - Webpolls",
+	"title.my_polls":           "My polls - Webpolls",
+	"title.poll":               "Poll - Webpolls",
+	"title.polls":              "Webpolls - Polls",
+	"title.login":              "Log in - Webpolls",
+	"title.two_factor":         "Verification - Webpolls",
+	"title.register":           "Sign up - Webpolls",
+	"title.webhooks":           "Webhooks - Webpolls",
+	"title.slack_link":         "Link Slack - Webpolls",
+	"title.home":               "Webpolls",
+	"title.webhook_deliveries": "Deliveries for %s - Webpolls",
+	"title.tags":               "Tags - Webpolls",
+	"title.notifications":      "Notifications - Webpolls",
+	"title.poll_preview":       "Preview - Webpolls",
+	"title.series":             "Recurring series - Webpolls",
+	"title.series_detail":      "%s - Webpolls",
+	"title.orgs":               "Organizations - Webpolls",
+	"title.org":                "%s - Webpolls",
+
+	"twofactor.invalid_code":      "invalid verification code",
+	"twofactor.not_enabled":       "two-step verification isn't enabled",
+	"twofactor.login_title":       "Two-step verification",
+	"twofactor.not_you":           "Not you?",
+	"twofactor.back_to_login":     "Back to log in",
+	"twofactor.login_help":        "Enter the 6-digit code from your authenticator app or one of your recovery codes.",
+	"twofactor.code":              "Code",
+	"twofactor.verify":            "Verify",
+	"twofactor.title":             "Two-step verification",
+	"twofactor.enabled":           "On",
+	"twofactor.disabled":          "Off",
+	"twofactor.description":       "Besides your password, we'll ask for a code from your authenticator app when you log in.",
+	"twofactor.remaining_codes":   "You have %d unused recovery codes left.",
+	"twofactor.regenerate_title":  "Regenerate recovery codes",
+	"twofactor.regenerate":        "Regenerate codes",
+	"twofactor.disable_title":     "Turn off two-step verification",
+	"twofactor.disable":           "Turn off",
+	"twofactor.enable":            "Turn on two-step verification",
+	"twofactor.setup_title":       "Set up your authenticator app",
+	"twofactor.setup_help":        "Scan the QR code with Google Authenticator, 1Password, Authy or similar and enter the code it shows.",
+	"twofactor.qr_alt":            "QR code for the authenticator app",
+	"twofactor.manual_key":        "Can't scan it? Enter this key manually:",
+	"twofactor.verification_code": "Verification code",
+	"twofactor.confirm":           "Confirm and turn on",
+	"twofactor.recovery_title":    "Recovery codes",
+	"twofactor.recovery_help":     "Keep them somewhere safe. Each code works once if you lose access to your authenticator app. We won't show them again.",
+	"twofactor.recovery_done":     "Done, I saved them",
+	"twofactor.setup_failed":      "Couldn't start the setup",
+	"twofactor.setup_expired":     "The setup expired, start again",
+	"twofactor.enable_failed":     "Couldn't enable two-step verification",
+	"twofactor.enabled_toast":     "Two-step verification enabled",
+	"twofactor.disabled_toast":    "Two-step verification disabled",
+	"twofactor.codes_regenerated": "Recovery codes regenerated",
+	"twofactor.code_reused":       "that code was already used, wait for your app to show the next one",
+
+	"user.fields_required": "all fields are required",
+	"user.username_taken":  "that username is already taken",
+	"user.email_taken":     "that email is already registered",
+	"user.not_found":       "user not found",
+	"user.deleted":         "User deleted successfully",
+	"user.fetched":         "User fetched successfully",
+	"user.updated":         "User updated successfully",
+
+	"webhook.invalid_url":       "the URL must be http:// or https://",
+	"webhook.invalid_event":     "invalid event %q",
+	"webhook.events_required":   "choose at least one event",
+	"webhook.limit":             "you can't have more than %d webhooks",
+	"webhook.not_found":         "webhook not found",
+	"webhook.test_queued":       "Test event queued",
+	"webhook.created":           "Webhook created",
+	"webhook.deleted":           "Webhook deleted",
+	"webhook.blocked_url":       "the URL can't point to a local, private or reserved address",
+	"webhook.unresolvable_host": "couldn't resolve %s",
+
+	"webhook_event.poll_created": "Poll created",
+	"webhook_event.poll_voted":   "New vote",
+	"webhook_event.poll_closed":  "Voting closed",
+	"webhook_event.poll_deleted": "Poll deleted",
+
+	"webhooks.title":            "Webhooks",
+	"webhooks.new":              "New webhook",
+	"webhooks.help":             "We'll send a JSON POST to the URL when any of the chosen events happens on your polls. Each delivery is signed with HMAC-SHA256 in the %s header.",
+	"webhooks.url":              "URL",
+	"webhooks.url_placeholder":  "https://example.com/webhooks/webpolls",
+	"webhooks.events":           "Events",
+	"webhooks.create":           "Create webhook",
+	"webhooks.empty":            "You don't have any webhooks yet.",
+	"webhooks.show_secret":      "Show signing secret",
+	"webhooks.view_deliveries":  "View deliveries",
+	"webhooks.send_test":        "Send test event",
+	"webhooks.delete":           "Delete",
+	"webhooks.delete_confirm":   "Delete this webhook? Its delivery log will be lost.",
+	"webhooks.back":             "Back to webhooks",
+	"webhooks.deliveries_title": "Deliveries",
+	"webhooks.refresh":          "Refresh",
+	"webhooks.col_event":        "Event",
+	"webhooks.col_status":       "Status",
+	"webhooks.col_attempts":     "Attempts",
+	"webhooks.col_response":     "Response",
+	"webhooks.col_created":      "Created",
+	"webhooks.no_deliveries":    "No deliveries yet.",
+	"webhooks.delivered":        "Delivered",
+	"webhooks.failed":           "Failed",
+	"webhooks.pending":          "Pending",
+	"webhooks.next_attempt":     "Next attempt: %s",
+	"webhooks.http_status":      "HTTP %d",
+
+	"weekday.0": "Sun",
+	"weekday.1": "Mon",
+	"weekday.2": "Tue",
+	"weekday.3": "Wed",
+	"weekday.4": "Thu",
+	"weekday.5": "Fri",
+	"weekday.6": "Sat",
+}
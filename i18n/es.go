@@ -0,0 +1,620 @@
+package i18n
+
+// es es el catálogo en español; es el de respaldo cuando falta una clave en otro idioma.
+var es = map[string]string{
+	"account.security_title": "Seguridad de la cuenta",
+
+	"admin.cannot_suspend_self":          "no puedes suspender tu propia cuenta",
+	"admin.staff_suspend_requires_admin": "solo un administrador puede suspender a otro miembro del staff",
+	"admin.role_change_requires_admin":   "solo un administrador puede cambiar roles",
+	"admin.cannot_change_own_role":       "no puedes cambiar tu propio rol",
+	"admin.invalid_role":                 "rol inválido",
+	"admin.invalid_action":               "acción inválida",
+	"admin.title":                        "Administración",
+	"admin.tab_users":                    "Usuarios",
+	"admin.tab_polls":                    "Encuestas",
+	"admin.tab_reports":                  "Reportes",
+	"admin.tab_audit":                    "Auditoría",
+	"admin.col_user":                     "Usuario",
+	"admin.col_email":                    "Email",
+	"admin.col_role":                     "Rol",
+	"admin.col_status":                   "Estado",
+	"admin.col_actions":                  "Acciones",
+	"admin.suspended":                    "Suspendido",
+	"admin.active":                       "Activo",
+	"admin.unsuspend":                    "Reactivar",
+	"admin.suspend":                      "Suspender",
+	"admin.suspend_confirm":              "¿Suspender a %s?",
+	"admin.col_poll":                     "Encuesta",
+	"admin.col_author":                   "Autor",
+	"admin.col_open_reports":             "Reportes abiertos",
+	"admin.no_polls":                     "No hay encuestas.",
+	"admin.hidden":                       "Oculta",
+	"admin.visible":                      "Visible",
+	"admin.unhide":                       "Mostrar",
+	"admin.hide":                         "Ocultar",
+	"admin.delete":                       "Eliminar",
+	"admin.delete_poll_confirm":          "¿Eliminar la encuesta \"%s\"? No se puede deshacer.",
+	"admin.reports_open":                 "Abiertos",
+	"admin.reports_resolved":             "Resueltos",
+	"admin.reports_dismissed":            "Descartados",
+	"admin.col_date":                     "Fecha",
+	"admin.col_reason":                   "Motivo",
+	"admin.col_reporter":                 "Reportado por",
+	"admin.no_reports":                   "No hay reportes.",
+	"admin.dismiss":                      "Descartar",
+	"admin.hide_poll":                    "Ocultar encuesta",
+	"admin.delete_poll":                  "Eliminar encuesta",
+	"admin.col_actor":                    "Responsable",
+	"admin.col_action":                   "Acción",
+	"admin.col_target":                   "Objetivo",
+	"admin.col_details":                  "Detalles",
+	"admin.no_audit":                     "Todavía no hay acciones registradas.",
+	"admin.system_actor":                 "Sistema",
+	"admin.role_updated":                 "Rol actualizado",
+	"admin.report_reviewed":              "Reporte revisado",
+	"admin.user_suspended":               "Usuario suspendido",
+	"admin.user_reactivated":             "Usuario reactivado",
+	"admin.poll_hidden":                  "Encuesta oculta",
+	"admin.poll_visible":                 "Encuesta visible de nuevo",
+	"admin.poll_deleted":                 "Encuesta eliminada",
+
+	"auth.wrong_password":      "contraseña incorrecta",
+	"auth.account_suspended":   "tu cuenta está suspendida",
+	"auth.invalid_credentials": "credenciales inválidas",
+
+	"comments.title":            "Comentarios",
+	"comments.not_found":        "comentario no encontrado",
+	"comments.invalid_id":       "ID de comentario inválido",
+	"comments.invalid_parent":   "el comentario al que respondes no existe",
+	"comments.body_required":    "el comentario no puede estar vacío",
+	"comments.body_too_long":    "el comentario no puede superar los %d caracteres",
+	"comments.blocked_words":    "el comentario contiene palabras no permitidas",
+	"comments.not_author":       "solo el autor o el dueño de la encuesta pueden hacer eso",
+	"comments.locked":           "los comentarios de esta encuesta están bloqueados",
+	"comments.disabled":         "los comentarios de esta encuesta están desactivados",
+	"comments.invalid_mode":     "estado de comentarios inválido",
+	"comments.mode":             "Comentarios:",
+	"comments.mode_open":        "Abiertos",
+	"comments.mode_locked":      "Bloqueados",
+	"comments.mode_disabled":    "Desactivados",
+	"comments.locked_notice":    "Los comentarios están bloqueados: se pueden leer pero no agregar.",
+	"comments.disabled_notice":  "Los comentarios están desactivados y nadie más los ve.",
+	"comments.login_to_comment": "para comentar",
+	"comments.empty":            "Todavía no hay comentarios.",
+	"comments.placeholder":      "Escribe un comentario…",
+	"comments.markdown_help":    "Admite **negrita**, *cursiva*, `código` y enlaces.",
+	"comments.submit":           "Comentar",
+	"comments.reply":            "Responder",
+	"comments.edit":             "Editar",
+	"comments.save":             "Guardar",
+	"comments.delete":           "Borrar",
+	"comments.delete_confirm":   "¿Borrar este comentario?",
+	"comments.deleted":          "Comentario borrado.",
+	"comments.edited":           "(editado)",
+	"comments.anonymous":        "Usuario eliminado",
+
+	"embed.too_many_origins":    "puedes autorizar como máximo %d sitios",
+	"embed.invalid_origin":      "%q no es un origen válido (ej. https://ejemplo.com)",
+	"embed.origin_has_path":     "%q debe ser solo esquema y dominio, sin ruta",
+	"embed.settings_toggle":     "Insertar en otra página",
+	"embed.vote_link":           "Vota en webpolls",
+	"embed.footer":              "Encuesta de webpolls",
+	"embed.snippet":             "Código para insertar",
+	"embed.snippet_help":        "Agrega ?theme=light, hide_title=1 o compact=1 a la URL del iframe para ajustarlo.",
+	"embed.origins":             "Sitios autorizados",
+	"embed.origins_placeholder": "https://ejemplo.com",
+	"embed.origins_help":        "Uno por línea. Solo esos sitios pueden mostrar la encuesta en un iframe; usa * para permitir cualquiera.",
+	"embed.save_origins":        "Guardar sitios",
+	"embed.json_only":           "Solo se soporta el formato json",
+	"embed.unknown_url":         "URL no reconocida",
+	"embed.origins_saved":       "Sitios autorizados guardados",
+
+	"error.internal":           "Error interno del servidor",
+	"error.csrf":               "La sesión expiró o el formulario no es válido. Recarga la página e inténtalo de nuevo.",
+	"error.forbidden":          "No tienes permisos para realizar esta acción",
+	"error.too_many_requests":  "Demasiados intentos. Vuelve a intentarlo en %s.",
+	"error.load_security":      "No se pudo obtener la configuración de seguridad",
+	"error.invalid_form":       "Formulario inválido",
+	"error.load_users":         "No se pudieron obtener los usuarios",
+	"error.load_polls":         "No se pudieron obtener las encuestas",
+	"error.load_reports":       "No se pudieron obtener los reportes",
+	"error.load_audit":         "No se pudo obtener el registro de auditoría",
+	"error.invalid_user_id":    "Id de usuario inválido",
+	"error.invalid_poll_id":    "Id de encuesta inválido",
+	"error.invalid_report_id":  "Id de reporte inválido",
+	"error.not_found":          "No encontrado",
+	"error.poll_not_found":     "Encuesta no encontrada",
+	"error.load_poll":          "Error al obtener encuesta",
+	"error.load_updated":       "Error al obtener datos actualizados",
+	"error.invalid_option_id":  "Id de opción inválido",
+	"error.vote_failed":        "Error al registrar voto",
+	"error.invalid_json":       "JSON inválido",
+	"error.update_option":      "Error al actualizar opción",
+	"error.delete_option":      "Error al eliminar opción",
+	"error.user_not_found":     "Usuario no encontrado",
+	"error.invalid_payload":    "Payload de actualización inválido",
+	"error.load_webhooks":      "No se pudieron obtener los webhooks",
+	"error.invalid_webhook_id": "Id de webhook inválido",
+
+	"form.email":                "Email",
+	"form.password":             "Contraseña",
+	"form.username":             "Usuario",
+	"form.username_placeholder": "Nombre de usuario",
+	"form.current_password":     "Contraseña actual",
+
+	"format.date":             "02/01/2006",
+	"format.datetime":         "02/01/2006 15:04",
+	"format.datetime_seconds": "02/01/2006 15:04:05",
+
+	"home.headline_1": "Tu opinión cuenta,",
+	"home.headline_2": "hazla valer.",
+	"home.lead":       "Crea encuestas dinámicas, comparte con tu audiencia y visualiza los resultados en tiempo real. La plataforma moderna para decisiones colectivas.",
+	"home.cta":        "Comenzar ahora",
+
+	"locale.unsupported": "idioma %q no soportado",
+
+	"login.title":           "Iniciar Sesión",
+	"login.no_account":      "¿No tienes una cuenta?",
+	"login.register_link":   "Regístrate aquí",
+	"login.remember_me":     "Recordarme",
+	"login.forgot_password": "¿Olvidaste tu contraseña?",
+	"login.submit":          "Ingresar",
+
+	"meta.description": "Pagina para crear polls y votar",
+
+	"nav.polls":         "Polls",
+	"nav.my_polls":      "Mis Polls",
+	"nav.security":      "Seguridad",
+	"nav.webhooks":      "Webhooks",
+	"nav.admin":         "Admin",
+	"nav.logout":        "Cerrar Sesión",
+	"nav.login":         "Iniciar Sesión",
+	"nav.register":      "Registrarse",
+	"nav.toggle_menu":   "Abrir menú",
+	"nav.language":      "Idioma",
+	"nav.notifications": "Notificaciones",
+	"nav.series":        "Series",
+	"nav.orgs":          "Organizaciones",
+
+	"notification.title":             "Notificaciones",
+	"notification.empty":             "No tienes notificaciones.",
+	"notification.unread":            "Sin leer",
+	"notification.mark_all_read":     "Marcar todas como leídas",
+	"notification.not_found":         "notificación no encontrada",
+	"notification.invalid_id":        "ID de notificación inválido",
+	"notification.preferences":       "Preferencias",
+	"notification.preferences_help":  "Elige de qué quieres enterarte.",
+	"notification.pref_vote":         "Votos en mis encuestas",
+	"notification.pref_close":        "Cierre de encuestas que creé o voté",
+	"notification.pref_comment":      "Comentarios y respuestas",
+	"notification.pref_digest":       "Resumen diario por correo",
+	"notification.save_preferences":  "Guardar preferencias",
+	"notification.preferences_saved": "Preferencias guardadas",
+	"notification.someone":           "Alguien",
+	"notification.vote":              "%s votó en \"%s\"",
+	"notification.votes":             "%s y %d más votaron en \"%s\"",
+	"notification.comment":           "%s comentó en \"%s\"",
+	"notification.comments":          "%s y %d más comentaron en \"%s\"",
+	"notification.closed":            "La votación de \"%s\" cerró",
+	"notification.digest_subject":    "Tienes %d notificaciones nuevas en Webpolls",
+	"notification.digest_greeting":   "Hola %s, esto pasó desde tu último resumen:",
+	"notification.digest_footer":     "Ver todas tus notificaciones:",
+
+	"option.content_required": "el contenido de la opción no puede estar vacío",
+	"option.blocked_words":    "la opción contiene palabras no permitidas",
+	"option.not_found":        "opción no encontrada",
+	"option.duplicate":        "la opción \"%s\" está repetida",
+
+	"org.not_found":        "la organización no existe o no eres miembro",
+	"org.invalid_id":       "ID de organización inválido",
+	"org.invalid_role":     "rol inválido",
+	"org.user_not_found":   "no existe el usuario %s",
+	"org.member_not_found": "no es miembro de la organización",
+	"org.last_owner":       "la organización tiene que tener al menos un dueño",
+	"org.owner_required":   "solo los dueños de la organización pueden hacer esto",
+	"org.name_required":    "el nombre es obligatorio",
+	"org.name_too_long":    "el nombre no puede tener más de %d caracteres",
+	"org.created":          "Organización %s creada",
+	"org.renamed":          "Nombre actualizado",
+	"org.deleted":          "Organización borrada",
+	"org.member_saved":     "Miembro guardado",
+	"org.member_removed":   "Miembro quitado",
+	"org.left":             "Saliste de la organización",
+	"org.title":            "Organizaciones",
+	"org.new":              "Nueva organización",
+	"org.help":             "Los dueños y editores de una organización administran juntos sus encuestas.",
+	"org.name":             "Nombre",
+	"org.name_placeholder": "Mi equipo",
+	"org.create":           "Crear",
+	"org.empty":            "Todavía no eres miembro de ninguna organización.",
+	"org.back":             "Volver a organizaciones",
+	"org.members":          "Miembros",
+	"org.username":         "Usuario",
+	"org.role":             "Rol",
+	"org.role_help":        "Dueño: administra miembros y encuestas. Editor: crea y administra encuestas. Lector: ve y vota.",
+	"org.add_member":       "Agregar o cambiar rol",
+	"org.delete":           "Borrar organización",
+	"org.delete_confirm":   "¿Borrar la organización? Sus encuestas quedan como encuestas de quien las creó.",
+	"org.polls":            "Encuestas de la organización",
+	"org.polls_help":       "Crea encuestas para la organización eligiéndola en",
+	"org.rename":           "Cambiar nombre",
+	"org.your_role":        "Tu rol: %s",
+	"org.leave":            "Salir",
+	"org.leave_confirm":    "¿Salir de la organización?",
+	"org.remove":           "Quitar",
+	"org.remove_confirm":   "¿Quitar a %s de la organización?",
+
+	"org_role.owner":  "Dueño",
+	"org_role.editor": "Editor",
+	"org_role.viewer": "Lector",
+
+	"outcome.winner":      "Ganadora: %s",
+	"outcome.decided_on":  "Decidida el %s",
+	"outcome.no_decision": "Sin decisión: %s",
+	"outcome.no_quorum":   "no se alcanzó el quórum",
+	"outcome.no_majority": "ninguna opción superó la mayoría requerida",
+	"outcome.tie":         "hubo un empate",
+
+	"poll.not_found_or_not_owner":     "la encuesta no existe o no es tuya",
+	"poll.still_open":                 "la votación sigue abierta",
+	"poll.close_not_allowed":          "la encuesta no existe, no es tuya o ya está cerrada",
+	"poll.question_required":          "la pregunta no puede estar vacía",
+	"poll.min_options":                "deben ser al menos 2 opciones",
+	"poll.max_options":                "deben ser máximo 4 opciones",
+	"poll.invalid_results_visibility": "visibilidad de resultados inválida",
+	"poll.closes_at_past":             "la fecha de cierre debe ser futura",
+	"poll.blocked_words":              "la encuesta contiene palabras no permitidas",
+	"poll.unavailable":                "la encuesta no está disponible",
+	"poll.voting_closed":              "la votación está cerrada",
+	"poll.min_options_remaining":      "la encuesta debe tener al menos 2 opciones",
+	"poll.not_found":                  "encuesta no encontrada",
+	"poll.back":                       "Volver a Encuestas",
+	"poll.total_votes":                "Total de votos:",
+	"poll.closed":                     "Votación cerrada",
+	"poll.closes_on":                  "Cierra el %s",
+	"poll.computing_outcome":          "Calculando resultado…",
+	"poll.your_vote":                  "Tu voto",
+	"poll.option_votes":               "%d votos (%.1f%%)",
+	"poll.change_vote":                "Cambiar voto",
+	"poll.close":                      "Cerrar votación",
+	"poll.close_confirm":              "¿Cerrar la votación ahora? Se calculará el resultado y no se podrá volver a abrir.",
+	"poll.login_link":                 "Inicia sesión",
+	"poll.login_to_vote":              "para votar.",
+	"poll.invalid_closes_at":          "Fecha de cierre inválida",
+	"poll.rules_not_integer":          "Las reglas de decisión deben ser números enteros",
+	"poll.created":                    "Encuesta creada correctamente",
+	"poll.deleted":                    "Encuesta eliminada correctamente",
+	"poll.login_required":             "Debes iniciar sesión para votar",
+	"poll.option_updated":             "Opción actualizada correctamente",
+	"poll.option_deleted":             "Opción eliminada correctamente",
+	"poll.max_options_toast":          "Máximo 4 opciones permitidas",
+	"poll.not_owner":                  "no puedes administrar esta encuesta",
+	"poll.title_taken":                "ya tienes una encuesta con esa pregunta",
+	"poll.copy_title":                 "%s (copia)",
+	"poll.already_published":          "la encuesta ya está publicada",
+	"poll.not_scheduled":              "la encuesta no tiene una publicación programada",
+	"poll.not_published":              "la encuesta todavía no se publicó",
+	"poll.closes_before_publish":      "la fecha de cierre debe ser posterior a la de publicación",
+	"poll.invalid_publish_at":         "Fecha de publicación inválida",
+	"poll.draft_saved":                "Borrador guardado",
+	"poll.scheduled":                  "Encuesta programada para el %s",
+	"poll.published":                  "Encuesta publicada",
+	"poll.unscheduled":                "Se canceló la publicación programada",
+	"poll.members_only":               "Solo votan los miembros de %s",
+	"poll.members_only_notice":        "Encuesta solo para miembros de %s",
+	"poll.members_only_without_org":   "solo para miembros requiere elegir una organización",
+	"poll.org_not_allowed":            "no puedes crear encuestas en esa organización",
+
+	"polls.system_title":            "Encuestas del Sistema",
+	"polls.mine_title":              "Mis Encuestas",
+	"polls.list_title":              "Lista de Encuestas",
+	"polls.create_title":            "Crear Encuesta",
+	"polls.create_help":             "Añade una nueva encuesta.",
+	"polls.question_placeholder":    "¿Pregunta?",
+	"polls.question":                "Pregunta",
+	"polls.add_option":              "Agregar opción",
+	"polls.results_visibility":      "Mostrar resultados",
+	"polls.closes_at":               "Cierre de la votación (opcional)",
+	"polls.rules":                   "Reglas de decisión (opcional)",
+	"polls.quorum_votes":            "Quórum (votos mínimos)",
+	"polls.invited_voters":          "Votantes invitados",
+	"polls.quorum_percent":          "Quórum (% de invitados)",
+	"polls.majority_percent":        "Mayoría (% a superar)",
+	"polls.majority_placeholder":    "0 = la más votada",
+	"polls.tie_break":               "En caso de empate",
+	"polls.create":                  "Crear Encuesta",
+	"polls.option":                  "Opción",
+	"polls.option_placeholder":      "Opción...",
+	"polls.empty":                   "No hay encuestas creadas aún.",
+	"polls.voted":                   "Votado",
+	"polls.delete":                  "Eliminar encuesta",
+	"polls.tag_title":               "Encuestas con #%s",
+	"polls.clear_tag":               "Quitar filtro de etiqueta",
+	"polls.browse_tags":             "Ver etiquetas",
+	"polls.search_placeholder":      "Buscar encuestas…",
+	"polls.status_all":              "Todas",
+	"polls.status_open":             "Abiertas",
+	"polls.status_closed":           "Cerradas",
+	"polls.tags":                    "Etiquetas",
+	"polls.tags_placeholder":        "deportes, comida",
+	"polls.tags_help":               "Separadas por comas, hasta 5.",
+	"polls.duplicate":               "Duplicar",
+	"polls.save_as_template":        "Guardar como plantilla",
+	"polls.template_help":           "En el título puedes usar {{date}}, {{week}}, {{month}} y {{year}}: se reemplazan al crear la encuesta.",
+	"polls.from_template":           "Partir de una plantilla",
+	"polls.blank_form":              "Encuesta en blanco",
+	"polls.template_delete":         "Borrar plantilla",
+	"polls.template_delete_confirm": "¿Borrar esta plantilla?",
+	"polls.save_draft":              "Guardar borrador",
+	"polls.publish_at":              "Publicar el (opcional)",
+	"polls.publish_at_help":         "Vacío para publicarla al crearla",
+	"polls.draft_badge":             "Borrador",
+	"polls.scheduled_badge":         "Se publica el %s",
+	"polls.edit_draft":              "Editar borrador",
+	"polls.edit_draft_title":        "Editar borrador",
+	"polls.edit_draft_help":         "Nadie más la ve hasta que la publiques",
+	"polls.cancel_edit":             "Cancelar",
+	"polls.org":                     "Organización",
+	"polls.personal":                "Personal (sin organización)",
+	"polls.members_only":            "Solo votan los miembros de la organización",
+
+	"publish.draft":          "Borrador: solo tú puedes verla",
+	"publish.scheduled_for":  "Se publica automáticamente el %s",
+	"publish.preview":        "Vista previa",
+	"publish.now":            "Publicar ahora",
+	"publish.now_confirm":    "¿Publicar la encuesta ahora? Después no se podrá editar.",
+	"publish.schedule_at":    "Programar para",
+	"publish.schedule":       "Programar",
+	"publish.unschedule":     "Cancelar programación",
+	"publish.back_to_draft":  "Volver al borrador",
+	"publish.preview_banner": "Vista previa: así verán la encuesta quienes voten",
+
+	"register.title":       "Crear Cuenta",
+	"register.has_account": "¿Ya tienes una cuenta?",
+	"register.login_link":  "Inicia sesión aquí",
+	"register.submit":      "Registrarse",
+
+	"report.already_reviewed": "el reporte ya fue revisado",
+	"report.invalid_reason":   "motivo de reporte inválido",
+	"report.details_too_long": "los detalles no pueden superar los %d caracteres",
+	"report.own_poll":         "no puedes reportar tu propia encuesta",
+	"report.duplicate":        "ya reportaste esta encuesta",
+	"report.toggle":           "Reportar esta encuesta",
+	"report.reason":           "Motivo",
+	"report.details":          "Detalles (opcional)",
+	"report.submit":           "Enviar reporte",
+	"report.thanks":           "Gracias, un moderador revisará la encuesta",
+
+	"report_reason.spam":       "Spam",
+	"report_reason.offensive":  "Contenido ofensivo",
+	"report_reason.misleading": "Engañosa",
+	"report_reason.filtered":   "Filtro automático",
+	"report_reason.other":      "Otro",
+
+	"results.after_vote":         "Después de votar",
+	"results.after_close":        "Al cerrar la votación",
+	"results.owner_only":         "Solo para mí",
+	"results.always":             "Siempre",
+	"results.hidden_after_vote":  "Los resultados se muestran después de votar.",
+	"results.hidden_after_close": "Los resultados se muestran al cerrar la votación.",
+	"results.hidden_owner_only":  "Solo el creador de la encuesta puede ver los resultados.",
+
+	"role.admin":     "Administrador",
+	"role.moderator": "Moderador",
+	"role.user":      "Usuario",
+
+	"rules.invalid_tie_break":       "política de desempate inválida",
+	"rules.quorum_min":              "el quórum debe ser de al menos 1 voto",
+	"rules.quorum_percent_range":    "el quórum debe estar entre 1% y 100%",
+	"rules.invited_voters_required": "indica cuántos votantes están invitados para usar un quórum porcentual",
+	"rules.majority_range":          "la mayoría debe estar entre 0% y 99%",
+
+	"series.not_found":          "serie no encontrada",
+	"series.invalid_id":         "ID de serie inválido",
+	"series.starts_at_required": "indica cuándo empieza la serie",
+	"series.invalid_starts_at":  "Fecha de inicio inválida",
+	"series.weekdays_required":  "elige al menos un día de la semana",
+	"series.invalid_weekday":    "día de la semana inválido",
+	"series.invalid_cron":       "la expresión cron debe tener cinco campos válidos: minuto hora día mes día-de-semana",
+	"series.cron_never":         "la expresión cron nunca se cumple",
+	"series.invalid_frequency":  "frecuencia inválida",
+	"series.never_runs":         "la serie no tiene ninguna ocurrencia futura",
+	"series.instance_title":     "%s (%s)",
+	"series.created":            "Serie creada: la primera encuesta se crea el %s",
+	"series.paused":             "Serie pausada",
+	"series.resumed":            "Serie reanudada",
+	"series.deleted":            "Serie eliminada",
+	"series.title":              "Series recurrentes",
+	"series.new":                "Nueva serie",
+	"series.help":               "Cada vez que toca se crea una encuesta nueva desde la plantilla y se cierra la anterior.",
+	"series.no_templates":       "Para crear una serie primero guarda una plantilla en",
+	"series.template":           "Plantilla",
+	"series.frequency":          "Frecuencia",
+	"series.starts_at":          "Empieza el",
+	"series.weekdays":           "Días (solo semanal)",
+	"series.cron":               "Expresión cron (solo cron)",
+	"series.cron_help":          "minuto hora día mes día-de-semana, en la hora del servidor",
+	"series.create":             "Crear serie",
+	"series.empty":              "Todavía no tienes series recurrentes",
+	"series.active":             "Activa",
+	"series.paused_badge":       "Pausada",
+	"series.next_run":           "Próxima encuesta: %s",
+	"series.view":               "Ver encuestas y tendencia",
+	"series.pause":              "Pausar",
+	"series.resume":             "Reanudar",
+	"series.delete":             "Eliminar",
+	"series.delete_confirm":     "¿Eliminar la serie? Las encuestas ya creadas se conservan.",
+	"series.back":               "Volver a las series",
+	"series.no_instances":       "La serie todavía no creó ninguna encuesta",
+	"series.trend":              "Resultados por encuesta",
+	"series.instance":           "Encuesta",
+	"series.votes":              "Votos",
+	"series.open":               "abierta",
+	"series.part_of":            "Parte de una serie recurrente",
+	"series.frequency_daily":    "Diaria",
+	"series.frequency_weekly":   "Semanal",
+	"series.frequency_monthly":  "Mensual",
+	"series.frequency_cron":     "Cron",
+	"series.rule_daily":         "Todos los días a las %s",
+	"series.rule_weekly":        "Cada semana (%s) a las %s",
+	"series.rule_monthly":       "El día %d de cada mes a las %s",
+	"series.rule_cron":          "Cron: %s",
+
+	"slack.invalid_link":   "el enlace de vinculación no es válido o expiró",
+	"slack.link_title":     "Vincular Slack",
+	"slack.link_retry":     "Vuelve a usar el comando en Slack para recibir un enlace nuevo.",
+	"slack.link_intro":     "Vas a vincular el usuario de Slack %s (equipo %s) con tu cuenta de webpolls.",
+	"slack.link_help":      "Las encuestas que crees y los votos que emitas desde Slack quedarán a tu nombre.",
+	"slack.link_submit":    "Vincular cuenta",
+	"slack.linked":         "Listo, tu usuario de Slack quedó vinculado. Ya puedes volver a Slack.",
+	"slack.create_failed":  "No se pudo crear la encuesta: %s",
+	"slack.vote_failed":    "No se pudo registrar tu voto: %s",
+	"slack.usage":          "Uso: `%s \"¿Pregunta?\" \"Opción 1\" \"Opción 2\"` (de 2 a 4 opciones, cada una entre comillas)",
+	"slack.link_prompt":    "Primero vincula tu usuario de Slack con tu cuenta de webpolls.",
+	"slack.view_poll":      "Ver en webpolls",
+	"slack.votes":          "%d votos",
+	"slack.invalid_vote":   "valor de voto inválido",
+	"slack.unknown_action": "acción de Slack desconocida: %q",
+	"slack.error":          "Ocurrió un error, inténtalo de nuevo.",
+	"slack.action_failed":  "No se pudo procesar la acción.",
+
+	"tag.too_long": "la etiqueta \"%s\" supera los %d caracteres",
+	"tag.invalid":  "la etiqueta \"%s\" solo puede tener letras, números y guiones",
+	"tag.too_many": "máximo %d etiquetas por encuesta",
+
+	"tags.title":      "Etiquetas",
+	"tags.help":       "Las etiquetas más usadas en las encuestas. Elige una para ver sus encuestas.",
+	"tags.empty":      "Todavía no hay encuestas con etiquetas",
+	"tags.poll_count": "%d encuestas",
+
+	"template.not_found":  "plantilla no encontrada",
+	"template.invalid_id": "ID de plantilla inválido",
+	"template.saved":      "Plantilla \"%s\" guardada",
+	"template.deleted":    "Plantilla borrada",
+	"template.in_use":     "la plantilla la usa una serie recurrente; borra la serie primero",
+
+	"tie_break.first_option": "Gana la primera opción",
+	"tie_break.random":       "Sorteo entre las empatadas",
+	"tie_break.no_decision":  "Sin decisión",
+
+	"time.one_second": "1 segundo",
+	"time.seconds":    "%d segundos",
+	"time.one_minute": "1 minuto",
+	"time.minutes":    "%d minutos",
+
+	"title.security":           "Seguridad - Webpolls",
+	"title.admin":              "Administración - Webpolls",
+	"title.my_polls":           "Mis Polls - Webpolls",
+	"title.poll":               "Encuesta - Webpolls",
+	"title.polls":              "Webpolls - Polls",
+	"title.login":              "Iniciar Sesión - Webpolls",
+	"title.two_factor":         "Verificación - Webpolls",
+	"title.register":           "Registrarse - Webpolls",
+	"title.webhooks":           "Webhooks - Webpolls",
+	"title.slack_link":         "Vincular Slack - Webpolls",
+	"title.home":               "Webpolls",
+	"title.webhook_deliveries": "Entregas de %s - Webpolls",
+	"title.tags":               "Etiquetas - Webpolls",
+	"title.notifications":      "Notificaciones - Webpolls",
+	"title.poll_preview":       "Vista previa - Webpolls",
+	"title.series":             "Series recurrentes - Webpolls",
+	"title.series_detail":      "%s - Webpolls",
+	"title.orgs":               "Organizaciones - Webpolls",
+	"title.org":                "%s - Webpolls",
+
+	"twofactor.invalid_code":      "código de verificación inválido",
+	"twofactor.not_enabled":       "la verificación en dos pasos no está activada",
+	"twofactor.login_title":       "Verificación en dos pasos",
+	"twofactor.not_you":           "¿No eres tú?",
+	"twofactor.back_to_login":     "Volver al inicio de sesión",
+	"twofactor.login_help":        "Ingresa el código de 6 dígitos de tu aplicación de autenticación o uno de tus códigos de recuperación.",
+	"twofactor.code":              "Código",
+	"twofactor.verify":            "Verificar",
+	"twofactor.title":             "Verificación en dos pasos",
+	"twofactor.enabled":           "Activada",
+	"twofactor.disabled":          "Desactivada",
+	"twofactor.description":       "Además de tu contraseña, te pediremos un código de tu aplicación de autenticación al iniciar sesión.",
+	"twofactor.remaining_codes":   "Te quedan %d códigos de recuperación sin usar.",
+	"twofactor.regenerate_title":  "Regenerar códigos de recuperación",
+	"twofactor.regenerate":        "Regenerar códigos",
+	"twofactor.disable_title":     "Desactivar verificación en dos pasos",
+	"twofactor.disable":           "Desactivar",
+	"twofactor.enable":            "Activar verificación en dos pasos",
+	"twofactor.setup_title":       "Configura tu aplicación de autenticación",
+	"twofactor.setup_help":        "Escanea el código QR con Google Authenticator, 1Password, Authy o similar y luego ingresa el código que te muestre.",
+	"twofactor.qr_alt":            "Código QR para la aplicación de autenticación",
+	"twofactor.manual_key":        "¿No puedes escanearlo? Ingresa esta clave manualmente:",
+	"twofactor.verification_code": "Código de verificación",
+	"twofactor.confirm":           "Confirmar y activar",
+	"twofactor.recovery_title":    "Códigos de recuperación",
+	"twofactor.recovery_help":     "Guárdalos en un lugar seguro. Cada código sirve una sola vez si pierdes acceso a tu aplicación de autenticación. No los volveremos a mostrar.",
+	"twofactor.recovery_done":     "Listo, ya los guardé",
+	"twofactor.setup_failed":      "No se pudo iniciar la configuración",
+	"twofactor.setup_expired":     "La configuración expiró, vuelve a empezar",
+	"twofactor.enable_failed":     "No se pudo activar la verificación en dos pasos",
+	"twofactor.enabled_toast":     "Verificación en dos pasos activada",
+	"twofactor.disabled_toast":    "Verificación en dos pasos desactivada",
+	"twofactor.codes_regenerated": "Códigos de recuperación regenerados",
+	"twofactor.code_reused":       "ese código ya se usó, espera a que tu aplicación muestre el siguiente",
+
+	"user.fields_required": "todos los campos son obligatorios",
+	"user.username_taken":  "el nombre de usuario ya existe",
+	"user.email_taken":     "el email ya existe",
+	"user.not_found":       "usuario no encontrado",
+	"user.deleted":         "Usuario eliminado correctamente",
+	"user.fetched":         "Usuario obtenido correctamente",
+	"user.updated":         "Usuario actualizado correctamente",
+
+	"webhook.invalid_url":       "la URL debe ser http:// o https://",
+	"webhook.invalid_event":     "evento %q inválido",
+	"webhook.events_required":   "elige al menos un evento",
+	"webhook.limit":             "no puedes tener más de %d webhooks",
+	"webhook.not_found":         "webhook no encontrado",
+	"webhook.test_queued":       "Evento de prueba encolado",
+	"webhook.created":           "Webhook creado",
+	"webhook.deleted":           "Webhook eliminado",
+	"webhook.blocked_url":       "la URL no puede apuntar a una dirección local, privada o reservada",
+	"webhook.unresolvable_host": "no se pudo resolver %s",
+
+	"webhook_event.poll_created": "Encuesta creada",
+	"webhook_event.poll_voted":   "Nuevo voto",
+	"webhook_event.poll_closed":  "Votación cerrada",
+	"webhook_event.poll_deleted": "Encuesta eliminada",
+
+	"webhooks.title":            "Webhooks",
+	"webhooks.new":              "Nuevo webhook",
+	"webhooks.help":             "Enviaremos un POST con JSON a la URL cuando ocurra alguno de los eventos elegidos en tus encuestas. Cada entrega va firmada con HMAC-SHA256 en el header %s.",
+	"webhooks.url":              "URL",
+	"webhooks.url_placeholder":  "https://ejemplo.com/webhooks/webpolls",
+	"webhooks.events":           "Eventos",
+	"webhooks.create":           "Crear webhook",
+	"webhooks.empty":            "Todavía no tienes webhooks.",
+	"webhooks.show_secret":      "Ver secreto de firma",
+	"webhooks.view_deliveries":  "Ver entregas",
+	"webhooks.send_test":        "Enviar evento de prueba",
+	"webhooks.delete":           "Eliminar",
+	"webhooks.delete_confirm":   "¿Eliminar este webhook? Se perderá su registro de entregas.",
+	"webhooks.back":             "Volver a Webhooks",
+	"webhooks.deliveries_title": "Entregas",
+	"webhooks.refresh":          "Actualizar",
+	"webhooks.col_event":        "Evento",
+	"webhooks.col_status":       "Estado",
+	"webhooks.col_attempts":     "Intentos",
+	"webhooks.col_response":     "Respuesta",
+	"webhooks.col_created":      "Creada",
+	"webhooks.no_deliveries":    "Sin entregas todavía.",
+	"webhooks.delivered":        "Entregada",
+	"webhooks.failed":           "Fallida",
+	"webhooks.pending":          "Pendiente",
+	"webhooks.next_attempt":     "Próximo intento: %s",
+	"webhooks.http_status":      "HTTP %d",
+
+	"weekday.0": "dom",
+	"weekday.1": "lun",
+	"weekday.2": "mar",
+	"weekday.3": "mié",
+	"weekday.4": "jue",
+	"weekday.5": "vie",
+	"weekday.6": "sáb",
+}
@@ -0,0 +1,137 @@
+// Package i18n traduce los textos de la interfaz y los errores de los servicios.
+//
+// Los servicios devuelven *Error con un código del catálogo; el handler lo traduce al
+// idioma de la petición con Message. Las vistas usan T con el contexto del render.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Idiomas soportados. El español es el idioma por defecto y el de respaldo del catálogo.
+const (
+	ES = "es"
+	EN = "en"
+
+	Default = ES
+)
+
+// Locales son los idiomas en el orden en que se ofrecen en la interfaz.
+var Locales = []string{ES, EN}
+
+// CookieName es la cookie donde se guarda el idioma elegido.
+const CookieName = "lang"
+
+var catalogs = map[string]map[string]string{
+	ES: es,
+	EN: en,
+}
+
+type localeKey struct{}
+
+// WithLocale guarda el idioma de la petición en el contexto.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale devuelve el idioma guardado en el contexto, o el idioma por defecto.
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return Default
+}
+
+// IsSupported indica si hay catálogo para el idioma.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// T traduce key al idioma del contexto. Con args, el texto se usa como formato de fmt.
+func T(ctx context.Context, key string, args ...any) string {
+	return Translate(Locale(ctx), key, args...)
+}
+
+// Translate traduce key al idioma indicado. Si falta en ese catálogo usa el español,
+// y si tampoco está devuelve la clave para que se note en la interfaz.
+func Translate(locale, key string, args ...any) string {
+	text, ok := catalogs[locale][key]
+	if !ok {
+		text, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Negotiate elige el idioma soportado con mayor peso de una cabecera Accept-Language.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if IsSupported(base) && q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// Error es un error de dominio identificado por un código del catálogo. Error() devuelve
+// el texto en español para los logs; los handlers lo traducen con Message.
+type Error struct {
+	Code string
+	Args []any
+}
+
+// NewError crea un error con el código y los argumentos del mensaje.
+func NewError(code string, args ...any) *Error {
+	return &Error{Code: code, Args: args}
+}
+
+func (e *Error) Error() string {
+	return Translate(Default, e.Code, e.Args...)
+}
+
+// Is compara por código, así los errores con argumentos se pueden comparar con errors.Is.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Message traduce err al idioma del contexto. Los errores sin código se devuelven tal cual.
+func Message(ctx context.Context, err error) string {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return T(ctx, coded.Code, coded.Args...)
+	}
+	return err.Error()
+}
+
+// Date formatea una fecha con el formato corto del idioma del contexto.
+func Date(ctx context.Context, t time.Time) string {
+	return t.Local().Format(T(ctx, "format.date"))
+}
+
+// DateTime formatea fecha y hora con el formato del idioma del contexto.
+func DateTime(ctx context.Context, t time.Time) string {
+	return t.Local().Format(T(ctx, "format.datetime"))
+}
@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ES},
+		{"en", EN},
+		{"en-US,en;q=0.9", EN},
+		{"fr-FR,fr;q=0.9,en;q=0.8", EN},
+		{"en;q=0.5,es;q=0.8", ES},
+		{"de,fr", ES},
+		{"EN-gb", EN},
+		{"en;q=abc,es;q=0.1", ES},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		locale string
+		key    string
+		args   []any
+		want   string
+	}{
+		{EN, "error.not_found", nil, "Not found"},
+		{ES, "error.not_found", nil, es["error.not_found"]},
+		{"fr", "error.not_found", nil, es["error.not_found"]},
+		{EN, "no.existe", nil, "no.existe"},
+		{EN, "poll.option_votes", []any{3, 50.0}, "3 votes (50.0%)"},
+	}
+	for _, tt := range tests {
+		if got := Translate(tt.locale, tt.key, tt.args...); got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+}
+
+// Todas las claves del catálogo en español tienen que estar traducidas al inglés.
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for key := range es {
+		if _, ok := en[key]; !ok {
+			t.Errorf("falta %q en inglés", key)
+		}
+	}
+	for key := range en {
+		if _, ok := es[key]; !ok {
+			t.Errorf("falta %q en español", key)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	ctx := WithLocale(context.Background(), EN)
+	wrapped := fmt.Errorf("guardando: %w", NewError("option.duplicate", "Go"))
+	if got, want := Message(ctx, wrapped), Translate(EN, "option.duplicate", "Go"); got != want {
+		t.Errorf("Message = %q, want %q", got, want)
+	}
+	if got := Message(ctx, errors.New("sin código")); got != "sin código" {
+		t.Errorf("Message sin código = %q", got)
+	}
+	if !errors.Is(wrapped, NewError("option.duplicate", "Rust")) {
+		t.Error("errors.Is debería comparar solo el código")
+	}
+}
@@ -1,8 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"webpolls/config"
 	"webpolls/db"
 	"webpolls/handlers"
 	"webpolls/middleware"
@@ -10,15 +21,43 @@ import (
 	"webpolls/utils"
 
 	sqlc "webpolls/db/sqlc"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 func main() {
+	// Configuración: flags, variables de entorno y archivo (ver config.Load)
+	cfg, err := config.Load(os.Args[1:], os.Stderr)
+	if errors.Is(err, config.ErrPrintConfig) {
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "configuración inválida:\n%v\n", err)
+		}
+		os.Exit(2)
+	}
+
+	// Logs estructurados; el paquete log también pasa por slog
+	utils.InitLogger(cfg.Log.Format, cfg.Log.Level)
+	slog.Info("configuración cargada", "profile", cfg.Profile, "config", cfg)
+
+	// Tracing con OpenTelemetry
+	shutdownTracing, err := utils.InitTracing(context.Background(), cfg.Tracing.Exporter)
+	if err != nil {
+		log.Fatalf("Error iniciando tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// inicio la conexion a la BD
-	dbConn := db.InitDB()
+	dbConn := db.InitDB(cfg.Database.ConnString())
 	defer dbConn.Close()
 
 	// Inicializar Session Store
-	utils.InitSessionStore()
+	utils.InitSessionStore(cfg.Session.Key, cfg.Session.Secure)
+	utils.SetTrustProxy(cfg.TrustProxy)
 
 	// Inyección de dependencias
 	queries := sqlc.New(dbConn)
@@ -26,21 +65,115 @@ func main() {
 	// Inicializar servicios
 	userService := services.NewUserService(queries)
 	pollService := services.NewPollService(queries, dbConn)
+	twoFactorService := services.NewTwoFactorService(queries, dbConn)
+	adminService := services.NewAdminService(queries, dbConn)
+	webhookService := services.NewWebhookService(queries, dbConn)
+	orgService := services.NewOrganizationService(queries, dbConn)
+	pollService.Webhooks = webhookService
+	adminService.Webhooks = webhookService
+	slackService := services.NewSlackService(queries, pollService, cfg.Slack.SigningSecret, cfg.BaseURL())
+	reportService := services.NewReportService(queries, dbConn, cfg.Moderation.ReportAutoHideThreshold)
+	pollService.Reports = reportService
+	pollService.Filter = contentFilter(cfg.Moderation)
+	commentService := services.NewCommentService(queries, pollService)
+	commentService.Filter = pollService.Filter
 	sseBroker := services.NewSSEBroker()
+	notificationService := services.NewNotificationService(queries, sseBroker)
+	notificationService.Mailer = mailer(cfg.Mail)
+	notificationService.BaseURL = cfg.BaseURL()
+	pollService.Notifications = notificationService
+	commentService.Notifications = notificationService
+
+	// Los middlewares de auth revisan rol y suspensión en cada petición
+	middleware.SetUserStatusFunc(userService.GetUserStatus)
+
+	// Primer administrador: se promueve la cuenta de ADMIN_EMAIL al arrancar
+	if adminEmail := cfg.AdminEmail; adminEmail != "" {
+		promoted, err := userService.EnsureAdmin(context.Background(), adminEmail)
+		if err != nil {
+			slog.Error("error promoviendo administrador", "email", adminEmail, "error", err)
+		} else if !promoted {
+			slog.Warn("ADMIN_EMAIL no corresponde a ningún usuario registrado", "email", adminEmail)
+		}
+	}
+
+	// Rate limiting: en memoria para una réplica, en Postgres para varias
+	var rateLimitStore services.RateLimitStore
+	if cfg.RateLimit.Store == "postgres" {
+		rateLimitStore = services.NewPostgresRateLimitStore(queries, dbConn)
+	} else {
+		rateLimitStore = services.NewMemoryRateLimitStore()
+	}
+	loginLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("login-ip", cfg.RateLimit.Login, middleware.KeyByIP),
+	)
+	lockoutPolicy := services.LockoutPolicy{
+		Threshold:  cfg.Lockout.Threshold,
+		BaseDelay:  cfg.Lockout.BaseDelay,
+		MaxDelay:   cfg.Lockout.MaxDelay,
+		ResetAfter: cfg.Lockout.ResetAfter,
+	}
+	// Por cuenta, sin importar la IP: más permisivo para que un tercero no pueda dejar afuera
+	// al dueño de la cuenta con pocos intentos
+	accountLockoutPolicy := services.LockoutPolicy{
+		Threshold:  cfg.Lockout.AccountThreshold,
+		BaseDelay:  cfg.Lockout.AccountBaseDelay,
+		MaxDelay:   cfg.Lockout.AccountMaxDelay,
+		ResetAfter: cfg.Lockout.ResetAfter,
+	}
+	loginLockout := middleware.LoginLockout(rateLimitStore, lockoutPolicy, middleware.KeyByLoginEmail)
+	accountLockout := middleware.LoginLockout(rateLimitStore, accountLockoutPolicy, middleware.KeyByLoginAccount)
+	twoFactorLockout := middleware.LoginLockout(rateLimitStore, lockoutPolicy, middleware.KeyByPendingTwoFactor)
+	accountLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("account-user", cfg.RateLimit.Account, middleware.KeyByUser),
+	)
+	registerLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("register-ip", cfg.RateLimit.Register, middleware.KeyByIP),
+	)
+	voteLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("vote-ip", cfg.RateLimit.VoteIP, middleware.KeyByIP),
+		rateLimitPolicy("vote-user", cfg.RateLimit.VoteUser, middleware.KeyByUser),
+	)
+	createPollLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("create-poll-user", cfg.RateLimit.CreatePoll, middleware.KeyByUser),
+	)
+	reportLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("report-user", cfg.RateLimit.Report, middleware.KeyByUser),
+	)
+	commentLimit := middleware.RateLimit(rateLimitStore,
+		rateLimitPolicy("comment-user", cfg.RateLimit.Comment, middleware.KeyByUser),
+	)
 
 	// Inicializar handlers con los servicios
-	userHandler := handlers.NewUserHandler(userService)
+	userHandler := handlers.NewUserHandler(userService, twoFactorService)
+	accountHandler := handlers.NewAccountHandler(twoFactorService)
 	pollHandler := handlers.NewPollHandler(pollService, sseBroker)
+	pollHandler.BaseURL = cfg.BaseURL()
+	embedHandler := handlers.NewEmbedHandler(pollService, cfg.BaseURL())
+	tagHandler := handlers.NewTagHandler(pollService)
+	templateHandler := handlers.NewTemplateHandler(pollService)
+	seriesHandler := handlers.NewSeriesHandler(pollService)
+	orgHandler := handlers.NewOrgHandler(orgService, pollService)
 	homeHandler := handlers.NewHomeHandler(userService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	commentHandler := handlers.NewCommentHandler(commentService, sseBroker)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	healthHandler := handlers.NewHealthHandler(dbConn)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	slackHandler := handlers.NewSlackHandler(slackService)
+
+	staffOnly := middleware.RequireRole(services.RoleModerator, services.RoleAdmin)
+	adminOnly := middleware.RequireRole(services.RoleAdmin)
 
 	// Crear un nuevo mux y registrar todas las rutas
 	mux := http.NewServeMux()
 
 	// Rutas de home
-	mux.HandleFunc("GET /{$}", homeHandler.GetHome)
+	mux.Handle("GET /{$}", middleware.OptionalAuthMiddleware(http.HandlerFunc(homeHandler.GetHome)))
 
 	// Rutas de usuarios
-	mux.HandleFunc("POST /users/create", userHandler.CreateUser)
+	mux.Handle("POST /users/create", registerLimit(http.HandlerFunc(userHandler.CreateUser)))
 	// mux.HandleFunc("GET /users/{id}", userHandler.GetUser)
 	// mux.HandleFunc("DELETE /users/{id}", userHandler.DeleteUser)
 	// mux.HandleFunc("PUT /users/{id}", userHandler.UpdateUser)
@@ -48,29 +181,228 @@ func main() {
 
 	// Auth routes
 	mux.HandleFunc("GET /login", userHandler.GetLogin)
-	mux.HandleFunc("POST /login", userHandler.PostLogin)
+	mux.Handle("POST /login", loginLimit(accountLockout(loginLockout(http.HandlerFunc(userHandler.PostLogin)))))
+	mux.HandleFunc("GET /login/2fa", userHandler.GetTwoFactorLogin)
+	mux.Handle("POST /login/2fa", loginLimit(twoFactorLockout(http.HandlerFunc(userHandler.PostTwoFactorLogin))))
 	mux.HandleFunc("GET /register", userHandler.GetRegister)
-	mux.HandleFunc("/logout", userHandler.Logout)
+	mux.HandleFunc("POST /logout", userHandler.Logout)
+	mux.Handle("POST /locale", middleware.OptionalAuthMiddleware(http.HandlerFunc(userHandler.SetLocale)))
+
+	// Rutas de cuenta (Protegidas)
+	mux.Handle("GET /account/security", middleware.AuthMiddleware(http.HandlerFunc(accountHandler.GetSecurity)))
+	mux.Handle("POST /account/2fa/setup", middleware.AuthMiddleware(http.HandlerFunc(accountHandler.SetupTwoFactor)))
+	mux.Handle("POST /account/2fa/enable", middleware.AuthMiddleware(accountLimit(http.HandlerFunc(accountHandler.EnableTwoFactor))))
+	mux.Handle("POST /account/2fa/disable", middleware.AuthMiddleware(accountLimit(http.HandlerFunc(accountHandler.DisableTwoFactor))))
+	mux.Handle("POST /account/2fa/recovery-codes", middleware.AuthMiddleware(accountLimit(http.HandlerFunc(accountHandler.RegenerateRecoveryCodes))))
+
+	// Notificaciones del usuario
+	mux.Handle("GET /notifications", middleware.AuthMiddleware(http.HandlerFunc(notificationHandler.GetNotifications)))
+	mux.Handle("GET /notifications/count", middleware.AuthMiddleware(http.HandlerFunc(notificationHandler.GetUnreadCount)))
+	mux.Handle("POST /notifications/read-all", middleware.AuthMiddleware(http.HandlerFunc(notificationHandler.MarkAllRead)))
+	mux.Handle("POST /notifications/{id}/read", middleware.AuthMiddleware(http.HandlerFunc(notificationHandler.MarkRead)))
+	mux.Handle("POST /notifications/preferences", middleware.AuthMiddleware(http.HandlerFunc(notificationHandler.UpdatePreferences)))
+
+	// Webhooks del usuario
+	mux.Handle("GET /account/webhooks", middleware.AuthMiddleware(http.HandlerFunc(webhookHandler.GetWebhooks)))
+	mux.Handle("POST /account/webhooks", middleware.AuthMiddleware(accountLimit(http.HandlerFunc(webhookHandler.CreateWebhook))))
+	mux.Handle("GET /account/webhooks/{id}", middleware.AuthMiddleware(http.HandlerFunc(webhookHandler.GetDeliveries)))
+	mux.Handle("POST /account/webhooks/{id}/delete", middleware.AuthMiddleware(http.HandlerFunc(webhookHandler.DeleteWebhook)))
+	mux.Handle("POST /account/webhooks/{id}/test", middleware.AuthMiddleware(accountLimit(http.HandlerFunc(webhookHandler.SendTestEvent))))
+
+	// Integración con Slack; sin SLACK_SIGNING_SECRET las rutas no se registran
+	if cfg.Slack.SigningSecret != "" {
+		mux.Handle("POST /integrations/slack/commands", slackHandler.VerifySignature(http.HandlerFunc(slackHandler.Command)))
+		mux.Handle("POST /integrations/slack/interactions", slackHandler.VerifySignature(http.HandlerFunc(slackHandler.Interaction)))
+		mux.Handle("GET /integrations/slack/link", middleware.AuthMiddleware(http.HandlerFunc(slackHandler.GetLink)))
+		mux.Handle("POST /integrations/slack/link", middleware.AuthMiddleware(accountLimit(http.HandlerFunc(slackHandler.PostLink))))
+	}
+
+	// Rutas de administración y moderación (solo staff)
+	mux.Handle("GET /admin", http.RedirectHandler("/admin/users", http.StatusSeeOther))
+	mux.Handle("GET /admin/users", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.GetUsers))))
+	mux.Handle("POST /admin/users/{id}/suspend", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.SuspendUser))))
+	mux.Handle("POST /admin/users/{id}/unsuspend", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.UnsuspendUser))))
+	mux.Handle("POST /admin/users/{id}/role", middleware.AuthMiddleware(adminOnly(http.HandlerFunc(adminHandler.SetUserRole))))
+	mux.Handle("GET /admin/polls", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.GetPolls))))
+	mux.Handle("POST /admin/polls/{id}/hide", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.HidePoll))))
+	mux.Handle("POST /admin/polls/{id}/unhide", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.UnhidePoll))))
+	mux.Handle("POST /admin/polls/{id}/delete", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.DeletePoll))))
+	mux.Handle("GET /admin/reports", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.GetReports))))
+	mux.Handle("POST /admin/reports/{id}/resolve", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.ResolveReport))))
+	mux.Handle("GET /admin/audit", middleware.AuthMiddleware(staffOnly(http.HandlerFunc(adminHandler.GetAuditLog))))
 
 	// Rutas de encuestas (Protegidas)
-	mux.Handle("POST /polls/create", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.CreatePoll)))
+	mux.Handle("POST /polls/create", middleware.AuthMiddleware(createPollLimit(http.HandlerFunc(pollHandler.CreatePoll))))
 	mux.Handle("GET /polls/{id}", middleware.OptionalAuthMiddleware(http.HandlerFunc(pollHandler.GetPollPage)))
-	mux.Handle("POST /polls/{id}/vote", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.Vote)))
+	mux.Handle("POST /polls/{id}/close", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.ClosePoll)))
+	mux.Handle("GET /polls/{id}/edit", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.EditDraft)))
+	mux.Handle("POST /polls/{id}/edit", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.UpdateDraft)))
+	mux.Handle("POST /polls/{id}/publish", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.PublishPoll)))
+	mux.Handle("POST /polls/{id}/unschedule", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.UnschedulePoll)))
+	mux.Handle("GET /polls/{id}/preview", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.PreviewPoll)))
+	mux.Handle("POST /polls/{id}/vote", middleware.AuthMiddleware(voteLimit(http.HandlerFunc(pollHandler.Vote))))
 	mux.Handle("DELETE /polls/{id}", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.DeletePoll)))
+	mux.Handle("GET /polls/{id}/embed", middleware.AuthMiddleware(http.HandlerFunc(embedHandler.GetSettings)))
+	mux.Handle("POST /polls/{id}/embed", middleware.AuthMiddleware(http.HandlerFunc(embedHandler.UpdateOrigins)))
+	mux.Handle("POST /polls/{id}/report", middleware.AuthMiddleware(reportLimit(http.HandlerFunc(reportHandler.ReportPoll))))
 	mux.Handle("GET /polls", middleware.OptionalAuthMiddleware(http.HandlerFunc(pollHandler.GetPolls)))
+
+	// Comentarios de las encuestas
+	mux.Handle("GET /polls/{id}/comments", middleware.OptionalAuthMiddleware(http.HandlerFunc(commentHandler.GetComments)))
+	mux.Handle("POST /polls/{id}/comments", middleware.AuthMiddleware(commentLimit(http.HandlerFunc(commentHandler.CreateComment))))
+	mux.Handle("POST /polls/{id}/comments/mode", middleware.AuthMiddleware(http.HandlerFunc(commentHandler.SetCommentsMode)))
+	mux.Handle("POST /comments/{id}/edit", middleware.AuthMiddleware(commentLimit(http.HandlerFunc(commentHandler.UpdateComment))))
+	mux.Handle("POST /comments/{id}/delete", middleware.AuthMiddleware(http.HandlerFunc(commentHandler.DeleteComment)))
+
+	// Plantillas y duplicado: completan el formulario de creación
+	mux.Handle("GET /polls/form", middleware.AuthMiddleware(http.HandlerFunc(templateHandler.GetPollForm)))
+	mux.Handle("GET /polls/{id}/duplicate", middleware.AuthMiddleware(http.HandlerFunc(templateHandler.DuplicatePoll)))
+	mux.Handle("POST /polls/{id}/template", middleware.AuthMiddleware(http.HandlerFunc(templateHandler.SavePollAsTemplate)))
+	mux.Handle("GET /templates", middleware.AuthMiddleware(http.HandlerFunc(templateHandler.GetTemplates)))
+	mux.Handle("POST /templates", middleware.AuthMiddleware(http.HandlerFunc(templateHandler.CreateTemplate)))
+	mux.Handle("POST /templates/{id}/delete", middleware.AuthMiddleware(http.HandlerFunc(templateHandler.DeleteTemplate)))
+
+	// Series de encuestas recurrentes (se crean a partir de una plantilla)
+	mux.Handle("GET /series", middleware.AuthMiddleware(http.HandlerFunc(seriesHandler.GetSeries)))
+	mux.Handle("POST /series", middleware.AuthMiddleware(http.HandlerFunc(seriesHandler.CreateSeries)))
+	mux.Handle("GET /series/{id}", middleware.AuthMiddleware(http.HandlerFunc(seriesHandler.GetSeriesPage)))
+	mux.Handle("POST /series/{id}/pause", middleware.AuthMiddleware(http.HandlerFunc(seriesHandler.PauseSeries)))
+	mux.Handle("POST /series/{id}/resume", middleware.AuthMiddleware(http.HandlerFunc(seriesHandler.ResumeSeries)))
+	mux.Handle("POST /series/{id}/delete", middleware.AuthMiddleware(http.HandlerFunc(seriesHandler.DeleteSeries)))
+
+	// Organizaciones: sus dueños y editores administran las encuestas de la organización
+	mux.Handle("GET /orgs", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.GetOrganizations)))
+	mux.Handle("POST /orgs", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.CreateOrganization)))
+	mux.Handle("GET /orgs/{id}", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.GetOrganization)))
+	mux.Handle("POST /orgs/{id}/rename", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.RenameOrganization)))
+	mux.Handle("POST /orgs/{id}/delete", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.DeleteOrganization)))
+	mux.Handle("POST /orgs/{id}/members", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.SetMember)))
+	mux.Handle("POST /orgs/{id}/members/{user_id}/delete", middleware.AuthMiddleware(http.HandlerFunc(orgHandler.RemoveMember)))
+
+	mux.Handle("GET /tags", middleware.OptionalAuthMiddleware(http.HandlerFunc(tagHandler.GetTags)))
+	mux.HandleFunc("GET /tags/suggest", tagHandler.SuggestTags)
 	mux.Handle("GET /my-polls", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.GetMyPolls))) // New protected route
 	mux.Handle("PUT /options/{id}", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.UpdateOption)))
 	mux.Handle("DELETE /polls/{poll_id}/options/{id}", middleware.AuthMiddleware(http.HandlerFunc(pollHandler.DeleteOption)))
 	mux.HandleFunc("GET /polls/components/option", pollHandler.GetPollOptionInput) // Public? Used in creation form. If creation is protected, this might need to be too, but it's just a fragment.
-	mux.HandleFunc("GET /events", pollHandler.SSE)
+	mux.Handle("GET /events", middleware.OptionalAuthMiddleware(http.HandlerFunc(pollHandler.SSE)))
+
+	// Encuestas embebibles
+	mux.HandleFunc("GET /embed/polls/{id}", embedHandler.Embed)
+	mux.HandleFunc("GET /oembed", embedHandler.OEmbed)
+
+	// Sondas de Kubernetes
+	mux.HandleFunc("GET /healthz", healthHandler.Healthz)
+	mux.HandleFunc("GET /readyz", healthHandler.Readyz)
+
+	// Métricas de Prometheus; METRICS_TOKEN protege el endpoint
+	prometheus.MustRegister(services.NewPoolCollector(dbConn))
+	metricsToken := cfg.Metrics.Token
+	if metricsToken == "" {
+		slog.Warn("METRICS_TOKEN no definido: /metrics queda abierto")
+	}
+	mux.Handle("GET /metrics", middleware.MetricsAuth(metricsToken)(promhttp.Handler()))
 
 	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("./static"))))
 
 	// inicio servidor
-	port := ":8080"
-	log.Println("Servidor corriendo en", port)
-	// Usar el mux envuelto en el middleware
-	if err := http.ListenAndServe(port, mux); err != nil {
+	port := fmt.Sprintf(":%d", cfg.Port)
+	// Usar el mux envuelto en los middlewares; el tracing y el log van por fuera para registrar todo
+	handler := middleware.LoggingMiddleware(middleware.MetricsMiddleware(middleware.Locale(middleware.CSRFMiddleware(middleware.RecordRoutePattern(mux)))))
+	handler = otelhttp.NewHandler(handler, "http.request", otelhttp.WithFilter(func(r *http.Request) bool {
+		return !strings.HasPrefix(r.URL.Path, "/static/") && !isProbePath(r.URL.Path)
+	}))
+	server := &http.Server{Addr: port, Handler: handler}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Decide las encuestas que llegan a su fecha de cierre y anuncia el resultado por SSE
+	go pollService.RunCloser(ctx, 30*time.Second, pollHandler.PublishPollUpdate)
+	// Publica los borradores programados cuando llega su fecha
+	go pollService.RunPublisher(ctx, 30*time.Second)
+	// Crea la próxima encuesta de cada serie recurrente y cierra la anterior
+	go pollService.RunSeries(ctx, time.Minute)
+	// Borra los buckets de rate limit y los fallos de login viejos
+	go rateLimitStore.RunCleanup(ctx)
+	// Entrega los webhooks encolados, con reintentos
+	go webhookService.RunDispatcher(ctx, 5*time.Second)
+	// Manda el resumen diario de notificaciones a quien lo pidió
+	if notificationService.DigestEnabled() {
+		go notificationService.RunDigest(ctx, 10*time.Minute)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("servidor corriendo", "addr", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		log.Fatal("Error al iniciar el servidor:", err)
+	case <-ctx.Done():
+	}
+
+	// Apagado ordenado: /readyz empieza a fallar, los streams SSE se cierran con aviso de
+	// reconexión y se espera a las peticiones en curso. Al salir, los defer cierran el pool y el tracing.
+	slog.Info("apagando servidor")
+	healthHandler.SetDraining()
+	sseBroker.Shutdown()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("error esperando peticiones en curso", "error", err)
+	}
+	slog.Info("servidor detenido")
+}
+
+// isProbePath indica las rutas de sondas y métricas, que no vale la pena trazar.
+func isProbePath(path string) bool {
+	return path == "/metrics" || path == "/healthz" || path == "/readyz"
+}
+
+// rateLimitPolicy arma la política a partir de su límite, ya interpretado por config.
+func rateLimitPolicy(name string, limit config.RateLimit, key middleware.RateLimitKeyFunc) middleware.RateLimitPolicy {
+	return middleware.RateLimitPolicy{Name: name, Rate: limit.Rate, Burst: limit.Burst, Key: key}
+}
+
+// mailer elige cómo se envían los correos según MAILER. Sin mailer no hay resumen por correo.
+func mailer(cfg config.MailConfig) services.Mailer {
+	switch cfg.Mailer {
+	case "smtp":
+		return &services.SMTPMailer{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.From,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		}
+	case "log":
+		return services.LogMailer{}
+	default:
+		return nil
+	}
+}
+
+// contentFilter arma el filtro de palabras prohibidas a partir de PROFANITY_WORDS y/o
+// PROFANITY_WORDS_FILE (una palabra por línea). Sin palabras no hay filtro.
+func contentFilter(cfg config.ModerationConfig) *services.ContentFilter {
+	words := cfg.ProfanityWords
+	if cfg.ProfanityWordsFile != "" {
+		fileWords, err := services.LoadWordList(cfg.ProfanityWordsFile)
+		if err != nil {
+			log.Fatalf("PROFANITY_WORDS_FILE: %v", err)
+		}
+		words = append(words, fileWords...)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	filter, err := services.NewContentFilter(words, cfg.ProfanityMode)
+	if err != nil {
+		log.Fatalf("PROFANITY_MODE: %v", err)
 	}
+	return filter
 }
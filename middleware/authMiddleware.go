@@ -16,7 +16,13 @@ const (
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		session := utils.GetSession(r)
-		if auth, ok := session.Values["authenticated"].(bool); !ok || !auth {
+		auth, _ := session.Values["authenticated"].(bool)
+		var ctx context.Context
+		if auth {
+			// Inject user info into context
+			ctx, auth = withUserStatus(w, r, session)
+		}
+		if !auth {
 			// Check if it's an HTMX request
 			if r.Header.Get("HX-Request") == "true" {
 				w.Header().Set("HX-Redirect", "/login")
@@ -27,10 +33,6 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Inject user info into context
-		ctx := context.WithValue(r.Context(), UserIDKey, session.Values["user_id"])
-		ctx = context.WithValue(ctx, UsernameKey, session.Values["username"])
-
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"webpolls/i18n"
+	"webpolls/utils"
+)
+
+const (
+	CSRFTokenKey contextKey = "csrf_token"
+
+	// CSRFHeader es el header que HTMX envía en cada petición (ver hx-headers en views.Layout).
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFFormField permite enviar el token en formularios que no pasan por HTMX.
+	CSRFFormField = "csrf_token"
+
+	csrfSessionKey = "csrf_token"
+)
+
+// CSRFMiddleware genera un token por sesión y lo exige en toda petición que cambie estado.
+// Quedan exentas las integraciones firmadas (Slack), cuya firma verifica el handler de la
+// integración, y los clientes de API que se autentican con "Authorization: Bearer" sin cookie
+// de sesión: un formulario entre sitios no puede agregar un header Authorization, y sin la
+// cookie la petición nunca se autentica con la sesión del navegador.
+func CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSignedIntegrationRequest(r) || isBearerRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session := utils.GetSession(r)
+		token, _ := session.Values[csrfSessionKey].(string)
+		if token == "" {
+			newToken, err := utils.RandomToken(32)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "error generando token CSRF", "error", err)
+				respondError(w, r, http.StatusInternalServerError, i18n.T(r.Context(), "error.internal"))
+				return
+			}
+			token = newToken
+			session.Values[csrfSessionKey] = token
+			if err := utils.SaveSession(w, r, session); err != nil {
+				slog.ErrorContext(r.Context(), "error guardando sesión con token CSRF", "error", err)
+			}
+		}
+
+		if !isSafeMethod(r.Method) {
+			sent := r.Header.Get(CSRFHeader)
+			if sent == "" {
+				sent = r.PostFormValue(CSRFFormField)
+			}
+			if subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				slog.WarnContext(r.Context(), "token CSRF inválido", "method", r.Method, "path", r.URL.Path)
+				respondError(w, r, http.StatusForbidden, i18n.T(r.Context(), "error.csrf"))
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), CSRFTokenKey, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// CSRFToken devuelve el token CSRF de la petición actual (vacío si no pasó por CSRFMiddleware).
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(CSRFTokenKey).(string)
+	return token
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// isSignedIntegrationRequest reconoce las peticiones de Slack: un navegador no puede enviar
+// ese header en un formulario entre sitios.
+func isSignedIntegrationRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/integrations/") && r.Header.Get("X-Slack-Signature") != ""
+}
+
+// isBearerRequest reconoce a un cliente de API: trae un token Bearer y ninguna cookie de
+// sesión. Si llega la cookie, la petición puede venir de un navegador y se valida el token CSRF.
+func isBearerRequest(r *http.Request) bool {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return false
+	}
+	_, err := r.Cookie(utils.SessionCookie)
+	return err == http.ErrNoCookie
+}
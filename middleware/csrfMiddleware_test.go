@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webpolls/utils"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	utils.InitSessionStore("test-session-key-0123456789abcdef", false)
+	handler := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// Un GET entrega la cookie de sesión con el token
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/polls", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("GET: got %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("GET no guardó la sesión")
+	}
+	session, _ := utils.Store.Get(requestWithCookies(http.MethodGet, "/", cookies), utils.SessionCookie)
+	token, _ := session.Values[csrfSessionKey].(string)
+	if token == "" {
+		t.Fatal("la sesión no tiene token CSRF")
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		path    string
+		want    int
+	}{
+		{"sin token", nil, "/polls/create", http.StatusForbidden},
+		{"token inválido", map[string]string{CSRFHeader: "otro"}, "/polls/create", http.StatusForbidden},
+		{"token válido", map[string]string{CSRFHeader: token}, "/polls/create", http.StatusNoContent},
+		{"bearer con cookie de sesión", map[string]string{"Authorization": "Bearer x"}, "/polls/create", http.StatusForbidden},
+		{"firma de Slack exime", map[string]string{"X-Slack-Signature": "v0=abc"}, "/integrations/slack/commands", http.StatusNoContent},
+		{"firma de Slack fuera de integraciones", map[string]string{"X-Slack-Signature": "v0=abc"}, "/polls/create", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := requestWithCookies(http.MethodPost, tt.path, cookies)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+			if rec.Code != tt.want {
+				t.Errorf("got %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// Un cliente de API sin cookie de sesión no necesita token, y tampoco recibe una sesión nueva.
+func TestCSRFMiddlewareBearer(t *testing.T) {
+	utils.InitSessionStore("test-session-key-0123456789abcdef", false)
+	handler := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"bearer sin cookie", "Bearer x", http.StatusNoContent},
+		{"basic sin cookie", "Basic eDp5", http.StatusForbidden},
+		{"sin header", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := requestWithCookies(http.MethodPost, "/polls/create", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+			if rec.Code != tt.want {
+				t.Errorf("got %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusNoContent && len(rec.Result().Cookies()) > 0 {
+				t.Error("se creó una sesión para un cliente de API")
+			}
+		})
+	}
+}
+
+func requestWithCookies(method, path string, cookies []*http.Cookie) *http.Request {
+	r := httptest.NewRequest(method, path, strings.NewReader(""))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	return r
+}
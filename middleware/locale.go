@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"webpolls/i18n"
+)
+
+// Locale elige el idioma de la petición: primero la cookie que deja el selector de idioma
+// (o el inicio de sesión, con la preferencia del usuario) y si no, Accept-Language.
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := ""
+		if cookie, err := r.Cookie(i18n.CookieName); err == nil && i18n.IsSupported(cookie.Value) {
+			locale = cookie.Value
+		} else {
+			locale = i18n.Negotiate(r.Header.Get("Accept-Language"))
+		}
+
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Add("Vary", "Cookie")
+		w.Header().Set("Content-Language", locale)
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+	})
+}
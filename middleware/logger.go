@@ -2,17 +2,71 @@ package middleware
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
-	"log"
+	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
+	"webpolls/utils"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// responseWriterWrapper es un wrapper que captura el código de estado y el cuerpo de la respuesta.
+// RequestIDHeader es el header por el que se recibe y se devuelve el ID de cada petición.
+const RequestIDHeader = "X-Request-ID"
+
+// maxLoggedBody es el tamaño máximo de cuerpo que se registra en nivel debug.
+const maxLoggedBody = 16 << 10
+
+// sensitiveFields son los campos que nunca se escriben en los logs.
+var sensitiveFields = []string{"password", "code", "secret", "token", "csrf", "authorization"}
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+const requestInfoKey contextKey = "request_info"
+
+// requestInfo lo completan los middlewares internos para que lo registren los externos.
+type requestInfo struct {
+	pattern string
+}
+
+// withRequestInfo devuelve el requestInfo de la petición, creándolo si todavía no existe.
+func withRequestInfo(r *http.Request) (*http.Request, *requestInfo) {
+	if info, ok := r.Context().Value(requestInfoKey).(*requestInfo); ok {
+		return r, info
+	}
+	info := &requestInfo{}
+	return r.WithContext(context.WithValue(r.Context(), requestInfoKey, info)), info
+}
+
+// RecordRoutePattern envuelve al mux y anota el patrón de la ruta que resolvió
+// (p. ej. "GET /polls/{id}"), que solo se conoce después de rutear.
+func RecordRoutePattern(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+		if info, ok := r.Context().Value(requestInfoKey).(*requestInfo); ok {
+			info.pattern = r.Pattern
+		}
+		// El span del servidor se nombra por la ruta, no por la URL
+		if r.Pattern != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Pattern)
+			span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+		}
+	})
+}
+
+// responseWriterWrapper captura el código de estado y el tamaño de la respuesta.
 type responseWriterWrapper struct {
 	http.ResponseWriter
 	statusCode int
-	body       *bytes.Buffer // Campo para capturar el cuerpo de la respuesta
+	bytes      int
 }
 
 // newResponseWriterWrapper crea una nueva instancia de nuestro wrapper.
@@ -20,7 +74,6 @@ func newResponseWriterWrapper(w http.ResponseWriter) *responseWriterWrapper {
 	return &responseWriterWrapper{
 		ResponseWriter: w,
 		statusCode:     http.StatusOK,
-		body:           new(bytes.Buffer), // Inicializamos el buffer
 	}
 }
 
@@ -30,45 +83,116 @@ func (w *responseWriterWrapper) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
-// Write captura el cuerpo de la respuesta y lo escribe en la respuesta original.
+// Write cuenta los bytes escritos y los pasa al ResponseWriter original.
 func (w *responseWriterWrapper) Write(b []byte) (int, error) {
-	w.body.Write(b) // Guardamos los bytes en nuestro buffer
-	return w.ResponseWriter.Write(b) // Pasamos los bytes al ResponseWriter original
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
 }
 
-// LoggingMiddleware es el middleware que registra la información de cada petición.
+// Flush permite que el SSE siga funcionando a través del wrapper.
+func (w *responseWriterWrapper) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap expone el ResponseWriter original a http.ResponseController.
+func (w *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// LoggingMiddleware asigna un ID a cada petición y registra un log estructurado al terminarla.
+// El ID se toma del header X-Request-ID si viene uno válido y se devuelve en la respuesta.
+// En nivel debug también registra el cuerpo de formularios y JSON, con los campos sensibles ocultos.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		// Envolvemos el ResponseWriter original para poder capturar estado y cuerpo
-		wrapper := newResponseWriterWrapper(w)
-
-		// Leemos el cuerpo de la petición para poder registrarlo
-		var bodyBytes []byte
-		if r.Body != nil {
-			bodyBytes, _ = io.ReadAll(r.Body)
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID, _ = utils.RandomToken(12)
 		}
-		// Restauramos el cuerpo para que los siguientes handlers puedan leerlo
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-		// Registramos la petición entrante
-		log.Printf("--> %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
-		// Solo registramos el cuerpo si existe
-		if len(bodyBytes) > 0 {
-			log.Printf("    Request Body: %s", bodyBytes)
+		w.Header().Set(RequestIDHeader, requestID)
+
+		r, info := withRequestInfo(r.WithContext(utils.WithRequestID(r.Context(), requestID)))
+		ctx := r.Context()
+
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			if body := redactedBody(r); body != nil {
+				slog.DebugContext(ctx, "request body", "body", body)
+			}
 		}
 
-		// Llamamos al siguiente handler en la cadena con nuestro wrapper
+		wrapper := newResponseWriterWrapper(w)
 		next.ServeHTTP(wrapper, r)
 
-		// Registramos la respuesta saliente
-		duration := time.Since(start)
-		log.Printf("<-- %d %s in %s", wrapper.statusCode, http.StatusText(wrapper.statusCode), duration)
-
-		// Registramos el cuerpo de la respuesta si no está vacío
-		if wrapper.body.Len() > 0 {
-			log.Printf("    Response Body: %s", wrapper.body.String())
+		level := slog.LevelInfo
+		if wrapper.statusCode >= 500 {
+			level = slog.LevelError
 		}
+		slog.Log(ctx, level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"pattern", info.pattern,
+			"status", wrapper.statusCode,
+			"bytes", wrapper.bytes,
+			"duration", time.Since(start),
+			"ip", utils.ClientIP(r),
+		)
 	})
 }
+
+// redactedBody lee el cuerpo (formulario o JSON), lo restaura para los handlers y
+// devuelve sus campos con los valores sensibles reemplazados.
+func redactedBody(r *http.Request) map[string]any {
+	if r.Body == nil || r.ContentLength == 0 || r.ContentLength > maxLoggedBody {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-www-form-urlencoded" && mediaType != "application/json" {
+		return nil
+	}
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, maxLoggedBody))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyBytes), r.Body))
+	if err != nil {
+		return nil
+	}
+
+	fields := map[string]any{}
+	if mediaType == "application/json" {
+		if json.Unmarshal(bodyBytes, &fields) != nil {
+			return nil
+		}
+	} else {
+		values, err := url.ParseQuery(string(bodyBytes))
+		if err != nil {
+			return nil
+		}
+		for key, v := range values {
+			if len(v) == 1 {
+				fields[key] = v[0]
+			} else {
+				fields[key] = v
+			}
+		}
+	}
+
+	for key := range fields {
+		if isSensitiveField(key) {
+			fields[key] = "[REDACTED]"
+		}
+	}
+	return fields
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
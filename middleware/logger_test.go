@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webpolls/utils"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLoggingMiddlewareRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"sin ID", "", false},
+		{"ID válido", "abc-123_x.y", true},
+		{"ID con espacios", "abc 123", false},
+		{"ID con salto de línea", "abc\nforged=1", false},
+		{"ID demasiado largo", strings.Repeat("a", 65), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = utils.RequestID(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				r.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			got := rec.Header().Get(RequestIDHeader)
+			if got == "" || got != seen {
+				t.Fatalf("header %q y contexto %q, want el mismo ID no vacío", got, seen)
+			}
+			if (got == tt.incoming) != tt.keep {
+				t.Errorf("ID = %q, want conservar %q: %v", got, tt.incoming, tt.keep)
+			}
+			if !validRequestID.MatchString(got) {
+				t.Errorf("el ID generado %q no es válido", got)
+			}
+		})
+	}
+}
+
+func TestRedactedBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        map[string]any
+	}{
+		{
+			"formulario", "application/x-www-form-urlencoded",
+			"email=ana%40example.com&password=secreta&csrf_token=abc&code=123456",
+			map[string]any{"email": "ana@example.com", "password": "[REDACTED]", "csrf_token": "[REDACTED]", "code": "[REDACTED]"},
+		},
+		{
+			"JSON", "application/json; charset=utf-8",
+			`{"question":"¿Sí?","Secret":"x","api_token":"y"}`,
+			map[string]any{"question": "¿Sí?", "Secret": "[REDACTED]", "api_token": "[REDACTED]"},
+		},
+		{"JSON inválido", "application/json", `{"password":`, nil},
+		{"otro tipo", "text/plain", "password=secreta", nil},
+		{"vacío", "application/json", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			got := redactedBody(r)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("redactedBody = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("%s = %v, want %v", key, got[key], value)
+				}
+			}
+			// El handler tiene que poder leer el cuerpo completo
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != tt.body {
+				t.Errorf("cuerpo restaurado = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestResponseWriterWrapper(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := newResponseWriterWrapper(rec)
+	w.WriteHeader(http.StatusTeapot)
+	w.Write([]byte("hola"))
+	w.Write([]byte(" mundo"))
+	if w.statusCode != http.StatusTeapot || w.bytes != 10 {
+		t.Errorf("status %d y %d bytes, want %d y 10", w.statusCode, w.bytes, http.StatusTeapot)
+	}
+	if http.NewResponseController(w).Flush() != nil {
+		t.Error("el wrapper no deja hacer Flush")
+	}
+}
+
+// El span del servidor toma el nombre de la ruta para que las trazas se agrupen por endpoint.
+func TestRecordRoutePatternNamesSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /polls/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	ctx, span := tracer.Start(context.Background(), "GET")
+	r := httptest.NewRequest(http.MethodGet, "/polls/42", nil).WithContext(ctx)
+	RecordRoutePattern(mux).ServeHTTP(httptest.NewRecorder(), r)
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || ended[0].Name() != "GET /polls/{id}" {
+		t.Fatalf("spans = %v, want uno llamado GET /polls/{id}", ended)
+	}
+	for _, attr := range ended[0].Attributes() {
+		if attr.Key == "http.route" && attr.Value.AsString() == "GET /polls/{id}" {
+			return
+		}
+	}
+	t.Errorf("falta http.route en %v", ended[0].Attributes())
+}
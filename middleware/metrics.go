@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "webpolls_http_request_duration_seconds",
+	Help:    "Duración de las peticiones HTTP por patrón de ruta.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "pattern", "status"})
+
+// MetricsMiddleware mide la duración de cada petición agrupada por el patrón de la ruta
+// (no por la URL, para no crear una serie por cada encuesta). Requiere RecordRoutePattern dentro.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, info := withRequestInfo(r)
+
+		wrapper := newResponseWriterWrapper(w)
+		next.ServeHTTP(wrapper, r)
+
+		pattern := info.pattern
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+		httpRequestDuration.WithLabelValues(r.Method, pattern, strconv.Itoa(wrapper.statusCode)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// MetricsAuth protege /metrics con un token enviado como "Authorization: Bearer <token>".
+// Con token vacío el endpoint queda abierto.
+func MetricsAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token != "" {
+				got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
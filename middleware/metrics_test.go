@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMetricsAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		status int
+	}{
+		{"sin token configurado", "", "", http.StatusOK},
+		{"token correcto", "s3cr3t", "Bearer s3cr3t", http.StatusOK},
+		{"sin cabecera", "s3cr3t", "", http.StatusUnauthorized},
+		{"token incorrecto", "s3cr3t", "Bearer otro", http.StatusUnauthorized},
+		{"prefijo de otro esquema", "s3cr3t", "Basic s3cr3t", http.StatusUnauthorized},
+		{"token como prefijo", "s3cr3t", "Bearer s3cr3tx", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			MetricsAuth(tt.token)(ok).ServeHTTP(rec, r)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("falta WWW-Authenticate")
+			}
+		})
+	}
+}
+
+// Las peticiones se agrupan por patrón de ruta, no por URL.
+func TestMetricsMiddlewarePattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := MetricsMiddleware(RecordRoutePattern(mux))
+
+	before := testutil.CollectAndCount(httpRequestDuration)
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2", "/metrics-test/3"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no-existe/123", nil))
+
+	if got := testutil.CollectAndCount(httpRequestDuration) - before; got != 2 {
+		t.Errorf("series nuevas = %d, want 2 (la ruta y unmatched)", got)
+	}
+	var metric dto.Metric
+	observer := httpRequestDuration.WithLabelValues(http.MethodGet, "GET /metrics-test/{id}", "202")
+	if err := observer.(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	if got := metric.GetHistogram().GetSampleCount(); got != 3 {
+		t.Errorf("peticiones a GET /metrics-test/{id} = %d, want 3", got)
+	}
+}
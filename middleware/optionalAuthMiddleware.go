@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 	"webpolls/utils"
 )
@@ -11,11 +10,11 @@ func OptionalAuthMiddleware(next http.Handler) http.Handler {
 		session := utils.GetSession(r)
 		if auth, ok := session.Values["authenticated"].(bool); ok && auth {
 			// Inject user info into context
-			ctx := context.WithValue(r.Context(), UserIDKey, session.Values["user_id"])
-			ctx = context.WithValue(ctx, UsernameKey, session.Values["username"])
-			next.ServeHTTP(w, r.WithContext(ctx))
-		} else {
-			next.ServeHTTP(w, r)
+			if ctx, ok := withUserStatus(w, r, session); ok {
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
 		}
+		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"webpolls/i18n"
+	"webpolls/services"
+	"webpolls/utils"
+)
+
+// RateLimitKeyFunc extrae la clave del bucket para una petición. Si devuelve "" la política no aplica.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimitPolicy es un token bucket con nombre: Rate tokens por segundo con ráfagas de hasta
+// Burst. Los límites se configuran como "N/unidad" (ver config.ParseRateLimit).
+type RateLimitPolicy struct {
+	Name  string
+	Rate  float64
+	Burst int
+	Key   RateLimitKeyFunc
+}
+
+// KeyByIP agrupa las peticiones por IP del cliente.
+func KeyByIP(r *http.Request) string {
+	return "ip:" + utils.ClientIP(r)
+}
+
+// KeyByUser agrupa las peticiones por usuario autenticado (requiere AuthMiddleware antes).
+func KeyByUser(r *http.Request) string {
+	if userID, ok := r.Context().Value(UserIDKey).(int32); ok {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	return ""
+}
+
+// RateLimit rechaza con 429 las peticiones que agotan alguno de los buckets de las políticas dadas.
+// Si el store falla se deja pasar la petición para no tumbar el sitio por el limitador.
+func RateLimit(store services.RateLimitStore, policies ...RateLimitPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, policy := range policies {
+				key := policy.Key(r)
+				if key == "" {
+					continue
+				}
+
+				result, err := store.Take(r.Context(), policy.Name+":"+key, policy.Rate, policy.Burst)
+				if err != nil {
+					slog.ErrorContext(r.Context(), "error en rate limit", "policy", policy.Name, "error", err)
+					continue
+				}
+				if !result.Allowed {
+					slog.WarnContext(r.Context(), "rate limit excedido", "policy", policy.Name, "key", key)
+					respondTooManyRequests(w, r, result.RetryAfter)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// KeyByLoginEmail agrupa los intentos de login por email ingresado e IP.
+func KeyByLoginEmail(r *http.Request) string {
+	return "login:" + loginEmail(r) + "|" + utils.ClientIP(r)
+}
+
+// KeyByLoginAccount agrupa los intentos de login por email ingresado, sin importar la IP:
+// así repartir los intentos entre muchas IPs no evita el bloqueo de la cuenta.
+func KeyByLoginAccount(r *http.Request) string {
+	email := loginEmail(r)
+	if email == "" {
+		return ""
+	}
+	return "login-account:" + email
+}
+
+func loginEmail(r *http.Request) string {
+	return strings.ToLower(strings.TrimSpace(r.PostFormValue("email")))
+}
+
+// KeyByPendingTwoFactor agrupa los intentos del segundo paso del login por usuario pendiente.
+func KeyByPendingTwoFactor(r *http.Request) string {
+	session := utils.GetSession(r)
+	if userID, ok := session.Values["pending_2fa_user_id"].(int32); ok {
+		return fmt.Sprintf("2fa:%d", userID)
+	}
+	return ""
+}
+
+// LoginLockout bloquea progresivamente la clave dada tras varios intentos fallidos.
+// Un intento fallido es una respuesta 401 del handler; cualquier respuesta 2xx reinicia el contador.
+func LoginLockout(store services.RateLimitStore, policy services.LockoutPolicy, keyFunc RateLimitKeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			locked, err := store.LockedFor(r.Context(), key)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "error consultando bloqueo de login", "error", err)
+			} else if locked > 0 {
+				respondTooManyRequests(w, r, locked)
+				return
+			}
+
+			wrapper := newResponseWriterWrapper(w)
+			next.ServeHTTP(wrapper, r)
+
+			switch {
+			case wrapper.statusCode == http.StatusUnauthorized:
+				lock, err := store.RegisterFailure(r.Context(), key, policy)
+				if err != nil {
+					slog.ErrorContext(r.Context(), "error registrando login fallido", "error", err)
+				} else if lock > 0 {
+					slog.WarnContext(r.Context(), "login bloqueado", "duration", lock, "key", key)
+				}
+			case wrapper.statusCode < 300:
+				if err := store.ResetFailures(r.Context(), key); err != nil {
+					slog.ErrorContext(r.Context(), "error reiniciando intentos de login", "error", err)
+				}
+			}
+		})
+	}
+}
+
+func respondTooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	respondError(w, r, http.StatusTooManyRequests, i18n.T(r.Context(), "error.too_many_requests", formatWait(r.Context(), seconds)))
+}
+
+func formatWait(ctx context.Context, seconds int) string {
+	if seconds == 1 {
+		return i18n.T(ctx, "time.one_second")
+	}
+	if seconds < 60 {
+		return i18n.T(ctx, "time.seconds", seconds)
+	}
+	minutes := (seconds + 59) / 60
+	if minutes == 1 {
+		return i18n.T(ctx, "time.one_minute")
+	}
+	return i18n.T(ctx, "time.minutes", minutes)
+}
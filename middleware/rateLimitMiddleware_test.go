@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"webpolls/services"
+)
+
+func loginRequest(email, ip string) *http.Request {
+	form := url.Values{"email": {email}, "password": {"x"}}
+	r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.RemoteAddr = ip + ":1234"
+	return r
+}
+
+func TestRateLimit(t *testing.T) {
+	policy := RateLimitPolicy{Name: "test", Rate: 2.0 / 60, Burst: 2, Key: KeyByIP}
+	handler := RateLimit(services.NewMemoryRateLimitStore(), policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, loginRequest("ana@example.com", "10.0.0.1"))
+		if rec.Code != want {
+			t.Fatalf("petición %d: got %d, want %d", i+1, rec.Code, want)
+		}
+	}
+
+	// Otra IP tiene su propio bucket
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, loginRequest("ana@example.com", "10.0.0.2"))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("otra IP: got %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	store := services.NewMemoryRateLimitStore()
+	policy := services.LockoutPolicy{Threshold: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	calls := 0
+	handler := LoginLockout(store, policy, KeyByLoginEmail)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	for i := range 3 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, loginRequest("Ana@example.com", "10.0.0.1"))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("intento %d: got %d, want %d", i+1, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, loginRequest("ana@example.com", "10.0.0.1"))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("bloqueado: got %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("falta Retry-After")
+	}
+	if calls != 3 {
+		t.Errorf("el handler corrió %d veces, want 3", calls)
+	}
+
+	// Otra cuenta desde la misma IP no se ve afectada
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, loginRequest("otra@example.com", "10.0.0.1"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("otra cuenta: got %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLoginKeys(t *testing.T) {
+	a := loginRequest(" Ana@Example.com ", "10.0.0.1")
+	b := loginRequest("ana@example.com", "10.0.0.2")
+	if KeyByLoginEmail(a) == KeyByLoginEmail(b) {
+		t.Error("KeyByLoginEmail no distingue IPs")
+	}
+	if KeyByLoginAccount(a) != KeyByLoginAccount(b) || KeyByLoginAccount(a) != "login-account:ana@example.com" {
+		t.Errorf("KeyByLoginAccount = %q y %q, want la misma clave sin IP", KeyByLoginAccount(a), KeyByLoginAccount(b))
+	}
+	if key := KeyByLoginAccount(loginRequest("", "10.0.0.1")); key != "" {
+		t.Errorf("KeyByLoginAccount sin email = %q, want vacío", key)
+	}
+}
+
+// Repartir los intentos entre muchas IPs no evita el bloqueo por cuenta.
+func TestLoginLockoutAcrossIPs(t *testing.T) {
+	store := services.NewMemoryRateLimitStore()
+	policy := services.LockoutPolicy{Threshold: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	calls := 0
+	handler := LoginLockout(store, policy, KeyByLoginAccount)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	for i := range 3 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, loginRequest("ana@example.com", fmt.Sprintf("10.0.0.%d", i+1)))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("intento %d: got %d, want %d", i+1, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, loginRequest("ana@example.com", "10.0.0.99"))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("desde otra IP: got %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("falta Retry-After")
+	}
+	if calls != 3 {
+		t.Errorf("el handler corrió %d veces, want 3", calls)
+	}
+
+	// Otra cuenta no se ve afectada
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, loginRequest("otra@example.com", "10.0.0.99"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("otra cuenta: got %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
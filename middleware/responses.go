@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"webpolls/components"
+)
+
+// respondError responde con un toast si la petición viene de HTMX o con el
+// sobre JSON estándar de la API ({"data": null, "error": "..."}) en otro caso.
+func respondError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Reswap", "none")
+		w.WriteHeader(code)
+		components.Toast(message, true).Render(r.Context(), w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	payload := map[string]interface{}{"data": nil, "error": message}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		slog.Error("error al codificar respuesta JSON", "error", err)
+	}
+}
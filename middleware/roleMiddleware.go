@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"webpolls/i18n"
+	"webpolls/services"
+
+	"github.com/gorilla/sessions"
+	"github.com/jackc/pgx/v5"
+)
+
+const RoleKey contextKey = "role"
+
+// UserStatusFunc devuelve el rol y la suspensión actuales de un usuario.
+type UserStatusFunc func(ctx context.Context, userID int32) (*services.UserStatus, error)
+
+var userStatus UserStatusFunc
+
+// SetUserStatusFunc registra cómo consultar el estado de la cuenta. Con ella configurada,
+// AuthMiddleware y OptionalAuthMiddleware cierran la sesión de los usuarios suspendidos
+// o borrados e inyectan el rol en el contexto.
+func SetUserStatusFunc(fn UserStatusFunc) {
+	userStatus = fn
+}
+
+// Role devuelve el rol del usuario autenticado o "" si no hay sesión.
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(RoleKey).(string)
+	return role
+}
+
+// RequireRole deja pasar solo a los usuarios con alguno de los roles dados.
+// Va siempre después de AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !slices.Contains(roles, Role(r.Context())) {
+				respondError(w, r, http.StatusForbidden, i18n.T(r.Context(), "error.forbidden"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// withUserStatus inyecta el usuario de la sesión y su rol en el contexto.
+// Devuelve false si la cuenta ya no puede usar la sesión (suspendida o borrada).
+func withUserStatus(w http.ResponseWriter, r *http.Request, session *sessions.Session) (context.Context, bool) {
+	ctx := context.WithValue(r.Context(), UserIDKey, session.Values["user_id"])
+	ctx = context.WithValue(ctx, UsernameKey, session.Values["username"])
+
+	if userStatus == nil {
+		return context.WithValue(ctx, RoleKey, services.RoleUser), true
+	}
+
+	userID, _ := session.Values["user_id"].(int32)
+	status, err := userStatus(r.Context(), userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		// Ante un error de BD no cerramos la sesión, pero tampoco damos más permisos que los de un usuario común
+		slog.ErrorContext(r.Context(), "error consultando estado del usuario", "user_id", userID, "error", err)
+		return context.WithValue(ctx, RoleKey, services.RoleUser), true
+	}
+	if err != nil || status.Suspended {
+		session.Values["authenticated"] = false
+		delete(session.Values, "user_id")
+		delete(session.Values, "username")
+		session.Save(r, w)
+		return r.Context(), false
+	}
+
+	return context.WithValue(ctx, RoleKey, status.Role), true
+}
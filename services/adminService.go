@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Estados de un reporte en la cola de moderación.
+const (
+	ReportOpen      = "open"
+	ReportResolved  = "resolved"
+	ReportDismissed = "dismissed"
+)
+
+// Acciones posibles al revisar un reporte.
+const (
+	ReportActionDismiss = "dismiss"
+	ReportActionHide    = "hide"
+	ReportActionDelete  = "delete"
+)
+
+// auditLogPageSize es la cantidad de entradas del registro de auditoría que se muestran.
+const auditLogPageSize = 100
+
+// AdminService agrupa las acciones del panel de administración y moderación.
+// Toda acción que modifica datos queda en el registro de auditoría en la misma transacción.
+type AdminService struct {
+	Queries *db.Queries
+	DB      *pgxpool.Pool
+	// Webhooks avisa al dueño cuando se borra su encuesta (nil = sin webhooks)
+	Webhooks *WebhookService
+}
+
+// NewAdminService crea una nueva instancia de AdminService.
+func NewAdminService(queries *db.Queries, db *pgxpool.Pool) *AdminService {
+	return &AdminService{Queries: queries, DB: db}
+}
+
+// Actor es el usuario del staff que realiza una acción.
+type Actor struct {
+	ID   int32
+	Role string
+}
+
+// IsStaff indica si el rol puede entrar al panel de administración.
+func IsStaff(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
+
+type AdminUser struct {
+	ID          int32      `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	SuspendedAt *time.Time `json:"suspended_at"`
+}
+
+type AdminPoll struct {
+	ID          int32  `json:"id"`
+	Title       string `json:"title"`
+	UserID      int32  `json:"user_id"`
+	Username    string `json:"username"`
+	Hidden      bool   `json:"hidden"`
+	OpenReports int64  `json:"open_reports"`
+}
+
+type ReportResponse struct {
+	ID               int32     `json:"id"`
+	PollID           int32     `json:"poll_id"`
+	PollTitle        string    `json:"poll_title"`
+	ReporterUsername string    `json:"reporter_username"`
+	Reason           string    `json:"reason"`
+	Details          string    `json:"details"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type AuditEntry struct {
+	ID            int32     `json:"id"`
+	ActorUsername string    `json:"actor_username"`
+	Action        string    `json:"action"`
+	TargetType    string    `json:"target_type"`
+	TargetID      int32     `json:"target_id"`
+	Details       string    `json:"details"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (s *AdminService) ListUsers(ctx context.Context) ([]AdminUser, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ListUsers")
+	defer span.End()
+
+	rows, err := s.Queries.GetAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []AdminUser
+	for _, row := range rows {
+		user := AdminUser{
+			ID:       row.ID,
+			Username: row.Username,
+			Email:    row.Email,
+			Role:     row.Role,
+		}
+		if row.SuspendedAt.Valid {
+			suspendedAt := row.SuspendedAt.Time
+			user.SuspendedAt = &suspendedAt
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// SetUserSuspended suspende o reactiva una cuenta. Los moderadores solo pueden suspender usuarios comunes.
+func (s *AdminService) SetUserSuspended(ctx context.Context, actor Actor, userID int32, suspended bool) error {
+	ctx, span := tracer.Start(ctx, "AdminService.SetUserSuspended")
+	defer span.End()
+
+	if actor.ID == userID {
+		return Forbidden("admin.cannot_suspend_self")
+	}
+
+	target, err := s.Queries.GetUserStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != RoleAdmin && target.Role != RoleUser {
+		return Forbidden("admin.staff_suspend_requires_admin")
+	}
+
+	suspendedAt := pgtype.Timestamptz{}
+	action := "user.unsuspend"
+	if suspended {
+		suspendedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		action = "user.suspend"
+	}
+
+	return s.withAudit(ctx, actor, action, "user", userID, "", func(qtx *db.Queries) error {
+		return qtx.SetUserSuspendedAt(ctx, db.SetUserSuspendedAtParams{
+			SuspendedAt: suspendedAt,
+			ID:          userID,
+		})
+	})
+}
+
+// SetUserRole cambia el rol de un usuario. Solo lo pueden hacer los administradores.
+func (s *AdminService) SetUserRole(ctx context.Context, actor Actor, userID int32, role string) error {
+	ctx, span := tracer.Start(ctx, "AdminService.SetUserRole")
+	defer span.End()
+
+	if actor.Role != RoleAdmin {
+		return Forbidden("admin.role_change_requires_admin")
+	}
+	if actor.ID == userID {
+		return Forbidden("admin.cannot_change_own_role")
+	}
+	if role != RoleUser && role != RoleModerator && role != RoleAdmin {
+		return InvalidField("role", "admin.invalid_role")
+	}
+
+	return s.withAudit(ctx, actor, "user.role", "user", userID, role, func(qtx *db.Queries) error {
+		updated, err := qtx.SetUserRole(ctx, db.SetUserRoleParams{
+			Role: role,
+			ID:   userID,
+		})
+		if err == nil && updated == 0 {
+			return ErrUserNotFound
+		}
+		return err
+	})
+}
+
+func (s *AdminService) ListPolls(ctx context.Context) ([]AdminPoll, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ListPolls")
+	defer span.End()
+
+	rows, err := s.Queries.GetPollsForModeration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var polls []AdminPoll
+	for _, row := range rows {
+		polls = append(polls, AdminPoll{
+			ID:          row.ID,
+			Title:       row.Title,
+			UserID:      row.UserID,
+			Username:    row.Username,
+			Hidden:      row.Hidden,
+			OpenReports: row.OpenReports,
+		})
+	}
+	return polls, nil
+}
+
+// SetPollHidden oculta o vuelve a mostrar una encuesta en los listados públicos.
+func (s *AdminService) SetPollHidden(ctx context.Context, actor Actor, pollID int32, hidden bool) error {
+	ctx, span := tracer.Start(ctx, "AdminService.SetPollHidden")
+	defer span.End()
+
+	action := "poll.unhide"
+	if hidden {
+		action = "poll.hide"
+	}
+
+	return s.withAudit(ctx, actor, action, "poll", pollID, "", func(qtx *db.Queries) error {
+		updated, err := qtx.SetPollHidden(ctx, db.SetPollHiddenParams{
+			Hidden: hidden,
+			ID:     pollID,
+		})
+		if err == nil && updated == 0 {
+			return ErrPollNotFound
+		}
+		return err
+	})
+}
+
+// DeletePoll borra una encuesta. El título queda en la auditoría porque la fila desaparece.
+func (s *AdminService) DeletePoll(ctx context.Context, actor Actor, pollID int32) error {
+	ctx, span := tracer.Start(ctx, "AdminService.DeletePoll")
+	defer span.End()
+
+	poll, err := s.Queries.GetPollByID(ctx, pollID)
+	if err != nil {
+		return err
+	}
+	if len(poll) == 0 {
+		return ErrPollNotFound
+	}
+	title := poll[0].Title
+
+	err = s.withAudit(ctx, actor, "poll.delete", "poll", pollID, title, func(qtx *db.Queries) error {
+		return qtx.DeletePoll(ctx, pollID)
+	})
+	if err == nil {
+		s.Webhooks.Emit(ctx, poll[0].UserID, EventPollDeleted, PollEventData{PollID: pollID, Title: title})
+	}
+	return err
+}
+
+func (s *AdminService) ListReports(ctx context.Context, status string) ([]ReportResponse, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ListReports")
+	defer span.End()
+
+	rows, err := s.Queries.ListReportsByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []ReportResponse
+	for _, row := range rows {
+		reports = append(reports, ReportResponse{
+			ID:               row.ID,
+			PollID:           row.PollID,
+			PollTitle:        row.PollTitle,
+			ReporterUsername: row.ReporterUsername.String,
+			Reason:           row.Reason,
+			Details:          row.Details,
+			Status:           row.Status,
+			CreatedAt:        row.CreatedAt.Time,
+		})
+	}
+	return reports, nil
+}
+
+// ResolveReport cierra un reporte con una de las acciones: descartarlo, ocultar la
+// encuesta o borrarla. Ocultar resuelve también los demás reportes abiertos de la encuesta.
+func (s *AdminService) ResolveReport(ctx context.Context, actor Actor, reportID int32, action string) error {
+	ctx, span := tracer.Start(ctx, "AdminService.ResolveReport")
+	defer span.End()
+
+	report, err := s.Queries.GetReportByID(ctx, reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status != ReportOpen {
+		return Conflict("report.already_reviewed")
+	}
+
+	resolvedBy := pgtype.Int4{Int32: actor.ID, Valid: true}
+
+	switch action {
+	case ReportActionDismiss:
+		return s.withAudit(ctx, actor, "report.dismiss", "report", reportID, "", func(qtx *db.Queries) error {
+			return qtx.ResolveReport(ctx, db.ResolveReportParams{
+				Status:     ReportDismissed,
+				ResolvedBy: resolvedBy,
+				ID:         reportID,
+			})
+		})
+	case ReportActionHide:
+		details := fmt.Sprintf("reporte #%d", reportID)
+		return s.withAudit(ctx, actor, "poll.hide", "poll", report.PollID, details, func(qtx *db.Queries) error {
+			_, err := qtx.SetPollHidden(ctx, db.SetPollHiddenParams{
+				Hidden: true,
+				ID:     report.PollID,
+			})
+			if err != nil {
+				return err
+			}
+			return qtx.ResolveOpenReportsByPoll(ctx, db.ResolveOpenReportsByPollParams{
+				Status:     ReportResolved,
+				ResolvedBy: resolvedBy,
+				PollID:     report.PollID,
+			})
+		})
+	case ReportActionDelete:
+		// Los reportes de la encuesta se borran en cascada
+		return s.DeletePoll(ctx, actor, report.PollID)
+	default:
+		return InvalidField("action", "admin.invalid_action")
+	}
+}
+
+func (s *AdminService) ListAuditLog(ctx context.Context) ([]AuditEntry, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ListAuditLog")
+	defer span.End()
+
+	rows, err := s.Queries.ListAuditLog(ctx, auditLogPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	for _, row := range rows {
+		entries = append(entries, AuditEntry{
+			ID:            row.ID,
+			ActorUsername: row.ActorUsername.String,
+			Action:        row.Action,
+			TargetType:    row.TargetType,
+			TargetID:      row.TargetID,
+			Details:       row.Details,
+			CreatedAt:     row.CreatedAt.Time,
+		})
+	}
+	return entries, nil
+}
+
+// withAudit ejecuta fn dentro de una transacción y registra la acción en el log de auditoría.
+func (s *AdminService) withAudit(ctx context.Context, actor Actor, action, targetType string, targetID int32, details string, fn func(qtx *db.Queries) error) error {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	if err := fn(qtx); err != nil {
+		return err
+	}
+
+	err = qtx.CreateAuditLogEntry(ctx, db.CreateAuditLogEntryParams{
+		ActorID:    pgtype.Int4{Int32: actor.ID, Valid: true},
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Los permisos se revisan antes de tocar la base, así que alcanza con un servicio sin conexión.
+func TestSetUserRolePermissions(t *testing.T) {
+	s := &AdminService{}
+	tests := []struct {
+		name   string
+		actor  Actor
+		userID int32
+		role   string
+		want   error
+	}{
+		{"moderador", Actor{ID: 1, Role: RoleModerator}, 2, RoleAdmin, Forbidden("admin.role_change_requires_admin")},
+		{"usuario común", Actor{ID: 1, Role: RoleUser}, 2, RoleModerator, Forbidden("admin.role_change_requires_admin")},
+		{"su propio rol", Actor{ID: 1, Role: RoleAdmin}, 1, RoleUser, Forbidden("admin.cannot_change_own_role")},
+		{"rol inexistente", Actor{ID: 1, Role: RoleAdmin}, 2, "root", InvalidField("role", "admin.invalid_role")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.SetUserRole(context.Background(), tt.actor, tt.userID, tt.role)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SetUserRole = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetUserSuspendedSelf(t *testing.T) {
+	err := (&AdminService{}).SetUserSuspended(context.Background(), Actor{ID: 3, Role: RoleAdmin}, 3, true)
+	if !errors.Is(err, Forbidden("admin.cannot_suspend_self")) {
+		t.Errorf("SetUserSuspended = %v, want admin.cannot_suspend_self", err)
+	}
+}
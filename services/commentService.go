@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Estados de los comentarios de una encuesta. Los elige quien la administra.
+const (
+	// CommentsOpen permite comentar y editar
+	CommentsOpen = "open"
+	// CommentsLocked deja leer los comentarios pero no agregar ni editar
+	CommentsLocked = "locked"
+	// CommentsDisabled oculta la sección
+	CommentsDisabled = "disabled"
+)
+
+// CommentsModes son los estados que puede elegir quien administra la encuesta.
+var CommentsModes = []string{CommentsOpen, CommentsLocked, CommentsDisabled}
+
+const (
+	maxCommentLength = 2000
+	// maxCommentDepth es la cantidad de niveles de un hilo. Las respuestas a un comentario
+	// del último nivel se muestran a su lado en lugar de seguir anidando.
+	maxCommentDepth = 3
+)
+
+// ErrCommentNotFound se devuelve cuando el comentario no existe, se borró o su encuesta no es visible.
+var ErrCommentNotFound = NotFound("comments.not_found")
+
+// CommentService maneja los comentarios en hilos de las encuestas.
+type CommentService struct {
+	Queries *db.Queries
+	// Filter revisa los comentarios contra la lista de palabras prohibidas (nil = sin filtro).
+	// Solo actúa en modo reject: ver validateBody
+	Filter *ContentFilter
+	// Notifications avisa al dueño de la encuesta y al autor del comentario respondido
+	Notifications *NotificationService
+	// Polls decide quién administra cada encuesta (su creador o los dueños y editores de su organización)
+	Polls *PollService
+}
+
+// NewCommentService crea una nueva instancia de CommentService.
+func NewCommentService(queries *db.Queries, polls *PollService) *CommentService {
+	return &CommentService{Queries: queries, Polls: polls}
+}
+
+// CommentResponse es un comentario con sus respuestas, listo para dibujar.
+type CommentResponse struct {
+	ID       int32  `json:"id"`
+	PollID   int32  `json:"poll_id"`
+	ParentID *int32 `json:"parent_id"`
+	UserID   *int32 `json:"user_id"`
+	// Username queda vacío si la cuenta se borró
+	Username string `json:"username"`
+	// Body es el markdown tal cual lo escribió el autor; HTML es su versión ya sanitizada
+	Body      string     `json:"body"`
+	HTML      string     `json:"html"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at"`
+	Deleted   bool       `json:"deleted"`
+	Depth     int        `json:"depth"`
+	// Permisos de quien consulta
+	CanReply  bool               `json:"can_reply"`
+	CanEdit   bool               `json:"can_edit"`
+	CanDelete bool               `json:"can_delete"`
+	Replies   []*CommentResponse `json:"replies"`
+}
+
+// CommentThread son los comentarios de una encuesta tal como los ve quien consulta.
+type CommentThread struct {
+	PollID int32  `json:"poll_id"`
+	Mode   string `json:"mode"`
+	// IsPollOwner habilita el selector de estado y la moderación: es el creador de la encuesta
+	// o un dueño o editor de su organización
+	IsPollOwner bool `json:"is_poll_owner"`
+	// CanComment indica si quien consulta puede escribir (sesión iniciada y comentarios abiertos)
+	CanComment bool `json:"can_comment"`
+	// Count son los comentarios sin borrar
+	Count    int                `json:"count"`
+	Comments []*CommentResponse `json:"comments"`
+}
+
+// commentPoll trae la configuración de comentarios de una encuesta visible para viewerID
+// e indica si viewerID puede administrarla.
+func (s *CommentService) commentPoll(ctx context.Context, pollID int32, viewerID *int32) (*db.GetPollCommentSettingsRow, bool, error) {
+	poll, err := s.Queries.GetPollCommentSettings(ctx, pollID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, ErrPollNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	visible, manage, err := s.Polls.pollAccess(ctx, poll.Hidden, poll.Status, poll.UserID, poll.OrgID, viewerID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !visible {
+		return nil, false, ErrPollNotFound
+	}
+	return &poll, manage, nil
+}
+
+// GetThread arma el árbol de comentarios de la encuesta. Con los comentarios desactivados
+// no se devuelve ninguno.
+func (s *CommentService) GetThread(ctx context.Context, pollID int32, viewerID *int32) (*CommentThread, error) {
+	ctx, span := tracer.Start(ctx, "CommentService.GetThread", trace.WithAttributes(attribute.Int("poll.id", int(pollID))))
+	defer span.End()
+
+	poll, isOwner, err := s.commentPoll(ctx, pollID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &CommentThread{
+		PollID:      pollID,
+		Mode:        poll.CommentsMode,
+		IsPollOwner: isOwner,
+		CanComment:  viewerID != nil && poll.CommentsMode == CommentsOpen,
+		Comments:    []*CommentResponse{},
+	}
+	if poll.CommentsMode == CommentsDisabled {
+		return thread, nil
+	}
+
+	rows, err := s.Queries.ListCommentsByPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	// host es el comentario del que cuelgan las respuestas a cada uno: él mismo, o su
+	// padre si ya está en el último nivel
+	host := make(map[int32]*CommentResponse, len(rows))
+	for _, row := range rows {
+		comment := &CommentResponse{
+			ID:        row.ID,
+			PollID:    row.PollID,
+			ParentID:  int4Ptr(row.ParentID),
+			UserID:    int4Ptr(row.UserID),
+			Username:  row.Username.String,
+			Body:      row.Body,
+			CreatedAt: row.CreatedAt.Time,
+			EditedAt:  timePtr(row.EditedAt),
+			Deleted:   row.DeletedAt.Valid,
+		}
+		if !comment.Deleted {
+			comment.HTML = RenderMarkdown(comment.Body)
+			thread.Count++
+			isAuthor := viewerID != nil && comment.UserID != nil && *viewerID == *comment.UserID
+			comment.CanReply = thread.CanComment
+			comment.CanEdit = isOwner || (isAuthor && poll.CommentsMode == CommentsOpen)
+			comment.CanDelete = isOwner || isAuthor
+		} else {
+			// De un comentario borrado solo queda su lugar en el hilo
+			comment.UserID, comment.Username = nil, ""
+		}
+		host[comment.ID] = comment
+
+		// Los padres siempre son anteriores, así que ya están en el mapa
+		var parent *CommentResponse
+		if comment.ParentID != nil {
+			parent = host[*comment.ParentID]
+		}
+		if parent == nil {
+			thread.Comments = append(thread.Comments, comment)
+			continue
+		}
+		comment.Depth = parent.Depth + 1
+		parent.Replies = append(parent.Replies, comment)
+		if comment.Depth >= maxCommentDepth-1 {
+			host[comment.ID] = parent
+		}
+	}
+	return thread, nil
+}
+
+// CreateComment publica un comentario, o una respuesta si parentID no es nil.
+func (s *CommentService) CreateComment(ctx context.Context, pollID, userID int32, parentID *int32, body string) (int32, error) {
+	ctx, span := tracer.Start(ctx, "CommentService.CreateComment", trace.WithAttributes(attribute.Int("poll.id", int(pollID))))
+	defer span.End()
+
+	poll, _, err := s.commentPoll(ctx, pollID, &userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := commentsWritable(poll.CommentsMode); err != nil {
+		return 0, err
+	}
+	body, err = s.validateBody(body)
+	if err != nil {
+		return 0, err
+	}
+
+	var parentAuthor pgtype.Int4
+	if parentID != nil {
+		parent, err := s.Queries.GetCommentByID(ctx, *parentID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return 0, err
+		}
+		if err != nil || parent.PollID != pollID || parent.DeletedAt.Valid {
+			return 0, InvalidField("parent_id", "comments.invalid_parent")
+		}
+		parentAuthor = parent.UserID
+	}
+
+	id, err := s.Queries.CreateComment(ctx, db.CreateCommentParams{
+		PollID:   pollID,
+		UserID:   pgtype.Int4{Int32: userID, Valid: true},
+		ParentID: int4(parentID),
+		Body:     body,
+	})
+	if err != nil {
+		return 0, err
+	}
+	slog.DebugContext(ctx, "comment created", "poll_id", pollID, "comment_id", id, "user_id", userID)
+
+	s.Notifications.Notify(ctx, poll.UserID, NotificationComment, pollID, userID)
+	if parentAuthor.Valid && parentAuthor.Int32 != poll.UserID {
+		s.Notifications.Notify(ctx, parentAuthor.Int32, NotificationComment, pollID, userID)
+	}
+	return id, nil
+}
+
+// UpdateComment cambia el texto de un comentario. Puede hacerlo su autor mientras los
+// comentarios estén abiertos, y quien administra la encuesta siempre. Devuelve la encuesta
+// del comentario.
+func (s *CommentService) UpdateComment(ctx context.Context, commentID, userID int32, body string) (int32, error) {
+	ctx, span := tracer.Start(ctx, "CommentService.UpdateComment", trace.WithAttributes(attribute.Int("comment.id", int(commentID))))
+	defer span.End()
+
+	comment, isOwner, err := s.visibleComment(ctx, commentID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !isOwner {
+		if !comment.UserID.Valid || comment.UserID.Int32 != userID {
+			return 0, Forbidden("comments.not_author")
+		}
+		if err := commentsWritable(comment.CommentsMode); err != nil {
+			return 0, err
+		}
+	}
+	body, err = s.validateBody(body)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.Queries.UpdateCommentBody(ctx, db.UpdateCommentBodyParams{ID: commentID, Body: body})
+	if err != nil {
+		return 0, err
+	}
+	return comment.PollID, nil
+}
+
+// DeleteComment borra un comentario (su autor o quien administra la encuesta, aunque estén
+// cerrados). Las respuestas se conservan. Devuelve la encuesta del comentario.
+func (s *CommentService) DeleteComment(ctx context.Context, commentID, userID int32) (int32, error) {
+	ctx, span := tracer.Start(ctx, "CommentService.DeleteComment", trace.WithAttributes(attribute.Int("comment.id", int(commentID))))
+	defer span.End()
+
+	comment, isOwner, err := s.visibleComment(ctx, commentID, userID)
+	if err != nil {
+		return 0, err
+	}
+	isAuthor := comment.UserID.Valid && comment.UserID.Int32 == userID
+	if !isAuthor && !isOwner {
+		return 0, Forbidden("comments.not_author")
+	}
+
+	if err := s.Queries.SoftDeleteComment(ctx, commentID); err != nil {
+		return 0, err
+	}
+	return comment.PollID, nil
+}
+
+// SetCommentsMode abre, bloquea o desactiva los comentarios de la encuesta (solo quien la administra).
+func (s *CommentService) SetCommentsMode(ctx context.Context, pollID, userID int32, mode string) error {
+	ctx, span := tracer.Start(ctx, "CommentService.SetCommentsMode", trace.WithAttributes(attribute.Int("poll.id", int(pollID))))
+	defer span.End()
+
+	if !isCommentsMode(mode) {
+		return InvalidField("mode", "comments.invalid_mode")
+	}
+	if _, err := s.Polls.ownedPoll(ctx, pollID, userID); err != nil {
+		return err
+	}
+	updated, err := s.Queries.SetPollCommentsMode(ctx, db.SetPollCommentsModeParams{
+		CommentsMode: mode,
+		ID:           pollID,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrPollNotFound
+	}
+	return nil
+}
+
+// visibleComment trae un comentario sin borrar de una encuesta visible para userID e indica
+// si userID puede administrar esa encuesta.
+func (s *CommentService) visibleComment(ctx context.Context, commentID, userID int32) (*db.GetCommentByIDRow, bool, error) {
+	comment, err := s.Queries.GetCommentByID(ctx, commentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if comment.DeletedAt.Valid {
+		return nil, false, ErrCommentNotFound
+	}
+	visible, manage, err := s.Polls.pollAccess(ctx, comment.PollHidden, comment.PollStatus, comment.PollOwnerID, comment.PollOrgID, &userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !visible {
+		return nil, false, ErrCommentNotFound
+	}
+	return &comment, manage, nil
+}
+
+// validateBody recorta el texto y lo revisa contra el largo máximo y el filtro de contenido.
+// El filtro solo rechaza comentarios en modo reject: en modo flag se publican sin reporte,
+// porque los reportes son de encuestas y uno automático podría terminar ocultando la encuesta
+// entera por un comentario ajeno a su dueño.
+func (s *CommentService) validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", InvalidField("body", "comments.body_required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", InvalidField("body", "comments.body_too_long", maxCommentLength)
+	}
+	if _, flagged := s.Filter.Match(body); flagged && s.Filter.Mode == FilterReject {
+		return "", InvalidField("body", "comments.blocked_words")
+	}
+	return body, nil
+}
+
+// commentsWritable indica si el estado permite escribir comentarios.
+func commentsWritable(mode string) error {
+	switch mode {
+	case CommentsLocked:
+		return Conflict("comments.locked")
+	case CommentsDisabled:
+		return Forbidden("comments.disabled")
+	}
+	return nil
+}
+
+func isCommentsMode(mode string) bool {
+	for _, m := range CommentsModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
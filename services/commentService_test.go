@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func commentRow(id, parentID int32, deleted bool) db.ListCommentsByPollRow {
+	row := db.ListCommentsByPollRow{
+		ID:       id,
+		PollID:   1,
+		UserID:   pgtype.Int4{Int32: 2, Valid: true},
+		Username: pgtype.Text{String: "ana", Valid: true},
+		Body:     "comentario",
+	}
+	if parentID != 0 {
+		row.ParentID = pgtype.Int4{Int32: parentID, Valid: true}
+	}
+	if deleted {
+		row.DeletedAt = pgtype.Timestamptz{Valid: true}
+	}
+	return row
+}
+
+// Las respuestas a un comentario del último nivel quedan al lado de él, así el hilo nunca
+// pasa de maxCommentDepth niveles.
+func TestGetThreadDepth(t *testing.T) {
+	fake := newFakeDB().
+		add("GetPollCommentSettings", db.GetPollCommentSettingsRow{ID: 1, UserID: 9, Status: PollPublished, CommentsMode: CommentsOpen}).
+		add("ListCommentsByPoll",
+			commentRow(1, 0, false), // raíz
+			commentRow(2, 1, false), // nivel 1
+			commentRow(3, 2, true),  // nivel 2, borrado
+			commentRow(4, 3, false), // respuesta al borrado: se queda en el nivel 2
+			commentRow(5, 4, false), // y la respuesta a esa también
+			commentRow(6, 0, false), // otra raíz
+		)
+	s := &CommentService{Queries: db.New(fake)}
+
+	thread, err := s.GetThread(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread.Comments) != 2 || thread.Count != 5 {
+		t.Fatalf("raíces = %d y comentarios = %d, want 2 y 5", len(thread.Comments), thread.Count)
+	}
+
+	tests := []struct {
+		name    string
+		comment *CommentResponse
+		id      int32
+		depth   int
+		replies int
+	}{
+		{"raíz", thread.Comments[0], 1, 0, 1},
+		{"nivel 1", thread.Comments[0].Replies[0], 2, 1, 3},
+		{"nivel 2 borrado", thread.Comments[0].Replies[0].Replies[0], 3, 2, 0},
+		{"respuesta al último nivel", thread.Comments[0].Replies[0].Replies[1], 4, 2, 0},
+		{"respuesta a la respuesta", thread.Comments[0].Replies[0].Replies[2], 5, 2, 0},
+		{"otra raíz", thread.Comments[1], 6, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.comment
+			if c.ID != tt.id || c.Depth != tt.depth || len(c.Replies) != tt.replies {
+				t.Errorf("comentario %d con profundidad %d y %d respuestas, want %d, %d y %d",
+					c.ID, c.Depth, len(c.Replies), tt.id, tt.depth, tt.replies)
+			}
+			if c.Depth >= maxCommentDepth {
+				t.Errorf("profundidad %d, want menos de %d", c.Depth, maxCommentDepth)
+			}
+		})
+	}
+
+	if deleted := thread.Comments[0].Replies[0].Replies[0]; !deleted.Deleted || deleted.UserID != nil || deleted.HTML != "" {
+		t.Errorf("el comentario borrado muestra datos: %+v", deleted)
+	}
+}
+
+func TestGetThreadDisabled(t *testing.T) {
+	fake := newFakeDB().
+		add("GetPollCommentSettings", db.GetPollCommentSettingsRow{ID: 1, UserID: 9, Status: PollPublished, CommentsMode: CommentsDisabled}).
+		add("ListCommentsByPoll", commentRow(1, 0, false))
+	s := &CommentService{Queries: db.New(fake)}
+
+	thread, err := s.GetThread(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread.Comments) != 0 || thread.CanComment || fake.calls["ListCommentsByPoll"] != 0 {
+		t.Errorf("hilo con comentarios desactivados = %+v, want vacío y sin consultar comentarios", thread)
+	}
+}
+
+// Los comentarios solo se filtran en modo reject: en modo flag se publican tal cual.
+func TestValidateBodyFilter(t *testing.T) {
+	tests := []struct {
+		mode string
+		want error
+	}{
+		{FilterReject, InvalidField("body", "comments.blocked_words")},
+		{FilterFlag, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			filter, err := NewContentFilter([]string{"tonto"}, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := &CommentService{Filter: filter}
+			body, err := s.validateBody("  qué tonto  ")
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("validateBody = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil || body != "qué tonto" {
+				t.Errorf("validateBody = (%q, %v), want el texto recortado", body, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Modos del filtro de contenido.
+const (
+	// FilterReject rechaza la encuesta u opción con un error de validación.
+	FilterReject = "reject"
+	// FilterFlag la deja pasar pero crea un reporte automático para la cola de moderación.
+	// Los comentarios no tienen reportes: en este modo no se filtran.
+	FilterFlag = "flag"
+)
+
+// ContentFilter busca palabras o frases prohibidas en títulos y opciones.
+// La comparación ignora mayúsculas y tildes y solo considera palabras completas.
+type ContentFilter struct {
+	Mode  string
+	terms []string
+}
+
+// NewContentFilter crea un filtro con la lista de palabras dada. Las entradas pueden ser frases.
+func NewContentFilter(words []string, mode string) (*ContentFilter, error) {
+	if mode != FilterReject && mode != FilterFlag {
+		return nil, fmt.Errorf("modo de filtro %q inválido: debe ser %s o %s", mode, FilterReject, FilterFlag)
+	}
+
+	filter := &ContentFilter{Mode: mode}
+	for _, word := range words {
+		if term := normalizeText(word); term != "" {
+			filter.terms = append(filter.terms, term)
+		}
+	}
+	return filter, nil
+}
+
+// LoadWordList lee una lista de palabras, una por línea. Ignora líneas vacías y comentarios (#).
+func LoadWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// Match devuelve el primer término prohibido que aparece en alguno de los textos.
+func (f *ContentFilter) Match(texts ...string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+	for _, text := range texts {
+		// Rodeamos con espacios para comparar solo palabras completas
+		normalized := " " + normalizeText(text) + " "
+		for _, term := range f.terms {
+			if strings.Contains(normalized, " "+term+" ") {
+				return term, true
+			}
+		}
+	}
+	return "", false
+}
+
+// normalizeText pasa a minúsculas, quita tildes y deja las palabras separadas por un espacio.
+func normalizeText(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	db "webpolls/db/sqlc"
+)
+
+func TestContentFilterMatch(t *testing.T) {
+	filter, err := NewContentFilter([]string{"Tonto", "muy malo", "  ", "Ñoño"}, FilterReject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		texts []string
+		term  string
+	}{
+		{[]string{"¿Cuál es tu color favorito?"}, ""},
+		{[]string{"eres TONTO"}, "tonto"},
+		{[]string{"¡Tóntó!"}, "tonto"},
+		{[]string{"es muy   malo, de verdad"}, "muy malo"},
+		{[]string{"ñono"}, "nono"},
+		// Solo palabras completas
+		{[]string{"tontería"}, ""},
+		{[]string{"muy maloso"}, ""},
+		{[]string{"título limpio", "opción tonta", "opción tonto"}, "tonto"},
+	}
+	for _, tt := range tests {
+		term, ok := filter.Match(tt.texts...)
+		if ok != (tt.term != "") || term != tt.term {
+			t.Errorf("Match(%q) = (%q, %v), want %q", tt.texts, term, ok, tt.term)
+		}
+	}
+
+	var none *ContentFilter
+	if _, ok := none.Match("tonto"); ok {
+		t.Error("un filtro nil no debería encontrar nada")
+	}
+}
+
+func TestNewContentFilterMode(t *testing.T) {
+	for _, mode := range []string{FilterReject, FilterFlag} {
+		if _, err := NewContentFilter(nil, mode); err != nil {
+			t.Errorf("NewContentFilter(%q) = %v", mode, err)
+		}
+	}
+	if _, err := NewContentFilter(nil, "ignore"); err == nil {
+		t.Error("NewContentFilter aceptó un modo desconocido")
+	}
+}
+
+func TestLoadWordList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	content := "# palabras prohibidas\ntonto\n\n  muy malo  \n#otra\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	words, err := LoadWordList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"tonto", "muy malo"}; !reflect.DeepEqual(words, want) {
+		t.Errorf("LoadWordList = %q, want %q", words, want)
+	}
+}
+
+// Las validaciones del reporte se hacen antes de tocar la base.
+func TestReportPollValidation(t *testing.T) {
+	s := &ReportService{}
+	tests := []struct {
+		reason  string
+		details string
+		want    string
+	}{
+		{"aburrida", "", "motivo de reporte inválido"},
+		{ReportReasonFiltered, "", "motivo de reporte inválido"}, // solo lo usa el filtro
+		{ReportReasonSpam, strings.Repeat("x", maxReportDetails+1), fmt.Sprintf("los detalles no pueden superar los %d caracteres", maxReportDetails)},
+	}
+	for _, tt := range tests {
+		_, err := s.ReportPoll(context.Background(), 1, 2, tt.reason, tt.details)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ReportPoll(%q) = %v, want %q", tt.reason, err, tt.want)
+		}
+	}
+}
+
+// El reporte del filtro cuenta para el umbral igual que los de los usuarios.
+func TestFlagPoll(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports *ReportService
+		open    int64
+		hidden  bool
+	}{
+		{"sin servicio de reportes", nil, 5, false},
+		{"umbral desactivado", &ReportService{}, 5, false},
+		{"bajo el umbral", &ReportService{AutoHideThreshold: 3}, 2, false},
+		{"llega al umbral", &ReportService{AutoHideThreshold: 3}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB().add("CountOpenReportsByPoll", tt.open)
+			if err := tt.reports.flagPoll(context.Background(), db.New(fake), 1, "spam"); err != nil {
+				t.Fatalf("flagPoll: %v", err)
+			}
+			if fake.calls["CreateReport"] != 1 {
+				t.Errorf("se crearon %d reportes, want 1", fake.calls["CreateReport"])
+			}
+			if hidden := fake.calls["SetPollHidden"] == 1; hidden != tt.hidden {
+				t.Errorf("oculta = %v, want %v", hidden, tt.hidden)
+			}
+			if audited := fake.calls["CreateAuditLogEntry"] == 1; audited != tt.hidden {
+				t.Errorf("auditada = %v, want %v", audited, tt.hidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrorKind clasifica los errores de dominio. Los handlers eligen el status HTTP a partir
+// del tipo, así los servicios no necesitan saber nada de HTTP.
+type ErrorKind string
+
+const (
+	KindNotFound     ErrorKind = "not_found"
+	KindForbidden    ErrorKind = "forbidden"
+	KindValidation   ErrorKind = "validation"
+	KindConflict     ErrorKind = "conflict"
+	KindUnauthorized ErrorKind = "unauthorized"
+)
+
+// DomainError es un error esperado del dominio: tiene un tipo, un mensaje traducible y,
+// para las validaciones, el detalle por campo.
+type DomainError struct {
+	Kind   ErrorKind
+	Err    *i18n.Error
+	Fields []FieldError
+}
+
+// FieldError es un problema con un campo concreto del formulario o del JSON.
+type FieldError struct {
+	Field string
+	Err   *i18n.Error
+}
+
+func newDomainError(kind ErrorKind, code string, args ...any) *DomainError {
+	return &DomainError{Kind: kind, Err: i18n.NewError(code, args...)}
+}
+
+// NotFound indica que el recurso no existe (o que no es visible para quien lo pide).
+func NotFound(code string, args ...any) *DomainError {
+	return newDomainError(KindNotFound, code, args...)
+}
+
+// Forbidden indica que el recurso existe pero el usuario no puede operar sobre él.
+func Forbidden(code string, args ...any) *DomainError {
+	return newDomainError(KindForbidden, code, args...)
+}
+
+// Invalid indica datos de entrada que no cumplen las reglas del dominio.
+func Invalid(code string, args ...any) *DomainError {
+	return newDomainError(KindValidation, code, args...)
+}
+
+// InvalidField es como Invalid pero señala el campo responsable.
+func InvalidField(field, code string, args ...any) *DomainError {
+	err := Invalid(code, args...)
+	err.Fields = []FieldError{{Field: field, Err: err.Err}}
+	return err
+}
+
+// Conflict indica que la operación choca con el estado actual (duplicados, ya revisado...).
+func Conflict(code string, args ...any) *DomainError {
+	return newDomainError(KindConflict, code, args...)
+}
+
+// Unauthorized indica credenciales o códigos incorrectos.
+func Unauthorized(code string, args ...any) *DomainError {
+	return newDomainError(KindUnauthorized, code, args...)
+}
+
+func (e *DomainError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap expone el mensaje para que i18n.Message pueda traducirlo.
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// Is compara por tipo y código, así errors.Is funciona con los errores centinela.
+func (e *DomainError) Is(target error) bool {
+	t, ok := target.(*DomainError)
+	return ok && t.Kind == e.Kind && t.Err.Code == e.Err.Code
+}
+
+// sqlStateUniqueViolation es el SQLSTATE de Postgres para una violación de UNIQUE.
+const sqlStateUniqueViolation = "23505"
+
+// uniqueViolation convierte la violación de la restricción constraint en un error de
+// validación del campo field. Cualquier otro error se devuelve sin cambios.
+func uniqueViolation(err error, constraint, field, code string, args ...any) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == sqlStateUniqueViolation && pgErr.ConstraintName == constraint {
+		return InvalidField(field, code, args...)
+	}
+	return err
+}
+
+// sqlStateForeignKeyViolation es el SQLSTATE de Postgres para una violación de FOREIGN KEY.
+const sqlStateForeignKeyViolation = "23503"
+
+// foreignKeyViolation convierte la violación de la restricción constraint (por ejemplo,
+// borrar una fila que otra tabla todavía usa) en un conflicto. Cualquier otro error se
+// devuelve sin cambios.
+func foreignKeyViolation(err error, constraint, code string, args ...any) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == sqlStateForeignKeyViolation && pgErr.ConstraintName == constraint {
+		return Conflict(code, args...)
+	}
+	return err
+}
+
+// validation junta los errores de varios campos para devolverlos todos de una vez.
+type validation struct {
+	fields []FieldError
+}
+
+func (v *validation) add(field, code string, args ...any) {
+	v.fields = append(v.fields, FieldError{Field: field, Err: i18n.NewError(code, args...)})
+}
+
+// merge incorpora los campos de otro error de validación; cualquier otro error se ignora.
+func (v *validation) merge(err error) {
+	if domain, ok := err.(*DomainError); ok {
+		v.fields = append(v.fields, domain.Fields...)
+	}
+}
+
+// err devuelve nil si no hubo problemas. El mensaje general es el del primer campo,
+// que es lo que se muestra en el toast.
+func (v *validation) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &DomainError{Kind: KindValidation, Err: v.fields[0].Err, Fields: v.fields}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestDomainErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"mismo centinela", ErrPollNotFound, ErrPollNotFound, true},
+		{"mismo tipo y código", NotFound("poll.not_found"), ErrPollNotFound, true},
+		{"envuelto", fmt.Errorf("cargando: %w", ErrPollNotFound), ErrPollNotFound, true},
+		{"mismo código, otro tipo", Forbidden("poll.not_found"), ErrPollNotFound, false},
+		{"mismo tipo, otro código", NotFound("comment.not_found"), ErrPollNotFound, false},
+		{"los argumentos no cuentan", InvalidField("options", "option.duplicate", "a"), Invalid("option.duplicate", "b"), true},
+		{"error común", errors.New("poll.not_found"), ErrPollNotFound, false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("%s: errors.Is = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidation(t *testing.T) {
+	var v validation
+	if err := v.err(); err != nil {
+		t.Fatalf("sin problemas err() = %v, want nil", err)
+	}
+
+	v.add("question", "poll.question_required")
+	v.merge(InvalidField("options", "poll.min_options"))
+	v.merge(errors.New("se ignora"))
+	err := v.err()
+
+	var domain *DomainError
+	if !errors.As(err, &domain) || domain.Kind != KindValidation {
+		t.Fatalf("err() = %v, want un error de validación", err)
+	}
+	if domain.Err.Code != "poll.question_required" {
+		t.Errorf("el mensaje general es %q, want el del primer campo", domain.Err.Code)
+	}
+	if len(domain.Fields) != 2 || domain.Fields[0].Field != "question" || domain.Fields[1].Field != "options" {
+		t.Errorf("Fields = %+v, want question y options", domain.Fields)
+	}
+}
+
+func TestUniqueViolation(t *testing.T) {
+	titleTaken := &pgconn.PgError{Code: sqlStateUniqueViolation, ConstraintName: "unique_poll_title"}
+	tests := []struct {
+		name  string
+		err   error
+		field string // vacío si el error debe volver sin cambios
+	}{
+		{"título repetido", titleTaken, "question"},
+		{"envuelto", fmt.Errorf("creando encuesta: %w", titleTaken), "question"},
+		{"otra restricción", &pgconn.PgError{Code: sqlStateUniqueViolation, ConstraintName: "unique_option"}, ""},
+		{"otro SQLSTATE", &pgconn.PgError{Code: "23503", ConstraintName: "unique_poll_title"}, ""},
+		{"error común", errors.New("conexión perdida"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uniqueViolation(tt.err, "unique_poll_title", "question", "poll.title_taken")
+			if tt.field == "" {
+				if err != tt.err {
+					t.Errorf("uniqueViolation = %v, want el error original", err)
+				}
+				return
+			}
+			var domain *DomainError
+			if !errors.As(err, &domain) || domain.Kind != KindValidation || len(domain.Fields) != 1 || domain.Fields[0].Field != tt.field {
+				t.Fatalf("uniqueViolation = %#v, want error de validación en %s", err, tt.field)
+			}
+			if domain.Err.Code != "poll.title_taken" {
+				t.Errorf("código = %q, want poll.title_taken", domain.Err.Code)
+			}
+		})
+	}
+}
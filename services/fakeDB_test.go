@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeDB reemplaza a Postgres en los tests de los servicios. Cada consulta de sqlc se
+// reconoce por su nombre ("-- name: GetPollByID :many") y devuelve las filas cargadas con
+// add: el struct que genera sqlc para la consulta, o el valor suelto si devuelve una sola
+// columna. Una consulta :one sin filas responde pgx.ErrNoRows y una fila de tipo error se
+// devuelve como error.
+type fakeDB struct {
+	rows map[string][]any
+	// calls cuenta cuántas veces se ejecutó cada consulta
+	calls map[string]int
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{rows: map[string][]any{}, calls: map[string]int{}}
+}
+
+func (d *fakeDB) add(query string, rows ...any) *fakeDB {
+	d.rows[query] = append(d.rows[query], rows...)
+	return d
+}
+
+func (d *fakeDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	d.calls[queryName(sql)]++
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (d *fakeDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	name := queryName(sql)
+	d.calls[name]++
+	return &fakeRows{rows: d.rows[name], next: -1}, nil
+}
+
+func (d *fakeDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	name := queryName(sql)
+	d.calls[name]++
+	rows := d.rows[name]
+	if len(rows) == 0 {
+		return fakeRow{err: pgx.ErrNoRows}
+	}
+	return fakeRow{value: rows[0]}
+}
+
+func queryName(sql string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(sql, "-- name: "), " ")
+	return name
+}
+
+type fakeRow struct {
+	value any
+	err   error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return scanFake(r.value, dest)
+}
+
+// scanFake copia value en dest: directo si hay un solo destino o campo por campo si es el
+// struct de la fila.
+func scanFake(value any, dest []any) error {
+	if err, ok := value.(error); ok {
+		return err
+	}
+	v := reflect.ValueOf(value)
+	if len(dest) == 1 {
+		reflect.ValueOf(dest[0]).Elem().Set(v)
+		return nil
+	}
+	if v.Kind() != reflect.Struct || v.NumField() != len(dest) {
+		return fmt.Errorf("fila %T con %d columnas para %d destinos", value, v.NumField(), len(dest))
+	}
+	for i := range dest {
+		reflect.ValueOf(dest[i]).Elem().Set(v.Field(i))
+	}
+	return nil
+}
+
+type fakeRows struct {
+	rows []any
+	next int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Next() bool                                   { r.next++; return r.next < len(r.rows) }
+func (r *fakeRows) Scan(dest ...any) error                       { return scanFake(r.rows[r.next], dest) }
+func (r *fakeRows) Values() ([]any, error)                       { return nil, nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
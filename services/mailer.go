@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Mailer envía correos de texto plano. El servicio de notificaciones no sabe cómo salen:
+// main elige la implementación según MAILER.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer solo registra los correos; sirve para desarrollo.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	slog.InfoContext(ctx, "email (not sent)", "to", to, "subject", subject, "body", body)
+	return nil
+}
+
+// SMTPMailer envía por SMTP, con STARTTLS si el servidor lo ofrece y autenticación PLAIN
+// si hay usuario.
+type SMTPMailer struct {
+	// Addr es host:puerto del servidor
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	_, span := tracer.Start(ctx, "SMTPMailer.Send")
+	defer span.End()
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return fmt.Errorf("SMTP_ADDR inválido: %w", err)
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	return smtp.SendMail(m.Addr, auth, m.From, []string{to}, buildMail(m.From, to, subject, body))
+}
+
+// buildMail arma el mensaje con sus cabeceras. Los saltos de línea del asunto se quitan
+// para que no se puedan inyectar cabeceras, y se codifica por si trae acentos.
+func buildMail(from, to, subject, body string) []byte {
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(msg.String())
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"bytes"
+	"html"
+	"log/slog"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Los comentarios se escriben en markdown. goldmark no deja pasar HTML crudo (sin
+// WithUnsafe lo omite) y bluemonday limpia igual la salida con la política UGC, así que
+// un enlace javascript: o un atributo inventado no llegan a la página aunque el parser cambie.
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.Linkify, extension.Strikethrough),
+		goldmark.WithRendererOptions(gmhtml.WithHardWraps()),
+	)
+	markdownPolicy = newMarkdownPolicy()
+)
+
+func newMarkdownPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// RenderMarkdown convierte el markdown de un comentario en HTML seguro.
+func RenderMarkdown(src string) string {
+	src = strings.ReplaceAll(src, "\x00", "")
+	var out bytes.Buffer
+	if err := markdown.Convert([]byte(src), &out); err != nil {
+		slog.Error("Failed to render markdown", "error", err)
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return strings.TrimSpace(markdownPolicy.Sanitize(out.String()))
+}
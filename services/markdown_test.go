@@ -0,0 +1,71 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		href string // vacío si no debe salir ningún enlace
+	}{
+		{"https", "[sitio](https://example.com/a?b=1)", `href="https://example.com/a?b=1"`},
+		{"http", "[sitio](http://example.com)", `href="http://example.com"`},
+		{"mailto", "[correo](mailto:ana@example.com)", `href="mailto:ana@example.com"`},
+		{"autolink", "mira https://example.com/x.", `href="https://example.com/x"`},
+		{"javascript", "[clic](javascript:alert(1))", ""},
+		{"javascript con mayúsculas", "[clic](JaVaScRiPt:alert(1))", ""},
+		{"data", "[clic](data:text/html;base64,PHNjcmlwdD4=)", ""},
+		{"vbscript", "[clic](vbscript:msgbox)", ""},
+		{"entidad en el esquema", "[clic](jav&#x61;script:alert(1))", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMarkdown(tt.src)
+			if strings.Contains(strings.ToLower(got), "script:") || strings.Contains(got, "data:") {
+				t.Fatalf("RenderMarkdown(%q) = %q, dejó pasar un esquema peligroso", tt.src, got)
+			}
+			if tt.href == "" {
+				if strings.Contains(got, "href=") {
+					t.Errorf("RenderMarkdown(%q) = %q, want sin enlace", tt.src, got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.href) || !strings.Contains(got, `rel="nofollow`) {
+				t.Errorf("RenderMarkdown(%q) = %q, want %s con rel nofollow", tt.src, got, tt.href)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownEscaping(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    string
+		notWant string
+	}{
+		{"html crudo", "<script>alert(1)</script>", "", "<script"},
+		{"html en línea", "hola <img src=x onerror=alert(1)>", "hola", "onerror"},
+		{"atributo en enlace", `[x](https://example.com" onclick="alert(1))`, "&#34; onclick=&#34;", ` onclick="`},
+		{"comparaciones", "1 < 2 && 3 > 2", "1 &lt; 2 &amp;&amp; 3 &gt; 2", ""},
+		{"código", "`<b>`", "<code>&lt;b&gt;</code>", "<b>"},
+		{"bloque de código", "```\n<div>\n```", "&lt;div&gt;", "<div>"},
+		{"negrita y cursiva", "**fuerte** y *suave*", "<strong>fuerte</strong> y <em>suave</em>", ""},
+		{"salto de línea", "uno\ndos", "uno<br>\ndos", ""},
+		{"carácter nulo", "a\x00b", "ab", "\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMarkdown(tt.src)
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("RenderMarkdown(%q) = %q, want que contenga %q", tt.src, got, tt.want)
+			}
+			if tt.notWant != "" && strings.Contains(got, tt.notWant) {
+				t.Errorf("RenderMarkdown(%q) = %q, no debería contener %q", tt.src, got, tt.notWant)
+			}
+		})
+	}
+}
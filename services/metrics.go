@@ -0,0 +1,102 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Métricas de negocio expuestas en /metrics.
+var (
+	votesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webpolls_votes_total",
+		Help: "Votos emitidos por encuesta.",
+	}, []string{"poll_id"})
+
+	sseClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "webpolls_sse_clients",
+		Help: "Clientes SSE conectados.",
+	})
+
+	sseDroppedMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "webpolls_sse_dropped_messages_total",
+		Help: "Mensajes SSE descartados porque el cliente no los consumía a tiempo.",
+	})
+
+	loginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webpolls_logins_total",
+		Help: "Intentos de login por resultado (success, failure).",
+	}, []string{"result"})
+
+	webhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webpolls_webhook_deliveries_total",
+		Help: "Intentos de entrega de webhooks por resultado (delivered, pending = se reintenta, failed).",
+	}, []string{"result"})
+)
+
+func recordVote(pollID int32) {
+	votesTotal.WithLabelValues(strconv.Itoa(int(pollID))).Inc()
+}
+
+func recordWebhookDelivery(result string) {
+	webhookDeliveriesTotal.WithLabelValues(result).Inc()
+}
+
+// RecordLogin cuenta un intento de login. Con 2FA el éxito se cuenta al completar el segundo paso.
+func RecordLogin(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	loginsTotal.WithLabelValues(result).Inc()
+}
+
+// poolCollector publica las estadísticas del pool de conexiones de pgx.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired     *prometheus.Desc
+	idle         *prometheus.Desc
+	total        *prometheus.Desc
+	max          *prometheus.Desc
+	acquireCount *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+}
+
+// NewPoolCollector crea un collector de Prometheus para las estadísticas de pool.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &poolCollector{
+		pool:         pool,
+		acquired:     prometheus.NewDesc("webpolls_db_pool_acquired_conns", "Conexiones en uso.", nil, nil),
+		idle:         prometheus.NewDesc("webpolls_db_pool_idle_conns", "Conexiones libres.", nil, nil),
+		total:        prometheus.NewDesc("webpolls_db_pool_total_conns", "Conexiones abiertas.", nil, nil),
+		max:          prometheus.NewDesc("webpolls_db_pool_max_conns", "Máximo de conexiones del pool.", nil, nil),
+		acquireCount: prometheus.NewDesc("webpolls_db_pool_acquires_total", "Conexiones obtenidas del pool.", nil, nil),
+		waitCount:    prometheus.NewDesc("webpolls_db_pool_empty_acquires_total", "Veces que hubo que esperar una conexión libre.", nil, nil),
+		waitDuration: prometheus.NewDesc("webpolls_db_pool_acquire_wait_seconds_total", "Tiempo total esperando conexiones.", nil, nil),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquireCount
+	ch <- c.waitCount
+	ch <- c.waitDuration
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBusinessMetrics(t *testing.T) {
+	success := testutil.ToFloat64(loginsTotal.WithLabelValues("success"))
+	failure := testutil.ToFloat64(loginsTotal.WithLabelValues("failure"))
+	RecordLogin(true)
+	RecordLogin(false)
+	RecordLogin(false)
+	if got := testutil.ToFloat64(loginsTotal.WithLabelValues("success")) - success; got != 1 {
+		t.Errorf("logins exitosos = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(loginsTotal.WithLabelValues("failure")) - failure; got != 2 {
+		t.Errorf("logins fallidos = %v, want 2", got)
+	}
+
+	votes := testutil.ToFloat64(votesTotal.WithLabelValues("4242"))
+	recordVote(4242)
+	if got := testutil.ToFloat64(votesTotal.WithLabelValues("4242")) - votes; got != 1 {
+		t.Errorf("votos de la encuesta 4242 = %v, want 1", got)
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Tipos de notificación.
+const (
+	// NotificationVote avisa al dueño que su encuesta recibió votos
+	NotificationVote = "vote"
+	// NotificationClosed avisa al dueño y a quienes votaron que la votación cerró
+	NotificationClosed = "closed"
+	// NotificationComment avisa al dueño de un comentario nuevo y al autor de una respuesta
+	NotificationComment = "comment"
+)
+
+// NotificationEvent es el evento SSE que recibe un usuario cuando cambia su cantidad de no leídas.
+const NotificationEvent = "notifications"
+
+const (
+	// maxNotifications es la cantidad de notificaciones que muestra la bandeja
+	maxNotifications = 50
+	// digestBatchSize es la cantidad de resúmenes que se toman por vuelta
+	digestBatchSize = 20
+)
+
+// NotificationService guarda las notificaciones internas, avisa por SSE y manda el
+// resumen diario por correo a quien lo pidió.
+type NotificationService struct {
+	Queries *db.Queries
+	// SSE actualiza en vivo el contador de la campana (nil = sin aviso)
+	SSE *SSEBroker
+	// Mailer envía el resumen diario (nil = sin correos)
+	Mailer Mailer
+	// BaseURL arma los enlaces absolutos del resumen
+	BaseURL string
+}
+
+// NewNotificationService crea una nueva instancia de NotificationService.
+func NewNotificationService(queries *db.Queries, sse *SSEBroker) *NotificationService {
+	return &NotificationService{Queries: queries, SSE: sse}
+}
+
+// NotificationResponse es una notificación de la bandeja. Los votos y comentarios sin leer
+// de una encuesta se agrupan: Count dice cuántos son y Actor es el último.
+type NotificationResponse struct {
+	ID        int32     `json:"id"`
+	Kind      string    `json:"kind"`
+	PollID    int32     `json:"poll_id"`
+	PollTitle string    `json:"poll_title"`
+	Actor     string    `json:"actor"`
+	Count     int32     `json:"count"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Read      bool      `json:"read"`
+}
+
+// NotificationPreferences son los avisos que quiere recibir el usuario.
+type NotificationPreferences struct {
+	OnVote      bool `json:"on_vote"`
+	OnClose     bool `json:"on_close"`
+	OnComment   bool `json:"on_comment"`
+	EmailDigest bool `json:"email_digest"`
+}
+
+// DefaultNotificationPreferences son las preferencias de quien nunca las cambió.
+var DefaultNotificationPreferences = NotificationPreferences{OnVote: true, OnClose: true, OnComment: true}
+
+func (p NotificationPreferences) wants(kind string) bool {
+	switch kind {
+	case NotificationVote:
+		return p.OnVote
+	case NotificationClosed:
+		return p.OnClose
+	case NotificationComment:
+		return p.OnComment
+	}
+	return false
+}
+
+// Notify registra un aviso para userID causado por actorID. No se avisa a nadie de lo que
+// hizo él mismo. Como Emit de los webhooks, acepta un servicio nil y los errores solo se
+// registran: una notificación perdida no debe hacer fallar un voto.
+func (s *NotificationService) Notify(ctx context.Context, userID int32, kind string, pollID, actorID int32) {
+	if s == nil || userID == actorID {
+		return
+	}
+	ctx, span := tracer.Start(ctx, "NotificationService.Notify")
+	defer span.End()
+
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err == nil && !prefs.wants(kind) {
+		return
+	}
+	if err == nil {
+		err = s.Queries.AddNotification(ctx, db.AddNotificationParams{
+			UserID:  userID,
+			Kind:    kind,
+			PollID:  pollID,
+			ActorID: pgtype.Int4{Int32: actorID, Valid: true},
+		})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error adding notification", "user_id", userID, "kind", kind, "poll_id", pollID, "error", err)
+		return
+	}
+	s.publishUnread(ctx, userID)
+}
+
+// NotifyPollClosed avisa del cierre al dueño y a todos los que votaron.
+func (s *NotificationService) NotifyPollClosed(ctx context.Context, pollID int32) {
+	if s == nil {
+		return
+	}
+	ctx, span := tracer.Start(ctx, "NotificationService.NotifyPollClosed")
+	defer span.End()
+
+	users, err := s.Queries.AddClosedNotifications(ctx, pollID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error adding close notifications", "poll_id", pollID, "error", err)
+		return
+	}
+	for _, userID := range users {
+		s.publishUnread(ctx, userID)
+	}
+}
+
+// List devuelve las últimas notificaciones del usuario, las más recientes primero.
+func (s *NotificationService) List(ctx context.Context, userID int32) ([]NotificationResponse, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.List")
+	defer span.End()
+
+	rows, err := s.Queries.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:           userID,
+		MaxNotifications: maxNotifications,
+	})
+	if err != nil {
+		return nil, err
+	}
+	notifications := make([]NotificationResponse, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, NotificationResponse{
+			ID:        row.ID,
+			Kind:      row.Kind,
+			PollID:    row.PollID,
+			PollTitle: row.PollTitle,
+			Actor:     row.ActorUsername.String,
+			Count:     row.Count,
+			UpdatedAt: row.UpdatedAt.Time,
+			Read:      row.ReadAt.Valid,
+		})
+	}
+	return notifications, nil
+}
+
+// UnreadCount es el número que muestra la campana.
+func (s *NotificationService) UnreadCount(ctx context.Context, userID int32) (int64, error) {
+	return s.Queries.CountUnreadNotifications(ctx, userID)
+}
+
+// MarkRead marca una notificación como leída y devuelve su encuesta, para ir a verla.
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID int32) (int32, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.MarkRead")
+	defer span.End()
+
+	pollID, err := s.Queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{ID: id, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, NotFound("notification.not_found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	s.publishUnread(ctx, userID)
+	return pollID, nil
+}
+
+// MarkAllRead vacía el contador del usuario.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int32) error {
+	ctx, span := tracer.Start(ctx, "NotificationService.MarkAllRead")
+	defer span.End()
+
+	if err := s.Queries.MarkAllNotificationsRead(ctx, userID); err != nil {
+		return err
+	}
+	s.publishUnread(ctx, userID)
+	return nil
+}
+
+// GetPreferences devuelve las preferencias del usuario, o las de por defecto si nunca las guardó.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int32) (NotificationPreferences, error) {
+	row, err := s.Queries.GetNotificationPreferences(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultNotificationPreferences, nil
+	}
+	if err != nil {
+		return NotificationPreferences{}, err
+	}
+	return NotificationPreferences{
+		OnVote:      row.OnVote,
+		OnClose:     row.OnClose,
+		OnComment:   row.OnComment,
+		EmailDigest: row.EmailDigest,
+	}, nil
+}
+
+// SetPreferences guarda los avisos que quiere recibir el usuario.
+func (s *NotificationService) SetPreferences(ctx context.Context, userID int32, prefs NotificationPreferences) error {
+	ctx, span := tracer.Start(ctx, "NotificationService.SetPreferences")
+	defer span.End()
+
+	return s.Queries.UpsertNotificationPreferences(ctx, db.UpsertNotificationPreferencesParams{
+		UserID:      userID,
+		OnVote:      prefs.OnVote,
+		OnClose:     prefs.OnClose,
+		OnComment:   prefs.OnComment,
+		EmailDigest: prefs.EmailDigest,
+	})
+}
+
+// DigestEnabled indica si hay con qué mandar el resumen por correo.
+func (s *NotificationService) DigestEnabled() bool {
+	return s.Mailer != nil
+}
+
+// SendDigests manda el resumen a los usuarios que lo pidieron, tienen avisos nuevos sin
+// leer y no recibieron otro en las últimas 24 horas. Devuelve cuántos tomó.
+func (s *NotificationService) SendDigests(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.SendDigests")
+	defer span.End()
+
+	// Tomar el lote ya marca el envío, así otra réplica no manda el mismo resumen
+	users, err := s.Queries.ClaimDueDigests(ctx, digestBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, user := range users {
+		if err := s.sendDigest(ctx, user); err != nil {
+			slog.ErrorContext(ctx, "error sending notification digest", "user_id", user.UserID, "error", err)
+		}
+	}
+	return len(users), nil
+}
+
+// RunDigest revisa cada every si hay resúmenes para mandar, hasta que se cancele ctx.
+func (s *NotificationService) RunDigest(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := s.SendDigests(ctx)
+				if err != nil {
+					slog.Error("error enviando resúmenes de notificaciones", "error", err)
+				}
+				if err != nil || n < digestBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// sendDigest arma el resumen en el idioma del usuario y marca como enviadas sus notificaciones.
+func (s *NotificationService) sendDigest(ctx context.Context, user db.ClaimDueDigestsRow) error {
+	rows, err := s.Queries.ListDigestNotifications(ctx, user.UserID)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+
+	locale := i18n.Default
+	if user.Locale.Valid && i18n.IsSupported(user.Locale.String) {
+		locale = user.Locale.String
+	}
+	ctx = i18n.WithLocale(ctx, locale)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\n\n", i18n.T(ctx, "notification.digest_greeting", user.Username))
+	ids := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		n := NotificationResponse{Kind: row.Kind, PollTitle: row.PollTitle, Actor: row.ActorUsername.String, Count: row.Count}
+		fmt.Fprintf(&body, "- %s\n  %s/polls/%d\n", NotificationText(ctx, n), s.BaseURL, row.PollID)
+		ids = append(ids, row.ID)
+	}
+	fmt.Fprintf(&body, "\n%s\n%s/notifications\n", i18n.T(ctx, "notification.digest_footer"), s.BaseURL)
+
+	subject := i18n.T(ctx, "notification.digest_subject", len(rows))
+	if err := s.Mailer.Send(ctx, user.Email, subject, body.String()); err != nil {
+		return err
+	}
+	return s.Queries.MarkNotificationsEmailed(ctx, ids)
+}
+
+// publishUnread manda por SSE la cantidad de no leídas del usuario.
+func (s *NotificationService) publishUnread(ctx context.Context, userID int32) {
+	if s.SSE == nil {
+		return
+	}
+	count, err := s.UnreadCount(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error counting unread notifications", "user_id", userID, "error", err)
+		return
+	}
+	s.SSE.PublishToUser(ctx, userID, NotificationEvent, fmt.Appendf(nil, `{"unread":%d}`, count))
+}
+
+// NotificationText describe la notificación en el idioma del contexto. Lo usan la bandeja
+// y el resumen por correo.
+func NotificationText(ctx context.Context, n NotificationResponse) string {
+	actor := n.Actor
+	if actor == "" {
+		actor = i18n.T(ctx, "notification.someone")
+	}
+	switch n.Kind {
+	case NotificationVote:
+		if n.Count > 1 {
+			return i18n.T(ctx, "notification.votes", actor, n.Count-1, n.PollTitle)
+		}
+		return i18n.T(ctx, "notification.vote", actor, n.PollTitle)
+	case NotificationComment:
+		if n.Count > 1 {
+			return i18n.T(ctx, "notification.comments", actor, n.Count-1, n.PollTitle)
+		}
+		return i18n.T(ctx, "notification.comment", actor, n.PollTitle)
+	default:
+		return i18n.T(ctx, "notification.closed", n.PollTitle)
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"testing"
+	db "webpolls/db/sqlc"
+)
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefs   []any // fila de preferencias guardada (ninguna = valores por defecto)
+		kind    string
+		actorID int32
+		want    bool
+	}{
+		{"voto con preferencias por defecto", nil, NotificationVote, 2, true},
+		{"su propio voto", nil, NotificationVote, 1, false},
+		{"comentarios apagados", []any{db.NotificationPreference{UserID: 1, OnVote: true, OnClose: true}}, NotificationComment, 2, false},
+		{"votos encendidos", []any{db.NotificationPreference{UserID: 1, OnVote: true}}, NotificationVote, 2, true},
+		{"tipo desconocido", []any{db.NotificationPreference{UserID: 1, OnVote: true, OnClose: true, OnComment: true}}, "poll.edited", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB().add("GetNotificationPreferences", tt.prefs...)
+			s := NewNotificationService(db.New(fake), nil)
+
+			s.Notify(context.Background(), 1, tt.kind, 10, tt.actorID)
+			if got := fake.calls["AddNotification"] == 1; got != tt.want {
+				t.Errorf("se guardó el aviso = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Los servicios que avisan aceptan un NotificationService nil.
+func TestNotifyNil(t *testing.T) {
+	var s *NotificationService
+	s.Notify(context.Background(), 1, NotificationVote, 10, 2)
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Roles de los miembros de una organización.
+const (
+	// RoleOwner administra la organización y sus miembros, y también sus encuestas
+	RoleOwner = "owner"
+	// RoleEditor crea y administra las encuestas de la organización
+	RoleEditor = "editor"
+	// RoleViewer ve las encuestas de la organización y vota en las que son solo para miembros
+	RoleViewer = "viewer"
+)
+
+// OrgRoles son los roles válidos, de más a menos permisos.
+var OrgRoles = []string{RoleOwner, RoleEditor, RoleViewer}
+
+const maxOrgNameLength = 100
+
+// ErrOrgNotFound se devuelve cuando la organización no existe o quien consulta no es miembro.
+var ErrOrgNotFound = NotFound("org.not_found")
+
+// Organization es una organización vista por uno de sus miembros (Role es el suyo).
+type Organization struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CanManagePolls indica si el rol alcanza para crear y administrar encuestas de la organización.
+func (o *Organization) CanManagePolls() bool {
+	return canManageOrgPolls(o.Role)
+}
+
+// OrgMember es un miembro de una organización.
+type OrgMember struct {
+	UserID   int32     `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// OrganizationService maneja las organizaciones y sus miembros. Los permisos sobre las
+// encuestas de cada organización los aplica PollService.
+type OrganizationService struct {
+	Queries *db.Queries
+	DB      *pgxpool.Pool
+}
+
+// NewOrganizationService crea una nueva instancia de OrganizationService.
+func NewOrganizationService(queries *db.Queries, db *pgxpool.Pool) *OrganizationService {
+	return &OrganizationService{Queries: queries, DB: db}
+}
+
+// CreateOrganization crea una organización con userID como dueño.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID int32, name string) (*Organization, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.CreateOrganization")
+	defer span.End()
+
+	name, err := validateOrgName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+	org, err := qtx.CreateOrganization(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := qtx.UpsertOrganizationMember(ctx, db.UpsertOrganizationMemberParams{
+		OrgID:  org.ID,
+		UserID: userID,
+		Role:   RoleOwner,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &Organization{ID: org.ID, Name: org.Name, Role: RoleOwner, CreatedAt: org.CreatedAt.Time}, nil
+}
+
+// ListOrganizations devuelve las organizaciones de las que userID es miembro.
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID int32) ([]Organization, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.ListOrganizations")
+	defer span.End()
+
+	rows, err := s.Queries.ListUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	orgs := make([]Organization, 0, len(rows))
+	for _, row := range rows {
+		orgs = append(orgs, Organization{ID: row.ID, Name: row.Name, Role: row.Role, CreatedAt: row.CreatedAt.Time})
+	}
+	return orgs, nil
+}
+
+// GetOrganization devuelve la organización y sus miembros. Solo la ven sus miembros.
+func (s *OrganizationService) GetOrganization(ctx context.Context, orgID, userID int32) (*Organization, []OrgMember, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.GetOrganization")
+	defer span.End()
+
+	org, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	members, err := s.members(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return org, members, nil
+}
+
+// RenameOrganization cambia el nombre de la organización (solo dueños).
+func (s *OrganizationService) RenameOrganization(ctx context.Context, orgID, actorID int32, name string) (*Organization, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.RenameOrganization")
+	defer span.End()
+
+	org, err := s.ownerMembership(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Name, err = validateOrgName(name); err != nil {
+		return nil, err
+	}
+	if _, err := s.Queries.RenameOrganization(ctx, db.RenameOrganizationParams{Name: org.Name, ID: orgID}); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// DeleteOrganization borra la organización (solo dueños). Sus encuestas quedan como
+// encuestas de quien las creó.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, orgID, actorID int32) error {
+	ctx, span := tracer.Start(ctx, "OrganizationService.DeleteOrganization")
+	defer span.End()
+
+	if _, err := s.ownerMembership(ctx, orgID, actorID); err != nil {
+		return err
+	}
+	_, err := s.Queries.DeleteOrganization(ctx, orgID)
+	return err
+}
+
+// SetMember agrega a username con el rol role o le cambia el rol si ya es miembro (solo dueños).
+func (s *OrganizationService) SetMember(ctx context.Context, orgID, actorID int32, username, role string) error {
+	ctx, span := tracer.Start(ctx, "OrganizationService.SetMember")
+	defer span.End()
+
+	if _, err := s.ownerMembership(ctx, orgID, actorID); err != nil {
+		return err
+	}
+	if !isOrgRole(role) {
+		return InvalidField("role", "org.invalid_role")
+	}
+	user, err := s.Queries.GetUserByUsername(ctx, strings.TrimSpace(username))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return InvalidField("username", "org.user_not_found", username)
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	if role != RoleOwner {
+		if err := keepAnOwner(ctx, qtx, orgID, user.ID); err != nil {
+			return err
+		}
+	}
+	err = qtx.UpsertOrganizationMember(ctx, db.UpsertOrganizationMemberParams{
+		OrgID:  orgID,
+		UserID: user.ID,
+		Role:   role,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// RemoveMember saca a memberID de la organización. Los dueños pueden sacar a cualquiera y
+// cualquier miembro puede irse; la organización nunca se queda sin dueño.
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, actorID, memberID int32) error {
+	ctx, span := tracer.Start(ctx, "OrganizationService.RemoveMember")
+	defer span.End()
+
+	actor, err := s.membership(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+	if actorID != memberID && actor.Role != RoleOwner {
+		return Forbidden("org.owner_required")
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	if err := keepAnOwner(ctx, qtx, orgID, memberID); err != nil {
+		return err
+	}
+	removed, err := qtx.DeleteOrganizationMember(ctx, db.DeleteOrganizationMemberParams{OrgID: orgID, UserID: memberID})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return NotFound("org.member_not_found")
+	}
+	return tx.Commit(ctx)
+}
+
+// keepAnOwner falla si userID es el único dueño: dejaría a la organización sin nadie que la
+// administre. Bloquea las filas de los dueños hasta el final de la transacción de q, así dos
+// cambios simultáneos no pueden quitar cada uno a uno de los dos últimos dueños.
+func keepAnOwner(ctx context.Context, q *db.Queries, orgID, userID int32) error {
+	owners, err := q.LockOrganizationOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(owners, userID) {
+		return nil
+	}
+	if len(owners) <= 1 {
+		return Conflict("org.last_owner")
+	}
+	return nil
+}
+func (s *OrganizationService) membership(ctx context.Context, orgID, userID int32) (*Organization, error) {
+	row, err := s.Queries.GetUserOrganization(ctx, db.GetUserOrganizationParams{OrgID: orgID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrgNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Organization{ID: row.ID, Name: row.Name, Role: row.Role, CreatedAt: row.CreatedAt.Time}, nil
+}
+
+func (s *OrganizationService) ownerMembership(ctx context.Context, orgID, userID int32) (*Organization, error) {
+	org, err := s.membership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Role != RoleOwner {
+		return nil, Forbidden("org.owner_required")
+	}
+	return org, nil
+}
+
+func (s *OrganizationService) members(ctx context.Context, orgID int32) ([]OrgMember, error) {
+	rows, err := s.Queries.ListOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]OrgMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, OrgMember{UserID: row.UserID, Username: row.Username, Role: row.Role, JoinedAt: row.CreatedAt.Time})
+	}
+	return members, nil
+}
+
+func validateOrgName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", InvalidField("name", "org.name_required")
+	}
+	if len([]rune(name)) > maxOrgNameLength {
+		return "", InvalidField("name", "org.name_too_long", maxOrgNameLength)
+	}
+	return name, nil
+}
+
+func isOrgRole(role string) bool {
+	for _, r := range OrgRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func canManageOrgPolls(role string) bool {
+	return role == RoleOwner || role == RoleEditor
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestCanManageOrgPolls(t *testing.T) {
+	tests := []struct {
+		role string
+		want bool
+	}{
+		{RoleOwner, true},
+		{RoleEditor, true},
+		{RoleViewer, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			if got := canManageOrgPolls(tt.role); got != tt.want {
+				t.Errorf("canManageOrgPolls(%q) = %v, want %v", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanVote(t *testing.T) {
+	org := pgtype.Int4{Int32: 3, Valid: true}
+	user := int32(7)
+	tests := []struct {
+		name        string
+		membersOnly bool
+		orgID       pgtype.Int4
+		userID      *int32
+		role        []any
+		want        bool
+	}{
+		{"abierta", false, org, nil, nil, true},
+		{"sin organización", true, pgtype.Int4{}, nil, nil, true},
+		{"anónimo", true, org, nil, nil, false},
+		{"no es miembro", true, org, &user, nil, false},
+		{"lector", true, org, &user, []any{RoleViewer}, true},
+		{"dueño", true, org, &user, []any{RoleOwner}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PollService{Queries: db.New(newFakeDB().add("GetOrganizationRole", tt.role...))}
+			got, err := s.canVote(context.Background(), tt.membersOnly, tt.orgID, tt.userID)
+			if err != nil {
+				t.Fatalf("canVote: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("canVote = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepAnOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		owners []any
+		want   error
+	}{
+		{"no es dueño", []any{int32(1)}, nil},
+		{"único dueño", []any{int32(7)}, Conflict("org.last_owner")},
+		{"uno de dos dueños", []any{int32(1), int32(7)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB().add("LockOrganizationOwners", tt.owners...)
+			err := keepAnOwner(context.Background(), db.New(fake), 3, 7)
+			if tt.want == nil && err != nil {
+				t.Fatalf("keepAnOwner: %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("keepAnOwner = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
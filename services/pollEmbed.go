@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	db "webpolls/db/sqlc"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxEmbedOrigins limita cuántos sitios puede autorizar una encuesta.
+const maxEmbedOrigins = 10
+
+// AnyEmbedOrigin autoriza a cualquier sitio a insertar la encuesta.
+const AnyEmbedOrigin = "*"
+
+// SetEmbedOrigins reemplaza la lista de sitios que pueden insertar la encuesta en un iframe.
+// Solo quien puede administrar la encuesta puede cambiarla. Devuelve la lista ya normalizada.
+func (s *PollService) SetEmbedOrigins(ctx context.Context, pollID, userID int32, origins []string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "PollService.SetEmbedOrigins", trace.WithAttributes(attribute.Int("poll.id", int(pollID))))
+	defer span.End()
+
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		origin, err := normalizeEmbedOrigin(origin)
+		if err != nil {
+			return nil, err
+		}
+		if seen[origin] {
+			continue
+		}
+		seen[origin] = true
+		normalized = append(normalized, origin)
+	}
+	if len(normalized) > maxEmbedOrigins {
+		return nil, InvalidField("origins", "embed.too_many_origins", maxEmbedOrigins)
+	}
+
+	if _, err := s.ownedPoll(ctx, pollID, userID); err != nil {
+		return nil, err
+	}
+	updated, err := s.Queries.UpdatePollEmbedOrigins(ctx, db.UpdatePollEmbedOriginsParams{
+		EmbedOrigins: normalized,
+		ID:           pollID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, ErrPollNotFound
+	}
+	return normalized, nil
+}
+
+// normalizeEmbedOrigin deja un origen como esquema://host[:puerto]. Rechaza rutas, queries y
+// cualquier cosa que no sea http(s), para que se pueda copiar tal cual a la cabecera CSP.
+func normalizeEmbedOrigin(origin string) (string, error) {
+	if origin == AnyEmbedOrigin {
+		return origin, nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", InvalidField("origins", "embed.invalid_origin", origin)
+	}
+	if u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", InvalidField("origins", "embed.origin_has_path", origin)
+	}
+	if strings.ContainsAny(u.Host, " ;,'\"") {
+		return "", InvalidField("origins", "embed.invalid_origin", origin)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
+// FrameAncestors arma la directiva CSP frame-ancestors para los orígenes de una encuesta.
+// El propio sitio siempre puede insertarla.
+func FrameAncestors(origins []string) string {
+	sources := []string{"'self'"}
+	for _, origin := range origins {
+		if origin == AnyEmbedOrigin {
+			return "frame-ancestors *"
+		}
+		sources = append(sources, origin)
+	}
+	return "frame-ancestors " + strings.Join(sources, " ")
+}
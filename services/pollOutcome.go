@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Políticas de desempate.
+const (
+	TieBreakNoDecision  = "no_decision"
+	TieBreakFirstOption = "first_option"
+	TieBreakRandom      = "random"
+)
+
+// TieBreaks son las políticas de desempate que puede elegir el creador.
+var TieBreaks = []string{TieBreakNoDecision, TieBreakFirstOption, TieBreakRandom}
+
+// Resultados posibles de una votación cerrada. Todos salvo OutcomeWinner son "sin decisión".
+const (
+	OutcomeWinner     = "winner"
+	OutcomeNoQuorum   = "no_quorum"
+	OutcomeNoMajority = "no_majority"
+	OutcomeTie        = "tie"
+)
+
+// DecisionRules son las reglas con las que se decide una encuesta al cerrar.
+type DecisionRules struct {
+	// QuorumVotes es la cantidad mínima de votos para que la votación sea válida
+	QuorumVotes *int32 `json:"quorum_votes"`
+	// QuorumPercent es el porcentaje de InvitedVoters que tiene que votar
+	InvitedVoters *int32 `json:"invited_voters"`
+	QuorumPercent *int32 `json:"quorum_percent"`
+	// MajorityPercent es el porcentaje de votos que hay que superar para ganar (0 = gana la más votada)
+	MajorityPercent int32  `json:"majority_percent"`
+	TieBreak        string `json:"tie_break"`
+}
+
+// PollOutcome es el resultado calculado al cerrar la votación.
+type PollOutcome struct {
+	Status         string    `json:"status"`
+	WinnerOptionID *int32    `json:"winner_option_id"`
+	WinnerContent  string    `json:"winner_content,omitempty"`
+	DecidedAt      time.Time `json:"decided_at"`
+}
+
+func (r *DecisionRules) validate() error {
+	if r.TieBreak == "" {
+		r.TieBreak = TieBreakNoDecision
+	}
+	var v validation
+	if !isTieBreak(r.TieBreak) {
+		v.add("tie_break", "rules.invalid_tie_break")
+	}
+	if r.QuorumVotes != nil && *r.QuorumVotes < 1 {
+		v.add("quorum_votes", "rules.quorum_min")
+	}
+	if r.QuorumPercent != nil {
+		if *r.QuorumPercent < 1 || *r.QuorumPercent > 100 {
+			v.add("quorum_percent", "rules.quorum_percent_range")
+		}
+		if r.InvitedVoters == nil || *r.InvitedVoters < 1 {
+			v.add("invited_voters", "rules.invited_voters_required")
+		}
+	}
+	if r.MajorityPercent < 0 || r.MajorityPercent > 99 {
+		v.add("majority_percent", "rules.majority_range")
+	}
+	return v.err()
+}
+
+// requiredVotes es la cantidad de votos para alcanzar el quórum (al menos 1).
+func (r DecisionRules) requiredVotes() int64 {
+	required := int64(1)
+	if r.QuorumVotes != nil {
+		required = max(required, int64(*r.QuorumVotes))
+	}
+	if r.QuorumPercent != nil && r.InvitedVoters != nil {
+		// Redondeo hacia arriba: 50% de 5 invitados son 3 votos
+		required = max(required, (int64(*r.QuorumPercent)*int64(*r.InvitedVoters)+99)/100)
+	}
+	return required
+}
+
+type optionTally struct {
+	id    int32
+	votes int64
+}
+
+// decideOutcome aplica las reglas a los votos de cada opción.
+func decideOutcome(rules DecisionRules, tallies []optionTally) (string, *int32) {
+	var total int64
+	for _, t := range tallies {
+		total += t.votes
+	}
+	if total < rules.requiredVotes() {
+		return OutcomeNoQuorum, nil
+	}
+
+	// Las opciones se crean en orden, así que el ID menor es la primera
+	sort.Slice(tallies, func(i, j int) bool { return tallies[i].id < tallies[j].id })
+	var leaders []optionTally
+	for _, t := range tallies {
+		switch {
+		case len(leaders) == 0 || t.votes > leaders[0].votes:
+			leaders = []optionTally{t}
+		case t.votes == leaders[0].votes:
+			leaders = append(leaders, t)
+		}
+	}
+
+	winner := leaders[0]
+	if len(leaders) > 1 {
+		switch rules.TieBreak {
+		case TieBreakFirstOption:
+		case TieBreakRandom:
+			winner = leaders[rand.IntN(len(leaders))]
+		default:
+			return OutcomeTie, nil
+		}
+	}
+
+	if rules.MajorityPercent > 0 && winner.votes*100 <= int64(rules.MajorityPercent)*total {
+		return OutcomeNoMajority, nil
+	}
+	return OutcomeWinner, &winner.id
+}
+
+// DecidePoll calcula y guarda el resultado de una encuesta cerrada. Devuelve nil si ya estaba decidida.
+func (s *PollService) DecidePoll(ctx context.Context, pollID int32) (*PollOutcome, error) {
+	ctx, span := tracer.Start(ctx, "PollService.DecidePoll")
+	defer span.End()
+
+	rows, err := s.Queries.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrPollNotFound
+	}
+	if !pollClosed(rows[0].ClosesAt) {
+		return nil, Conflict("poll.still_open")
+	}
+
+	results, err := s.Queries.GetPollResults(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	votes := make(map[int32]int64)
+	for _, r := range results {
+		votes[r.OptionID] = r.VoteCount
+	}
+	var tallies []optionTally
+	for _, row := range rows {
+		tallies = append(tallies, optionTally{id: row.OptionID, votes: votes[row.OptionID]})
+	}
+
+	status, winnerID := decideOutcome(rulesFromRow(rows[0]), tallies)
+	decided, err := s.Queries.SetPollOutcome(ctx, db.SetPollOutcomeParams{
+		Outcome:        pgtype.Text{String: status, Valid: true},
+		WinnerOptionID: int4(winnerID),
+		ID:             pollID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decided == 0 {
+		return nil, nil
+	}
+
+	outcome := &PollOutcome{Status: status, WinnerOptionID: winnerID, DecidedAt: time.Now()}
+	for _, row := range rows {
+		if winnerID != nil && row.OptionID == *winnerID {
+			outcome.WinnerContent = row.OptionContent
+		}
+	}
+	s.Webhooks.Emit(ctx, rows[0].UserID, EventPollClosed, PollEventData{
+		PollID:  pollID,
+		Title:   rows[0].Title,
+		Outcome: outcome,
+	})
+	s.Notifications.NotifyPollClosed(ctx, pollID)
+	return outcome, nil
+}
+
+// ClosePoll cierra la votación ahora mismo (solo quien puede administrarla) y calcula el resultado.
+func (s *PollService) ClosePoll(ctx context.Context, pollID, userID int32) (*PollOutcome, error) {
+	ctx, span := tracer.Start(ctx, "PollService.ClosePoll")
+	defer span.End()
+
+	if _, err := s.ownedPoll(ctx, pollID, userID); err != nil {
+		return nil, err
+	}
+	closed, err := s.Queries.ClosePollNow(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if closed == 0 {
+		return nil, NotFound("poll.close_not_allowed")
+	}
+	return s.DecidePoll(ctx, pollID)
+}
+
+// CloseDuePolls decide las encuestas cuya fecha de cierre ya pasó. Devuelve las que decidió.
+func (s *PollService) CloseDuePolls(ctx context.Context) ([]int32, error) {
+	ctx, span := tracer.Start(ctx, "PollService.CloseDuePolls")
+	defer span.End()
+
+	ids, err := s.Queries.ListPollsPendingOutcome(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var decided []int32
+	for _, id := range ids {
+		outcome, err := s.DecidePoll(ctx, id)
+		if err != nil {
+			slog.ErrorContext(ctx, "error deciding poll", "poll_id", id, "error", err)
+			continue
+		}
+		if outcome != nil {
+			decided = append(decided, id)
+		}
+	}
+	return decided, nil
+}
+
+// RunCloser revisa cada every las encuestas que cerraron y llama a onDecided por cada una
+// que decide, hasta que se cancele ctx.
+func (s *PollService) RunCloser(ctx context.Context, every time.Duration, onDecided func(context.Context, int32)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			decided, err := s.CloseDuePolls(ctx)
+			if err != nil {
+				slog.Error("error cerrando encuestas", "error", err)
+				continue
+			}
+			for _, id := range decided {
+				onDecided(ctx, id)
+			}
+		}
+	}
+}
+
+func rulesFromRow(row db.GetPollByIDRow) DecisionRules {
+	return DecisionRules{
+		QuorumVotes:     int4Ptr(row.QuorumVotes),
+		InvitedVoters:   int4Ptr(row.InvitedVoters),
+		QuorumPercent:   int4Ptr(row.QuorumPercent),
+		MajorityPercent: row.MajorityPercent,
+		TieBreak:        row.TieBreak,
+	}
+}
+
+// outcomeFromRows arma el resultado guardado, o nil si la encuesta todavía no se decidió.
+func outcomeFromRows(rows []db.GetPollByIDRow) *PollOutcome {
+	if !rows[0].Outcome.Valid {
+		return nil
+	}
+	outcome := &PollOutcome{
+		Status:         rows[0].Outcome.String,
+		WinnerOptionID: int4Ptr(rows[0].WinnerOptionID),
+		DecidedAt:      rows[0].DecidedAt.Time,
+	}
+	for _, row := range rows {
+		if outcome.WinnerOptionID != nil && row.OptionID == *outcome.WinnerOptionID {
+			outcome.WinnerContent = row.OptionContent
+		}
+	}
+	return outcome
+}
+
+func isTieBreak(tieBreak string) bool {
+	for _, t := range TieBreaks {
+		if t == tieBreak {
+			return true
+		}
+	}
+	return false
+}
+
+func int4(n *int32) pgtype.Int4 {
+	if n == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *n, Valid: true}
+}
+
+func int4Ptr(n pgtype.Int4) *int32 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int32
+}
@@ -0,0 +1,94 @@
+package services
+
+import "testing"
+
+func ptr[T any](v T) *T { return &v }
+
+func TestDecideOutcome(t *testing.T) {
+	tally := func(votes ...int64) []optionTally {
+		tallies := make([]optionTally, len(votes))
+		for i, v := range votes {
+			tallies[i] = optionTally{id: int32(i + 1), votes: v}
+		}
+		return tallies
+	}
+	tests := []struct {
+		name    string
+		rules   DecisionRules
+		tallies []optionTally
+		status  string
+		winner  *int32
+	}{
+		{"sin votos", DecisionRules{}, tally(0, 0), OutcomeNoQuorum, nil},
+		{"gana la más votada", DecisionRules{}, tally(1, 3, 2), OutcomeWinner, ptr[int32](2)},
+		{"quórum por votos no alcanzado", DecisionRules{QuorumVotes: ptr[int32](5)}, tally(3, 1), OutcomeNoQuorum, nil},
+		{"quórum por votos justo", DecisionRules{QuorumVotes: ptr[int32](4)}, tally(3, 1), OutcomeWinner, ptr[int32](1)},
+		// 50% de 5 invitados redondea a 3 votos
+		{"quórum porcentual no alcanzado", DecisionRules{QuorumPercent: ptr[int32](50), InvitedVoters: ptr[int32](5)}, tally(2, 0), OutcomeNoQuorum, nil},
+		{"quórum porcentual alcanzado", DecisionRules{QuorumPercent: ptr[int32](50), InvitedVoters: ptr[int32](5)}, tally(2, 1), OutcomeWinner, ptr[int32](1)},
+		{"empate sin desempate", DecisionRules{TieBreak: TieBreakNoDecision}, tally(2, 2, 1), OutcomeTie, nil},
+		{"empate gana la primera", DecisionRules{TieBreak: TieBreakFirstOption}, tally(1, 2, 2), OutcomeWinner, ptr[int32](2)},
+		// La mayoría hay que superarla: 2 de 4 no es más del 50%
+		{"mayoría no superada", DecisionRules{MajorityPercent: 50}, tally(2, 1, 1), OutcomeNoMajority, nil},
+		{"mayoría superada", DecisionRules{MajorityPercent: 50}, tally(3, 1, 1), OutcomeWinner, ptr[int32](1)},
+		{"desempate sin mayoría", DecisionRules{MajorityPercent: 60, TieBreak: TieBreakFirstOption}, tally(2, 2), OutcomeNoMajority, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, winner := decideOutcome(tt.rules, tt.tallies)
+			if status != tt.status {
+				t.Errorf("status = %q, want %q", status, tt.status)
+			}
+			if (winner == nil) != (tt.winner == nil) || (winner != nil && *winner != *tt.winner) {
+				t.Errorf("winner = %v, want %v", deref(winner), deref(tt.winner))
+			}
+		})
+	}
+}
+
+func deref(n *int32) any {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// Con desempate aleatorio el ganador siempre es uno de los empatados.
+func TestDecideOutcomeRandomTieBreak(t *testing.T) {
+	for range 50 {
+		status, winner := decideOutcome(DecisionRules{TieBreak: TieBreakRandom}, []optionTally{{1, 0}, {2, 3}, {3, 3}})
+		if status != OutcomeWinner || winner == nil || (*winner != 2 && *winner != 3) {
+			t.Fatalf("decideOutcome = (%q, %v), want 2 o 3", status, deref(winner))
+		}
+	}
+}
+
+func TestDecisionRulesValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules DecisionRules
+		want  string // vacío si las reglas son válidas
+	}{
+		{"por defecto", DecisionRules{}, ""},
+		{"completas", DecisionRules{QuorumVotes: ptr[int32](3), QuorumPercent: ptr[int32](50), InvitedVoters: ptr[int32](10), MajorityPercent: 66, TieBreak: TieBreakRandom}, ""},
+		{"desempate desconocido", DecisionRules{TieBreak: "moneda"}, "política de desempate inválida"},
+		{"quórum cero", DecisionRules{QuorumVotes: ptr[int32](0)}, "el quórum debe ser de al menos 1 voto"},
+		{"porcentaje fuera de rango", DecisionRules{QuorumPercent: ptr[int32](101), InvitedVoters: ptr[int32](5)}, "el quórum debe estar entre 1% y 100%"},
+		{"porcentaje sin invitados", DecisionRules{QuorumPercent: ptr[int32](50)}, "indica cuántos votantes están invitados para usar un quórum porcentual"},
+		{"mayoría del 100%", DecisionRules{MajorityPercent: 100}, "la mayoría debe estar entre 0% y 99%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("validate = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("validate = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
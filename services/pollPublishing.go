@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Estados de publicación de una encuesta.
+const (
+	// PollDraft es un borrador: solo lo ve su dueño y no acepta votos
+	PollDraft = "draft"
+	// PollScheduled es un borrador que se publica solo al llegar PublishAt
+	PollScheduled = "scheduled"
+	PollPublished = "published"
+)
+
+// pollPublic indica si la encuesta la puede ver cualquiera: publicada y no oculta por moderación.
+func pollPublic(hidden bool, status string) bool {
+	return !hidden && status == PollPublished
+}
+
+// pollAccess indica si viewerID puede ver la encuesta y si puede administrarla. Las ocultas
+// por moderación y las que todavía no se publicaron solo las ve quien puede administrarlas
+// (su creador o los dueños y editores de su organización).
+func (s *PollService) pollAccess(ctx context.Context, hidden bool, status string, ownerID int32, orgID pgtype.Int4, viewerID *int32) (visible, manage bool, err error) {
+	manage, err = s.canManage(ctx, ownerID, orgID, viewerID)
+	if err != nil {
+		return false, false, err
+	}
+	return manage || pollPublic(hidden, status), manage, nil
+}
+
+// publication decide con qué estado se guarda una encuesta nueva: borrador si se pidió,
+// programada si PublishAt es futura o publicada en este momento.
+func (p *PollRequest) publication(now time.Time) (string, pgtype.Timestamptz) {
+	switch {
+	case p.Draft:
+		return PollDraft, pgtype.Timestamptz{}
+	case p.PublishAt != nil && p.PublishAt.After(now):
+		return PollScheduled, timestamptz(p.PublishAt)
+	default:
+		return PollPublished, timestamptz(&now)
+	}
+}
+
+// GetDraft devuelve una encuesta de userID que todavía no se publicó, lista para editarla.
+func (s *PollService) GetDraft(ctx context.Context, pollID, userID int32) (*PollRequest, error) {
+	ctx, span := tracer.Start(ctx, "PollService.GetDraft")
+	defer span.End()
+
+	poll, err := s.ownedPollResponse(ctx, pollID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.Status == PollPublished {
+		return nil, Conflict("poll.already_published")
+	}
+	return requestFromPoll(poll), nil
+}
+
+// UpdateDraft reemplaza pregunta, opciones, configuración y etiquetas de una encuesta que
+// todavía no se publicó. Como no puede tener votos, las opciones se vuelven a crear.
+func (s *PollService) UpdateDraft(ctx context.Context, pollID, userID int32, params PollRequest) error {
+	ctx, span := tracer.Start(ctx, "PollService.UpdateDraft")
+	defer span.End()
+
+	tags, err := params.validate()
+	if err != nil {
+		return err
+	}
+	poll, err := s.ownedPoll(ctx, pollID, userID)
+	if err != nil {
+		return err
+	}
+	if poll.Status == PollPublished {
+		return Conflict("poll.already_published")
+	}
+	if err := s.checkPollOrg(ctx, params.OrgID, userID); err != nil {
+		return err
+	}
+	term, flagged := s.Filter.Match(params.texts()...)
+	if flagged && s.Filter.Mode == FilterReject {
+		return Invalid("poll.blocked_words")
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	updated, err := qtx.UpdateDraftPoll(ctx, db.UpdateDraftPollParams{
+		Title:             params.Question,
+		ClosesAt:          timestamptz(params.ClosesAt),
+		ResultsVisibility: params.ResultsVisibility,
+		QuorumVotes:       int4(params.Rules.QuorumVotes),
+		InvitedVoters:     int4(params.Rules.InvitedVoters),
+		QuorumPercent:     int4(params.Rules.QuorumPercent),
+		MajorityPercent:   params.Rules.MajorityPercent,
+		TieBreak:          params.Rules.TieBreak,
+		OrgID:             int4(params.OrgID),
+		MembersOnly:       params.MembersOnly,
+		ID:                pollID,
+	})
+	if err != nil {
+		return uniqueViolation(err, "unique_poll_title", "question", "poll.title_taken")
+	}
+	// Se publicó (a mano o por la programación) entre la lectura y la escritura
+	if updated == 0 {
+		return Conflict("poll.already_published")
+	}
+
+	if err := qtx.DeleteOptionsByPollID(ctx, pollID); err != nil {
+		return err
+	}
+	if _, err := createOptions(ctx, qtx, pollID, params.Options); err != nil {
+		return err
+	}
+	if err := qtx.DeletePollTags(ctx, pollID); err != nil {
+		return err
+	}
+	if err := setPollTags(ctx, qtx, pollID, tags); err != nil {
+		return err
+	}
+	if flagged {
+		if err := s.Reports.flagPoll(ctx, qtx, pollID, term); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// PublishPoll publica un borrador ahora (publishAt nil o pasada) o lo programa. Devuelve el
+// nuevo estado.
+func (s *PollService) PublishPoll(ctx context.Context, pollID, userID int32, publishAt *time.Time) (string, error) {
+	ctx, span := tracer.Start(ctx, "PollService.PublishPoll")
+	defer span.End()
+
+	poll, err := s.ownedPoll(ctx, pollID, userID)
+	if err != nil {
+		return "", err
+	}
+	if poll.Status == PollPublished {
+		return "", Conflict("poll.already_published")
+	}
+
+	now := time.Now()
+	status, at := (&PollRequest{PublishAt: publishAt}).publication(now)
+	if poll.ClosesAt.Valid && !poll.ClosesAt.Time.After(at.Time) {
+		return "", InvalidField("publish_at", "poll.closes_before_publish")
+	}
+
+	updated, err := s.Queries.SetPollPublication(ctx, db.SetPollPublicationParams{
+		Status:    status,
+		PublishAt: at,
+		ID:        pollID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if updated == 0 {
+		return "", Conflict("poll.already_published")
+	}
+	if status == PollPublished {
+		s.emitPublished(ctx, pollID, poll.UserID, poll.Title)
+	}
+	return status, nil
+}
+
+// UnschedulePoll cancela la publicación programada y devuelve la encuesta a borrador.
+func (s *PollService) UnschedulePoll(ctx context.Context, pollID, userID int32) error {
+	ctx, span := tracer.Start(ctx, "PollService.UnschedulePoll")
+	defer span.End()
+
+	poll, err := s.ownedPoll(ctx, pollID, userID)
+	if err != nil {
+		return err
+	}
+	if poll.Status != PollScheduled {
+		return Conflict("poll.not_scheduled")
+	}
+	updated, err := s.Queries.SetPollPublication(ctx, db.SetPollPublicationParams{
+		Status: PollDraft,
+		ID:     pollID,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return Conflict("poll.already_published")
+	}
+	return nil
+}
+
+// PreviewPoll devuelve la encuesta de userID tal como la verá alguien que todavía no votó.
+func (s *PollService) PreviewPoll(ctx context.Context, pollID, userID int32) (*PollResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.PreviewPoll")
+	defer span.End()
+
+	poll, err := s.ownedPollResponse(ctx, pollID, userID)
+	if err != nil {
+		return nil, err
+	}
+	poll.IsOwner = false
+	poll.UserVotedOptionID = nil
+	poll.ResultsVisible = resultsVisible(poll.ResultsVisibility, false, false, poll.Closed)
+	if !poll.ResultsVisible {
+		poll.TotalVotes = 0
+		poll.Outcome = nil
+		for i := range poll.Options {
+			poll.Options[i].VoteCount = 0
+			poll.Options[i].Percentage = 0
+		}
+	}
+	return poll, nil
+}
+
+// PublishDuePolls publica las encuestas programadas cuya fecha ya llegó. Devuelve las que publicó.
+func (s *PollService) PublishDuePolls(ctx context.Context) ([]int32, error) {
+	ctx, span := tracer.Start(ctx, "PollService.PublishDuePolls")
+	defer span.End()
+
+	rows, err := s.Queries.PublishDuePolls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	published := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		s.emitPublished(ctx, row.ID, row.UserID, row.Title)
+		published = append(published, row.ID)
+	}
+	return published, nil
+}
+
+// RunPublisher revisa cada every si hay encuestas programadas para publicar, hasta que se
+// cancele ctx.
+func (s *PollService) RunPublisher(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PublishDuePolls(ctx); err != nil {
+				slog.Error("error publicando encuestas programadas", "error", err)
+			}
+		}
+	}
+}
+
+// emitPublished avisa a los webhooks de una encuesta que se acaba de publicar. Para los
+// suscriptores una encuesta existe desde que se publica, no desde que se empezó el borrador.
+func (s *PollService) emitPublished(ctx context.Context, pollID, ownerID int32, title string) {
+	if s.Webhooks == nil {
+		return
+	}
+	options, err := s.Queries.GetOptionByPollID(ctx, pollID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error loading options for webhook", "poll_id", pollID, "error", err)
+		return
+	}
+	data := PollEventData{PollID: pollID, Title: title}
+	for _, option := range options {
+		data.Options = append(data.Options, OptionResponse{ID: option.ID, Content: option.Content, PollID: option.PollID})
+	}
+	s.Webhooks.Emit(ctx, ownerID, EventPollCreated, data)
+}
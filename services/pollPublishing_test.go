@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPollAccess(t *testing.T) {
+	owner, other := int32(1), int32(2)
+	org := pgtype.Int4{Int32: 3, Valid: true}
+	tests := []struct {
+		name    string
+		hidden  bool
+		status  string
+		orgID   pgtype.Int4
+		viewer  *int32
+		role    []any
+		visible bool
+		manage  bool
+	}{
+		{"publicada", false, PollPublished, pgtype.Int4{}, nil, nil, true, false},
+		{"borrador para un visitante", false, PollDraft, pgtype.Int4{}, nil, nil, false, false},
+		{"borrador para otra persona", false, PollDraft, pgtype.Int4{}, &other, nil, false, false},
+		{"borrador para su dueño", false, PollDraft, pgtype.Int4{}, &owner, nil, true, true},
+		{"programada para otra persona", false, PollScheduled, pgtype.Int4{}, &other, nil, false, false},
+		{"programada para su dueño", false, PollScheduled, pgtype.Int4{}, &owner, nil, true, true},
+		{"oculta por moderación", true, PollPublished, pgtype.Int4{}, &other, nil, false, false},
+		{"oculta para su dueño", true, PollPublished, pgtype.Int4{}, &owner, nil, true, true},
+		{"borrador para un editor de la organización", false, PollDraft, org, &other, []any{RoleEditor}, true, true},
+		{"borrador para un lector de la organización", false, PollDraft, org, &other, []any{RoleViewer}, false, false},
+		{"publicada para un lector de la organización", false, PollPublished, org, &other, []any{RoleViewer}, true, false},
+		{"borrador de la organización para un extraño", false, PollDraft, org, &other, nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PollService{Queries: db.New(newFakeDB().add("GetOrganizationRole", tt.role...))}
+			visible, manage, err := s.pollAccess(context.Background(), tt.hidden, tt.status, owner, tt.orgID, tt.viewer)
+			if err != nil {
+				t.Fatalf("pollAccess: %v", err)
+			}
+			if visible != tt.visible || manage != tt.manage {
+				t.Errorf("pollAccess = (%v, %v), want (%v, %v)", visible, manage, tt.visible, tt.manage)
+			}
+		})
+	}
+}
+
+func TestPublication(t *testing.T) {
+	now := time.Now()
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		request PollRequest
+		status  string
+		at      *time.Time
+	}{
+		{"inmediata", PollRequest{}, PollPublished, &now},
+		{"borrador", PollRequest{Draft: true, PublishAt: &later}, PollDraft, nil},
+		{"programada", PollRequest{PublishAt: &later}, PollScheduled, &later},
+		{"fecha pasada", PollRequest{PublishAt: &earlier}, PollPublished, &now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, at := tt.request.publication(now)
+			if status != tt.status || at.Valid != (tt.at != nil) || (tt.at != nil && !at.Time.Equal(*tt.at)) {
+				t.Errorf("publication = (%q, %v), want (%q, %v)", status, at, tt.status, tt.at)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrSeriesNotFound se devuelve cuando la serie no existe o es de otro usuario.
+var ErrSeriesNotFound = NotFound("series.not_found")
+
+// PollSeries es una encuesta recurrente: cada NextRunAt se crea una encuesta nueva desde la
+// plantilla y se cierra la anterior.
+type PollSeries struct {
+	ID         int32      `json:"id"`
+	TemplateID int32      `json:"template_id"`
+	Name       string     `json:"name"`
+	Recurrence Recurrence `json:"recurrence"`
+	NextRunAt  time.Time  `json:"next_run_at"`
+	Active     bool       `json:"active"`
+}
+
+// SeriesInstance es una de las encuestas creadas por la serie, con sus resultados.
+type SeriesInstance struct {
+	PollID      int32                `json:"poll_id"`
+	Title       string               `json:"title"`
+	PublishedAt *time.Time           `json:"published_at"`
+	Closed      bool                 `json:"closed"`
+	TotalVotes  int64                `json:"total_votes"`
+	Outcome     string               `json:"outcome,omitempty"`
+	Results     []SeriesOptionResult `json:"results"`
+}
+
+// SeriesOptionResult son los votos de una opción en una instancia.
+type SeriesOptionResult struct {
+	Content    string  `json:"content"`
+	VoteCount  int64   `json:"vote_count"`
+	Percentage float64 `json:"percentage"`
+	Winner     bool    `json:"winner"`
+}
+
+// SeriesReport junta la serie con todas sus encuestas, de la más vieja a la más nueva, para
+// ver cómo cambian los resultados.
+type SeriesReport struct {
+	Series    PollSeries       `json:"series"`
+	Instances []SeriesInstance `json:"instances"`
+	// Options son las opciones que aparecen en alguna instancia, en orden de aparición
+	Options []string `json:"options"`
+}
+
+// Share devuelve el porcentaje de votos de la opción content en la instancia (0 si no la tuvo).
+func (i *SeriesInstance) Share(content string) float64 {
+	for _, result := range i.Results {
+		if result.Content == content {
+			return result.Percentage
+		}
+	}
+	return 0
+}
+
+// CreateSeries crea una serie de userID a partir de una de sus plantillas. La primera
+// encuesta se crea en la primera ocurrencia de la regla.
+func (s *PollService) CreateSeries(ctx context.Context, userID, templateID int32, rule Recurrence) (*PollSeries, error) {
+	ctx, span := tracer.Start(ctx, "PollService.CreateSeries")
+	defer span.End()
+
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	template, err := s.GetTemplate(ctx, templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	next := rule.Next(time.Now())
+	if next.IsZero() {
+		return nil, InvalidField("starts_at", "series.never_runs")
+	}
+
+	id, err := s.Queries.CreatePollSeries(ctx, db.CreatePollSeriesParams{
+		UserID:     userID,
+		TemplateID: template.ID,
+		Frequency:  rule.Frequency,
+		Weekdays:   append([]int32{}, rule.Weekdays...),
+		CronExpr:   rule.Cron,
+		StartsAt:   timestamptz(&rule.StartsAt),
+		NextRunAt:  timestamptz(&next),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PollSeries{
+		ID:         id,
+		TemplateID: template.ID,
+		Name:       template.Name,
+		Recurrence: rule,
+		NextRunAt:  next,
+		Active:     true,
+	}, nil
+}
+
+// ListSeries devuelve las series de userID ordenadas por nombre de plantilla.
+func (s *PollService) ListSeries(ctx context.Context, userID int32) ([]PollSeries, error) {
+	ctx, span := tracer.Start(ctx, "PollService.ListSeries")
+	defer span.End()
+
+	rows, err := s.Queries.ListPollSeries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]PollSeries, 0, len(rows))
+	for _, row := range rows {
+		series = append(series, *seriesFromRow(db.GetPollSeriesRow(row)))
+	}
+	return series, nil
+}
+
+// GetSeriesReport devuelve una serie de userID con los resultados de todas sus encuestas.
+func (s *PollService) GetSeriesReport(ctx context.Context, id, userID int32) (*SeriesReport, error) {
+	ctx, span := tracer.Start(ctx, "PollService.GetSeriesReport")
+	defer span.End()
+
+	series, err := s.ownedSeries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Queries.GetSeriesResults(ctx, pgtype.Int4{Int32: id, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SeriesReport{Series: *series}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		n := len(report.Instances)
+		if n == 0 || report.Instances[n-1].PollID != row.PollID {
+			report.Instances = append(report.Instances, SeriesInstance{
+				PollID:      row.PollID,
+				Title:       row.Title,
+				PublishedAt: timePtr(row.PublishAt),
+				Closed:      pollClosed(row.ClosesAt),
+				Outcome:     row.Outcome.String,
+			})
+			n++
+		}
+		instance := &report.Instances[n-1]
+		instance.TotalVotes += row.VoteCount
+		instance.Results = append(instance.Results, SeriesOptionResult{
+			Content:   row.OptionContent,
+			VoteCount: row.VoteCount,
+			Winner:    row.WinnerOptionID.Valid && row.WinnerOptionID.Int32 == row.OptionID,
+		})
+		if !seen[row.OptionContent] {
+			seen[row.OptionContent] = true
+			report.Options = append(report.Options, row.OptionContent)
+		}
+	}
+	for i := range report.Instances {
+		instance := &report.Instances[i]
+		for j := range instance.Results {
+			if instance.TotalVotes > 0 {
+				instance.Results[j].Percentage = float64(instance.Results[j].VoteCount) / float64(instance.TotalVotes) * 100
+			}
+		}
+	}
+	return report, nil
+}
+
+// SetSeriesActive pausa o reanuda una serie. Al reanudarla se sigue desde la próxima
+// ocurrencia: las que se saltaron mientras estuvo pausada no se crean.
+func (s *PollService) SetSeriesActive(ctx context.Context, id, userID int32, active bool) (*PollSeries, error) {
+	ctx, span := tracer.Start(ctx, "PollService.SetSeriesActive")
+	defer span.End()
+
+	series, err := s.ownedSeries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	next := series.NextRunAt
+	if active {
+		if next = series.Recurrence.Next(time.Now()); next.IsZero() {
+			return nil, Conflict("series.never_runs")
+		}
+	}
+	updated, err := s.Queries.SetPollSeriesActive(ctx, db.SetPollSeriesActiveParams{
+		Active:    active,
+		NextRunAt: timestamptz(&next),
+		ID:        id,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, ErrSeriesNotFound
+	}
+	series.Active = active
+	series.NextRunAt = next
+	return series, nil
+}
+
+// DeleteSeries borra una serie de userID. Sus encuestas quedan como encuestas sueltas.
+func (s *PollService) DeleteSeries(ctx context.Context, id, userID int32) error {
+	ctx, span := tracer.Start(ctx, "PollService.DeleteSeries")
+	defer span.End()
+
+	n, err := s.Queries.DeletePollSeries(ctx, db.DeletePollSeriesParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSeriesNotFound
+	}
+	return nil
+}
+
+// RunDueSeries crea la encuesta que toca en cada serie vencida y cierra la anterior.
+// Devuelve las encuestas creadas.
+func (s *PollService) RunDueSeries(ctx context.Context) ([]int32, error) {
+	ctx, span := tracer.Start(ctx, "PollService.RunDueSeries")
+	defer span.End()
+
+	rows, err := s.Queries.ListDueSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var created []int32
+	for _, row := range rows {
+		id, err := s.runSeries(ctx, row)
+		if err != nil {
+			slog.ErrorContext(ctx, "error creating series poll", "series_id", row.ID, "error", err)
+			continue
+		}
+		if id != 0 {
+			created = append(created, id)
+		}
+	}
+	return created, nil
+}
+
+// runSeries avanza la serie a su próxima ocurrencia, cierra la encuesta anterior y crea la
+// nueva en una sola transacción: si algo falla la ocurrencia se reintenta en la próxima
+// vuelta. Si otra instancia del servidor ya la avanzó no hace nada y devuelve 0.
+func (s *PollService) runSeries(ctx context.Context, row db.ListDueSeriesRow) (int32, error) {
+	now := time.Now()
+	rule := Recurrence{
+		Frequency: row.Frequency,
+		Weekdays:  row.Weekdays,
+		Cron:      row.CronExpr,
+		StartsAt:  row.StartsAt.Time,
+	}
+	template, err := s.GetTemplate(ctx, row.TemplateID, row.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.Queries.WithTx(tx)
+
+	// Si el servidor estuvo caído no se recuperan las ocurrencias perdidas: se sigue desde ahora
+	var claimed int64
+	if next := rule.Next(now); next.IsZero() {
+		// La regla no vuelve a ocurrir: se crea esta última y la serie queda pausada
+		claimed, err = qtx.FinishPollSeries(ctx, db.FinishPollSeriesParams{
+			ID:            row.ID,
+			PreviousRunAt: row.NextRunAt,
+		})
+	} else {
+		claimed, err = qtx.AdvancePollSeries(ctx, db.AdvancePollSeriesParams{
+			NextRunAt:     timestamptz(&next),
+			ID:            row.ID,
+			PreviousRunAt: row.NextRunAt,
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	if claimed == 0 {
+		return 0, nil
+	}
+
+	// La encuesta anterior la decide RunCloser, que además avisa por SSE
+	if _, err := qtx.CloseSeriesPolls(ctx, pgtype.Int4{Int32: row.ID, Valid: true}); err != nil {
+		return 0, err
+	}
+
+	draft := template.Draft(ctx, now)
+	draft.UserID = row.UserID
+	draft.SeriesID = &row.ID
+	poll, err := s.insertSeriesPoll(ctx, tx, *draft)
+	if errors.Is(err, InvalidField("question", "poll.title_taken")) {
+		draft.Question = instanceTitle(ctx, draft.Question, now)
+		poll, err = s.insertSeriesPoll(ctx, tx, *draft)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	s.emitCreated(ctx, poll)
+	return poll.ID, nil
+}
+
+// insertSeriesPoll guarda la encuesta dentro de un savepoint de tx, para que un título
+// repetido no invalide la transacción y se pueda reintentar con otro.
+func (s *PollService) insertSeriesPoll(ctx context.Context, tx pgx.Tx, params PollRequest) (*PollResponse, error) {
+	checked, err := s.checkPoll(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer savepoint.Rollback(ctx)
+
+	poll, err := s.insertPoll(ctx, s.Queries.WithTx(savepoint), checked)
+	if err != nil {
+		return nil, err
+	}
+	return poll, savepoint.Commit(ctx)
+}
+
+// instanceTitle es el título de la ocurrencia de now cuando el de la plantilla ya está usado:
+// no cambia entre ocurrencias (no tiene marcadores o no alcanzan), así que se le agrega la
+// fecha y hora para que siga siendo único.
+func instanceTitle(ctx context.Context, title string, now time.Time) string {
+	return i18n.T(ctx, "series.instance_title", title, i18n.DateTime(ctx, now))
+}
+
+// RunSeries revisa cada every si alguna serie tiene que crear su próxima encuesta, hasta que
+// se cancele ctx.
+func (s *PollService) RunSeries(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunDueSeries(ctx); err != nil {
+				slog.Error("error creando encuestas recurrentes", "error", err)
+			}
+		}
+	}
+}
+
+func (s *PollService) ownedSeries(ctx context.Context, id, userID int32) (*PollSeries, error) {
+	row, err := s.Queries.GetPollSeries(ctx, db.GetPollSeriesParams{ID: id, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSeriesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return seriesFromRow(row), nil
+}
+
+func seriesFromRow(row db.GetPollSeriesRow) *PollSeries {
+	return &PollSeries{
+		ID:         row.ID,
+		TemplateID: row.TemplateID,
+		Name:       row.TemplateName,
+		Recurrence: Recurrence{
+			Frequency: row.Frequency,
+			Weekdays:  row.Weekdays,
+			Cron:      row.CronExpr,
+			StartsAt:  row.StartsAt.Time,
+		},
+		NextRunAt: row.NextRunAt.Time,
+		Active:    row.Active,
+	}
+}
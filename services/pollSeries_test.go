@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"webpolls/i18n"
+)
+
+func TestSeriesTitleCollision(t *testing.T) {
+	monday := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.Local)
+	es := i18n.WithLocale(context.Background(), i18n.ES)
+	en := i18n.WithLocale(context.Background(), i18n.EN)
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		title    string
+		next     time.Time
+		collides bool
+		want     [2]string
+	}{
+		{"sin marcadores", es, "Retro semanal", monday.AddDate(0, 0, 7), true,
+			[2]string{"Retro semanal (03/03/2025 09:00)", "Retro semanal (10/03/2025 09:00)"}},
+		{"semana en serie diaria", es, "Retro S{{week}}", monday.AddDate(0, 0, 1), true,
+			[2]string{"Retro S10 (03/03/2025 09:00)", "Retro S10 (04/03/2025 09:00)"}},
+		{"misma fecha dos veces al día", en, "Standup {{date}}", monday.Add(8 * time.Hour), true,
+			[2]string{"Standup Mar 3, 2025 (Mar 3, 2025 09:00)", "Standup Mar 3, 2025 (Mar 3, 2025 17:00)"}},
+		{"fecha en serie diaria", es, "Standup {{date}}", monday.AddDate(0, 0, 1), false, [2]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &PollTemplate{Title: tt.title}
+			first := template.Draft(tt.ctx, monday).Question
+			second := template.Draft(tt.ctx, tt.next).Question
+			if collides := first == second; collides != tt.collides {
+				t.Fatalf("%q y %q chocan = %v, want %v", first, second, collides, tt.collides)
+			}
+			if !tt.collides {
+				return
+			}
+			got := [2]string{instanceTitle(tt.ctx, first, monday), instanceTitle(tt.ctx, second, tt.next)}
+			if got != tt.want {
+				t.Errorf("títulos = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
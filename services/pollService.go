@@ -3,17 +3,30 @@ package services
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
+	"time"
 	db "webpolls/db/sqlc"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PollService encapsula la lógica de negocio para las encuestas.
 type PollService struct {
 	Queries *db.Queries // <-- Exportado (con mayúscula)
 	DB      *pgxpool.Pool
+	// Filter revisa títulos y opciones contra la lista de palabras prohibidas (nil = sin filtro)
+	Filter *ContentFilter
+	// Webhooks recibe los eventos de las encuestas (nil = sin webhooks)
+	Webhooks *WebhookService
+	// Notifications avisa a los usuarios de votos y cierres (nil = sin notificaciones)
+	Notifications *NotificationService
+	// Reports recibe los reportes automáticos del filtro y decide si ocultar la encuesta
+	// (nil = solo se crea el reporte)
+	Reports *ReportService
 }
 
 // NewPollService crea una nueva instancia de PollService.
@@ -21,6 +34,20 @@ func NewPollService(queries *db.Queries, db *pgxpool.Pool) *PollService {
 	return &PollService{Queries: queries, DB: db} // <-- actualizado
 }
 
+// ErrPollNotFound se devuelve cuando la encuesta no existe o quien la pide no puede verla.
+var ErrPollNotFound = NotFound("poll.not_found")
+
+// Cuándo se muestran los resultados de una encuesta (conteos y porcentajes).
+const (
+	ResultsAlways     = "always"
+	ResultsAfterVote  = "after_vote"
+	ResultsAfterClose = "after_close"
+	ResultsOwnerOnly  = "owner_only"
+)
+
+// ResultsVisibilities son las políticas de visibilidad de resultados que puede elegir el creador.
+var ResultsVisibilities = []string{ResultsAlways, ResultsAfterVote, ResultsAfterClose, ResultsOwnerOnly}
+
 type OptionResponse struct {
 	ID         int32   `json:"id"`
 	Content    string  `json:"content"`
@@ -34,9 +61,23 @@ type OptionRequest struct {
 }
 
 type PollRequest struct {
-	Question string          `json:"question"`
-	Options  []OptionRequest `json:"options"`
-	UserID   int32           `json:"user_id"`
+	Question          string          `json:"question"`
+	Options           []OptionRequest `json:"options"`
+	UserID            int32           `json:"user_id"`
+	ClosesAt          *time.Time      `json:"closes_at"`
+	ResultsVisibility string          `json:"results_visibility"`
+	Rules             DecisionRules   `json:"rules"`
+	Tags              []string        `json:"tags"`
+	// Draft guarda la encuesta como borrador en lugar de publicarla
+	Draft bool `json:"draft"`
+	// PublishAt programa la publicación (nil o pasada = publicar ya)
+	PublishAt *time.Time `json:"publish_at"`
+	// SeriesID lo completa RunSeries en las encuestas de una serie recurrente
+	SeriesID *int32 `json:"-"`
+	// OrgID hace la encuesta de una organización: la administran también sus dueños y editores
+	OrgID *int32 `json:"org_id"`
+	// MembersOnly deja votar solo a los miembros de la organización
+	MembersOnly bool `json:"members_only"`
 }
 
 type PollResponse struct {
@@ -46,17 +87,104 @@ type PollResponse struct {
 	Options           []OptionResponse `json:"options"`
 	TotalVotes        int64            `json:"total_votes"`
 	UserVotedOptionID *int32           `json:"user_voted_option_id"`
+	Hidden            bool             `json:"hidden"`
+	ClosesAt          *time.Time       `json:"closes_at"`
+	Closed            bool             `json:"closed"`
+	ResultsVisibility string           `json:"results_visibility"`
+	// ResultsVisible indica si quien consulta puede ver los resultados; si no, los conteos van en cero
+	ResultsVisible bool          `json:"results_visible"`
+	Rules          DecisionRules `json:"rules"`
+	// Outcome es el resultado de la votación una vez cerrada (nil mientras no se decida o si los resultados están ocultos)
+	Outcome *PollOutcome `json:"outcome"`
+	// IsOwner indica si quien consulta puede administrar la encuesta: la creó o es dueño o
+	// editor de su organización (puede cerrarla antes de tiempo)
+	IsOwner bool `json:"is_owner"`
+	// EmbedOrigins son los sitios que pueden insertar la encuesta en un iframe
+	EmbedOrigins []string `json:"embed_origins"`
+	Tags         []string `json:"tags"`
+	// Status es PollDraft, PollScheduled o PollPublished
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+	SeriesID  *int32     `json:"series_id,omitempty"`
+	OrgID     *int32     `json:"org_id,omitempty"`
+	OrgName   string     `json:"org_name,omitempty"`
+	// MembersOnly indica que solo votan los miembros de la organización; CanVote dice si quien
+	// consulta cumple esa condición
+	MembersOnly bool `json:"members_only"`
+	CanVote     bool `json:"can_vote"`
 }
 
-func (s *PollService) CreatePoll(ctx context.Context, params PollRequest) (*PollResponse, error) {
-	if params.Question == "" {
-		return nil, errors.New("la pregunta no puede estar vacía")
+// validate revisa la pregunta, las opciones y la configuración, completa los valores por
+// defecto y devuelve las etiquetas normalizadas.
+func (p *PollRequest) validate() ([]string, error) {
+	var v validation
+	if p.Question == "" {
+		v.add("question", "poll.question_required")
+	}
+	if len(p.Options) < 2 {
+		v.add("options", "poll.min_options")
+	}
+	if len(p.Options) > 4 {
+		v.add("options", "poll.max_options")
+	}
+	if p.ResultsVisibility == "" {
+		p.ResultsVisibility = ResultsAlways
 	}
-	if len(params.Options) < 2 {
-		return nil, errors.New("deben ser al menos 2 opciones")
+	if !isResultsVisibility(p.ResultsVisibility) {
+		v.add("results_visibility", "poll.invalid_results_visibility")
 	}
-	if len(params.Options) > 4 {
-		return nil, errors.New("deben ser máximo 4 opciones")
+	if p.ClosesAt != nil && !p.ClosesAt.After(time.Now()) {
+		v.add("closes_at", "poll.closes_at_past")
+	}
+	if p.MembersOnly && p.OrgID == nil {
+		v.add("members_only", "poll.members_only_without_org")
+	}
+	if !p.Draft && p.ClosesAt != nil && p.PublishAt != nil && !p.ClosesAt.After(*p.PublishAt) {
+		v.add("closes_at", "poll.closes_before_publish")
+	}
+	v.merge(p.Rules.validate())
+	tags := normalizeTags(p.Tags, &v)
+	return tags, v.err()
+}
+
+// texts son la pregunta y las opciones, para revisarlas con el filtro de contenido.
+func (p *PollRequest) texts() []string {
+	texts := []string{p.Question}
+	for _, option := range p.Options {
+		texts = append(texts, option.Content)
+	}
+	return texts
+}
+
+// createOptions crea las opciones no vacías de la encuesta; tienen que quedar al menos dos.
+func createOptions(ctx context.Context, q *db.Queries, pollID int32, requested []OptionRequest) ([]db.Option, error) {
+	var options []db.Option
+	for _, optionContent := range requested {
+		if optionContent.Content == "" {
+			continue
+		}
+		option, err := q.CreateOption(ctx, db.CreateOptionParams{
+			Content: optionContent.Content,
+			PollID:  pollID,
+		})
+		if err != nil {
+			return nil, uniqueViolation(err, "unique_option", "options", "option.duplicate", optionContent.Content)
+		}
+		options = append(options, option)
+	}
+	if len(options) < 2 {
+		return nil, InvalidField("options", "poll.min_options")
+	}
+	return options, nil
+}
+
+func (s *PollService) CreatePoll(ctx context.Context, params PollRequest) (*PollResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.CreatePoll")
+	defer span.End()
+
+	checked, err := s.checkPoll(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
 	tx, err := s.DB.Begin(ctx)
@@ -65,42 +193,85 @@ func (s *PollService) CreatePoll(ctx context.Context, params PollRequest) (*Poll
 	}
 	defer tx.Rollback(ctx)
 
-	qtx := s.Queries.WithTx(tx)
+	data, err := s.insertPoll(ctx, s.Queries.WithTx(tx), checked)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.emitCreated(ctx, data)
+	return data, nil
+}
 
-	log.Println(params)
-	poll, err := qtx.CreatePoll(ctx, db.CreatePollParams{
-		Title:  params.Question,
-		UserID: params.UserID,
-	})
+// checkedPoll es una PollRequest que ya pasó las validaciones, lista para insertPoll.
+type checkedPoll struct {
+	PollRequest
+	tags []string
+	// flagged indica que el filtro en modo "flag" encontró flaggedTerm
+	flagged     bool
+	flaggedTerm string
+}
+
+// checkPoll valida params antes de abrir la transacción: reglas, organización y filtro de contenido.
+func (s *PollService) checkPoll(ctx context.Context, params PollRequest) (*checkedPoll, error) {
+	tags, err := params.validate()
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkPollOrg(ctx, params.OrgID, params.UserID); err != nil {
+		return nil, err
+	}
 
-	// Crear opciones asociadas
-	var options []db.Option
-	for _, optionContent := range params.Options {
-		if optionContent.Content != "" {
-
-			option, err := qtx.CreateOption(ctx, db.CreateOptionParams{
-				Content: optionContent.Content,
-				PollID:  poll.ID,
-			})
-			if err != nil {
-				return nil, err
-			}
-			options = append(options, option)
-		}
+	term, flagged := s.Filter.Match(params.texts()...)
+	if flagged && s.Filter.Mode == FilterReject {
+		return nil, Invalid("poll.blocked_words")
+	}
+	return &checkedPoll{PollRequest: params, tags: tags, flagged: flagged, flaggedTerm: term}, nil
+}
 
+// insertPoll guarda la encuesta con sus opciones y etiquetas usando qtx. No confirma la
+// transacción ni emite eventos: de eso se encarga quien llama (ver emitCreated).
+func (s *PollService) insertPoll(ctx context.Context, qtx *db.Queries, params *checkedPoll) (*PollResponse, error) {
+	status, publishAt := params.publication(time.Now())
+	slog.DebugContext(ctx, "creating poll", "user_id", params.UserID, "options", len(params.Options), "status", status)
+	poll, err := qtx.CreatePoll(ctx, db.CreatePollParams{
+		Title:             params.Question,
+		UserID:            params.UserID,
+		ClosesAt:          timestamptz(params.ClosesAt),
+		ResultsVisibility: params.ResultsVisibility,
+		QuorumVotes:       int4(params.Rules.QuorumVotes),
+		InvitedVoters:     int4(params.Rules.InvitedVoters),
+		QuorumPercent:     int4(params.Rules.QuorumPercent),
+		MajorityPercent:   params.Rules.MajorityPercent,
+		TieBreak:          params.Rules.TieBreak,
+		Status:            status,
+		PublishAt:         publishAt,
+		SeriesID:          int4(params.SeriesID),
+		OrgID:             int4(params.OrgID),
+		MembersOnly:       params.MembersOnly,
+	})
+	if err != nil {
+		return nil, uniqueViolation(err, "unique_poll_title", "question", "poll.title_taken")
 	}
 
-	if len(options) < 2 {
-		return nil, errors.New("deben ser al menos 2 opciones")
+	// Crear opciones asociadas
+	options, err := createOptions(ctx, qtx, poll.ID, params.Options)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := setPollTags(ctx, qtx, poll.ID, params.tags); err != nil {
 		return nil, err
 	}
 
+	// En modo "flag" la encuesta se publica pero queda en la cola de moderación
+	if params.flagged {
+		if err := s.Reports.flagPoll(ctx, qtx, poll.ID, params.flaggedTerm); err != nil {
+			return nil, err
+		}
+	}
+
 	// Convert db.Option to OptionResponse
 	var responseOptions []OptionResponse
 	for _, opt := range options {
@@ -112,32 +283,58 @@ func (s *PollService) CreatePoll(ctx context.Context, params PollRequest) (*Poll
 	}
 
 	data := &PollResponse{
-		ID:      poll.ID,
-		Title:   poll.Title,
-		UserID:  poll.UserID,
-		Options: responseOptions,
+		ID:                poll.ID,
+		Title:             poll.Title,
+		UserID:            poll.UserID,
+		Options:           responseOptions,
+		ClosesAt:          timePtr(poll.ClosesAt),
+		ResultsVisibility: poll.ResultsVisibility,
+		ResultsVisible:    true,
+		Rules:             params.Rules,
+		Tags:              params.tags,
+		Status:            poll.Status,
+		PublishAt:         timePtr(poll.PublishAt),
+		OrgID:             params.OrgID,
+		MembersOnly:       params.MembersOnly,
 	}
 	return data, nil
 }
 
+// emitCreated anuncia una encuesta recién guardada. Los borradores se anuncian recién al
+// publicarse (ver emitPublished).
+func (s *PollService) emitCreated(ctx context.Context, poll *PollResponse) {
+	if poll.Status != PollPublished {
+		return
+	}
+	s.Webhooks.Emit(ctx, poll.UserID, EventPollCreated, PollEventData{
+		PollID:  poll.ID,
+		Title:   poll.Title,
+		Options: poll.Options,
+	})
+}
+
 func (s *PollService) GetPollByID(ctx context.Context, id int32, userID *int32) (*PollResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.GetPollByID", trace.WithAttributes(attribute.Int("poll.id", int(id))))
+	defer span.End()
+
 	poll, err := s.Queries.GetPollByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if len(poll) == 0 {
+		return nil, ErrPollNotFound
+	}
 
-	// Get results
-	results, err := s.Queries.GetPollResults(ctx, id)
+	canView, isOwner, err := s.pollAccess(ctx, poll[0].Hidden, poll[0].Status, poll[0].UserID, poll[0].OrgID, userID)
 	if err != nil {
-		// If no results, just continue with 0 counts
-		log.Printf("Error getting results for poll %d: %v", id, err)
+		return nil, err
 	}
-
-	voteCounts := make(map[int32]int64)
-	var totalVotes int64
-	for _, r := range results {
-		voteCounts[r.OptionID] = r.VoteCount
-		totalVotes += r.VoteCount
+	if !canView {
+		return nil, ErrPollNotFound
+	}
+	canVote, err := s.canVote(ctx, poll[0].MembersOnly, poll[0].OrgID, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	var userVotedOptionID *int32
@@ -149,7 +346,25 @@ func (s *PollService) GetPollByID(ctx context.Context, id int32, userID *int32)
 		if err == nil {
 			userVotedOptionID = &votedOption
 		} else if err != pgx.ErrNoRows {
-			log.Printf("Error checking user vote: %v", err)
+			slog.ErrorContext(ctx, "error checking user vote", "error", err)
+		}
+	}
+
+	// Los conteos ni se consultan si quien mira todavía no puede verlos
+	closed := pollClosed(poll[0].ClosesAt)
+	visible := resultsVisible(poll[0].ResultsVisibility, isOwner, userVotedOptionID != nil, closed)
+
+	voteCounts := make(map[int32]int64)
+	var totalVotes int64
+	if visible {
+		results, err := s.Queries.GetPollResults(ctx, id)
+		if err != nil {
+			// If no results, just continue with 0 counts
+			slog.ErrorContext(ctx, "error getting poll results", "poll_id", id, "error", err)
+		}
+		for _, r := range results {
+			voteCounts[r.OptionID] = r.VoteCount
+			totalVotes += r.VoteCount
 		}
 	}
 
@@ -171,42 +386,184 @@ func (s *PollService) GetPollByID(ctx context.Context, id int32, userID *int32)
 		})
 	}
 
-	return &PollResponse{
+	var outcome *PollOutcome
+	if visible {
+		outcome = outcomeFromRows(poll)
+	}
+
+	response := &PollResponse{
 		ID:                poll[0].ID,
 		Title:             poll[0].Title,
 		UserID:            poll[0].UserID,
 		Options:           options,
 		TotalVotes:        totalVotes,
 		UserVotedOptionID: userVotedOptionID,
-	}, nil
+		Hidden:            poll[0].Hidden,
+		ClosesAt:          timePtr(poll[0].ClosesAt),
+		Closed:            closed,
+		ResultsVisibility: poll[0].ResultsVisibility,
+		ResultsVisible:    visible,
+		Rules:             rulesFromRow(poll[0]),
+		Outcome:           outcome,
+		IsOwner:           isOwner,
+		EmbedOrigins:      poll[0].EmbedOrigins,
+		Status:            poll[0].Status,
+		PublishAt:         timePtr(poll[0].PublishAt),
+		SeriesID:          int4Ptr(poll[0].SeriesID),
+		OrgID:             int4Ptr(poll[0].OrgID),
+		OrgName:           poll[0].OrgName.String,
+		MembersOnly:       poll[0].MembersOnly,
+		CanVote:           canVote,
+	}
+	if err := s.attachTags(ctx, []*PollResponse{response}); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (s *PollService) Vote(ctx context.Context, pollID int32, optionID int32, userID int32) error {
-	return s.Queries.VoteOneStep(ctx, db.VoteOneStepParams{
+	ctx, span := tracer.Start(ctx, "PollService.Vote", trace.WithAttributes(
+		attribute.Int("poll.id", int(pollID)),
+		attribute.Int("poll.option_id", int(optionID)),
+	))
+	defer span.End()
+
+	poll, err := s.Queries.GetPollByID(ctx, pollID)
+	if err != nil {
+		return err
+	}
+	if len(poll) == 0 {
+		return NotFound("poll.unavailable")
+	}
+	visible, _, err := s.pollAccess(ctx, poll[0].Hidden, poll[0].Status, poll[0].UserID, poll[0].OrgID, &userID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return NotFound("poll.unavailable")
+	}
+	// Solo llega hasta acá quien administra la encuesta, por ejemplo desde la vista previa
+	if poll[0].Status != PollPublished {
+		return Conflict("poll.not_published")
+	}
+	if pollClosed(poll[0].ClosesAt) {
+		return Conflict("poll.voting_closed")
+	}
+	canVote, err := s.canVote(ctx, poll[0].MembersOnly, poll[0].OrgID, &userID)
+	if err != nil {
+		return err
+	}
+	if !canVote {
+		return Forbidden("poll.members_only", poll[0].OrgName.String)
+	}
+	if !pollHasOption(poll, optionID) {
+		return InvalidField("option_id", "option.not_found")
+	}
+
+	err = s.Queries.VoteOneStep(ctx, db.VoteOneStepParams{
 		PollID:   pollID,
 		OptionID: optionID,
 		UserID:   userID,
 	})
+	if err != nil {
+		return err
+	}
+	recordVote(pollID)
+	s.Webhooks.Emit(ctx, poll[0].UserID, EventPollVoted, PollEventData{
+		PollID:   pollID,
+		Title:    poll[0].Title,
+		OptionID: &optionID,
+	})
+	s.Notifications.Notify(ctx, poll[0].UserID, NotificationVote, pollID, userID)
+	return nil
 }
 
-func (s *PollService) GetPolls(ctx context.Context, userID int32) ([]*PollResponse, error) {
-	rows, err := s.Queries.GetAllPolls(ctx, userID)
+// pollHasOption dice si optionID es una de las opciones de la encuesta; sin este chequeo un
+// voto podría contar para la opción de otra encuesta.
+func pollHasOption(poll []db.GetPollByIDRow, optionID int32) bool {
+	for _, row := range poll {
+		if row.OptionID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPolls lista las encuestas visibles que cumplen el filtro.
+func (s *PollService) GetPolls(ctx context.Context, filter PollFilter) ([]*PollResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.GetPolls")
+	defer span.End()
+
+	rows, err := s.Queries.GetAllPolls(ctx, filter.params())
 	if err != nil {
 		return nil, err
 	}
 
-	return s.mapGetAllPollsRowsToPolls(rows)
+	polls, err := s.mapGetAllPollsRowsToPolls(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, polls); err != nil {
+		return nil, err
+	}
+	return polls, nil
 }
 
+// GetPollsByUser lista las encuestas de ownerID y las de las organizaciones en las que es
+// dueño o editor, que también puede administrar.
 func (s *PollService) GetPollsByUser(ctx context.Context, ownerID int32, viewerID int32) ([]*PollResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.GetPollsByUser")
+	defer span.End()
+
+	orgs, err := s.EditableOrgs(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	orgIDs := make([]int32, 0, len(orgs))
+	for _, org := range orgs {
+		orgIDs = append(orgIDs, org.ID)
+	}
 	rows, err := s.Queries.GetPollsByUserID(ctx, db.GetPollsByUserIDParams{
 		OwnerID:  ownerID,
 		ViewerID: viewerID,
+		OrgIds:   orgIDs,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	return s.mapGetPollsByUserIDRowsToPolls(ctx, rows)
+}
+
+// GetPollsByOrg lista las encuestas de la organización para uno de sus miembros. Los
+// borradores y programadas solo los ven quienes pueden administrarlas.
+func (s *PollService) GetPollsByOrg(ctx context.Context, orgID int32, viewerID int32) ([]*PollResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.GetPollsByOrg")
+	defer span.End()
+
+	role, err := s.Queries.GetOrganizationRole(ctx, db.GetOrganizationRoleParams{OrgID: orgID, UserID: viewerID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrgNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Queries.GetPollsByOrgID(ctx, db.GetPollsByOrgIDParams{
+		ViewerID:  viewerID,
+		OrgID:     pgtype.Int4{Int32: orgID, Valid: true},
+		CanManage: canManageOrgPolls(role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	userRows := make([]db.GetPollsByUserIDRow, 0, len(rows))
+	for _, row := range rows {
+		userRows = append(userRows, db.GetPollsByUserIDRow(row))
+	}
+	return s.mapGetPollsByUserIDRowsToPolls(ctx, userRows)
+}
+
+func (s *PollService) mapGetPollsByUserIDRowsToPolls(ctx context.Context, rows []db.GetPollsByUserIDRow) ([]*PollResponse, error) {
 	pollsMap := make(map[int32]*PollResponse)
 
 	for _, row := range rows {
@@ -223,6 +580,11 @@ func (s *PollService) GetPollsByUser(ctx context.Context, ownerID int32, viewerI
 				UserID:            row.UserID,
 				Options:           []OptionResponse{},
 				UserVotedOptionID: userVotedOptionID,
+				Hidden:            row.Hidden,
+				Status:            row.Status,
+				PublishAt:         timePtr(row.PublishAt),
+				OrgID:             int4Ptr(row.OrgID),
+				OrgName:           row.OrgName.String,
 			}
 		}
 
@@ -238,6 +600,9 @@ func (s *PollService) GetPollsByUser(ctx context.Context, ownerID int32, viewerI
 		result = append(result, poll)
 	}
 
+	if err := s.attachTags(ctx, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -258,6 +623,8 @@ func (s *PollService) mapGetAllPollsRowsToPolls(rows []db.GetAllPollsRow) ([]*Po
 				UserID:            row.UserID,
 				Options:           []OptionResponse{},
 				UserVotedOptionID: userVotedOptionID,
+				// GetAllPolls solo lista encuestas publicadas
+				Status: PollPublished,
 			}
 		}
 
@@ -276,21 +643,154 @@ func (s *PollService) mapGetAllPollsRowsToPolls(rows []db.GetAllPollsRow) ([]*Po
 	return result, nil
 }
 
-func (s *PollService) DeletePoll(ctx context.Context, id int32) error {
-	return s.Queries.DeletePoll(ctx, id)
+// DeletePoll borra una encuesta de userID. La moderación usa AdminService.DeletePoll.
+func (s *PollService) DeletePoll(ctx context.Context, id int32, userID int32) error {
+	ctx, span := tracer.Start(ctx, "PollService.DeletePoll")
+	defer span.End()
+
+	poll, err := s.ownedPoll(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.Queries.DeletePoll(ctx, id); err != nil {
+		return err
+	}
+	s.Webhooks.Emit(ctx, poll.UserID, EventPollDeleted, PollEventData{PollID: id, Title: poll.Title})
+	return nil
+}
+
+// ownedPoll carga la encuesta y verifica que sea de userID antes de modificarla.
+func (s *PollService) ownedPoll(ctx context.Context, pollID int32, userID int32) (*db.GetPollByIDRow, error) {
+	rows, err := s.Queries.GetPollByID(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrPollNotFound
+	}
+	owner, err := s.canManage(ctx, rows[0].UserID, rows[0].OrgID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if !owner {
+		return nil, Forbidden("poll.not_owner")
+	}
+	return &rows[0], nil
+}
+
+// canManage indica si userID puede administrar la encuesta: es quien la creó o es dueño o
+// editor de la organización a la que pertenece.
+func (s *PollService) canManage(ctx context.Context, ownerID int32, orgID pgtype.Int4, userID *int32) (bool, error) {
+	if userID == nil {
+		return false, nil
+	}
+	if *userID == ownerID {
+		return true, nil
+	}
+	if !orgID.Valid {
+		return false, nil
+	}
+	role, err := s.Queries.GetOrganizationRole(ctx, db.GetOrganizationRoleParams{OrgID: orgID.Int32, UserID: *userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return canManageOrgPolls(role), err
 }
 
-func (s *PollService) UpdateOption(ctx context.Context, params OptionResponse) (*OptionResponse, error) {
+// canVote indica si userID cumple las condiciones para votar: en las encuestas solo para
+// miembros tiene que pertenecer a la organización, con cualquier rol.
+func (s *PollService) canVote(ctx context.Context, membersOnly bool, orgID pgtype.Int4, userID *int32) (bool, error) {
+	if !membersOnly || !orgID.Valid {
+		return true, nil
+	}
+	if userID == nil {
+		return false, nil
+	}
+	_, err := s.Queries.GetOrganizationRole(ctx, db.GetOrganizationRoleParams{OrgID: orgID.Int32, UserID: *userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// checkPollOrg verifica que userID pueda crear o mover encuestas a la organización orgID.
+func (s *PollService) checkPollOrg(ctx context.Context, orgID *int32, userID int32) error {
+	if orgID == nil {
+		return nil
+	}
+	role, err := s.Queries.GetOrganizationRole(ctx, db.GetOrganizationRoleParams{OrgID: *orgID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !canManageOrgPolls(role)) {
+		return InvalidField("org_id", "poll.org_not_allowed")
+	}
+	return err
+}
+
+// EditableOrgs devuelve las organizaciones en las que userID puede crear encuestas.
+func (s *PollService) EditableOrgs(ctx context.Context, userID int32) ([]Organization, error) {
+	ctx, span := tracer.Start(ctx, "PollService.EditableOrgs")
+	defer span.End()
+
+	rows, err := s.Queries.ListUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var orgs []Organization
+	for _, row := range rows {
+		if canManageOrgPolls(row.Role) {
+			orgs = append(orgs, Organization{ID: row.ID, Name: row.Name, Role: row.Role, CreatedAt: row.CreatedAt.Time})
+		}
+	}
+	return orgs, nil
+}
+
+func (s *PollService) UpdateOption(ctx context.Context, params OptionResponse, userID int32) (*OptionResponse, error) {
+	ctx, span := tracer.Start(ctx, "PollService.UpdateOption")
+	defer span.End()
+
 	if params.Content == "" {
-		return nil, errors.New("el contenido de la opción no puede estar vacío")
+		return nil, InvalidField("content", "option.content_required")
+	}
+
+	option, err := s.Queries.GetOptionByID(ctx, params.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NotFound("option.not_found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedPoll(ctx, option.PollID, userID); err != nil {
+		return nil, err
+	}
+
+	term, flagged := s.Filter.Match(params.Content)
+	if flagged && s.Filter.Mode == FilterReject {
+		return nil, InvalidField("content", "option.blocked_words")
 	}
 
-	updatedOption, err := s.Queries.UpdateOption(ctx, db.UpdateOptionParams{
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	updatedOption, err := qtx.UpdateOption(ctx, db.UpdateOptionParams{
 		ID:      params.ID,
 		Content: params.Content,
 	})
 
 	if err != nil {
+		return nil, uniqueViolation(err, "unique_option", "content", "option.duplicate", params.Content)
+	}
+
+	if flagged {
+		if err := s.Reports.flagPoll(ctx, qtx, updatedOption.PollID, term); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 
@@ -301,14 +801,21 @@ func (s *PollService) UpdateOption(ctx context.Context, params OptionResponse) (
 	}, nil
 }
 
-func (s *PollService) DeleteOption(ctx context.Context, id int32, poll_id int32) error {
+func (s *PollService) DeleteOption(ctx context.Context, id int32, poll_id int32, userID int32) error {
+	ctx, span := tracer.Start(ctx, "PollService.DeleteOption")
+	defer span.End()
+
+	if _, err := s.ownedPoll(ctx, poll_id, userID); err != nil {
+		return err
+	}
+
 	options, err := s.Queries.GetOptionByPollID(ctx, poll_id)
 	if err != nil {
 		return err
 	}
 
 	if len(options) == 2 {
-		return errors.New("la encuesta debe tener al menos 2 opciones")
+		return Conflict("poll.min_options_remaining")
 	}
 
 	for _, option := range options {
@@ -317,5 +824,50 @@ func (s *PollService) DeleteOption(ctx context.Context, id int32, poll_id int32)
 		}
 	}
 
-	return errors.New("opcion no encontrada")
+	return NotFound("option.not_found")
+}
+
+// resultsVisible aplica la política de visibilidad de resultados. El dueño siempre los ve.
+func resultsVisible(visibility string, isOwner, hasVoted, closed bool) bool {
+	if isOwner {
+		return true
+	}
+	switch visibility {
+	case ResultsAfterVote:
+		return hasVoted || closed
+	case ResultsAfterClose:
+		return closed
+	case ResultsOwnerOnly:
+		return false
+	default:
+		return true
+	}
+}
+
+func isResultsVisibility(visibility string) bool {
+	for _, v := range ResultsVisibilities {
+		if v == visibility {
+			return true
+		}
+	}
+	return false
+}
+
+// pollClosed indica si ya pasó la fecha de cierre (las encuestas sin fecha no cierran).
+func pollClosed(closesAt pgtype.Timestamptz) bool {
+	return closesAt.Valid && !closesAt.Time.After(time.Now())
+}
+
+func timestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPollHasOption(t *testing.T) {
+	poll := []db.GetPollByIDRow{
+		{ID: 1, OptionID: 10},
+		{ID: 1, OptionID: 11},
+	}
+	tests := []struct {
+		optionID int32
+		want     bool
+	}{
+		{10, true},
+		{11, true},
+		{12, false}, // opción de otra encuesta
+		{0, false},
+	}
+	for _, tt := range tests {
+		if got := pollHasOption(poll, tt.optionID); got != tt.want {
+			t.Errorf("pollHasOption(%d) = %v, want %v", tt.optionID, got, tt.want)
+		}
+	}
+}
+
+func TestResultsVisible(t *testing.T) {
+	tests := []struct {
+		visibility string
+		owner      bool
+		voted      bool
+		closed     bool
+		want       bool
+	}{
+		{ResultsAlways, false, false, false, true},
+		{ResultsAfterVote, false, false, false, false},
+		{ResultsAfterVote, false, true, false, true},
+		{ResultsAfterVote, false, false, true, true},
+		{ResultsAfterClose, false, true, false, false},
+		{ResultsAfterClose, false, false, true, true},
+		{ResultsOwnerOnly, false, true, true, false},
+		{ResultsOwnerOnly, true, false, false, true},
+		{ResultsAfterClose, true, false, false, true},
+	}
+	for _, tt := range tests {
+		if got := resultsVisible(tt.visibility, tt.owner, tt.voted, tt.closed); got != tt.want {
+			t.Errorf("resultsVisible(%q, owner=%v, voted=%v, closed=%v) = %v, want %v",
+				tt.visibility, tt.owner, tt.voted, tt.closed, got, tt.want)
+		}
+	}
+}
+
+func TestPollClosed(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		closesAt pgtype.Timestamptz
+		want     bool
+	}{
+		{"sin fecha", pgtype.Timestamptz{}, false},
+		{"cierra después", pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true}, false},
+		{"ya cerró", pgtype.Timestamptz{Time: now.Add(-time.Second), Valid: true}, true},
+	}
+	for _, tt := range tests {
+		if got := pollClosed(tt.closesAt); got != tt.want {
+			t.Errorf("pollClosed(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Límites de las etiquetas de una encuesta.
+const (
+	maxTagsPerPoll = 5
+	maxTagLength   = 30
+	// maxTagCloud es la cantidad de etiquetas que muestra /tags
+	maxTagCloud = 100
+	// maxTagSuggestions es la cantidad de sugerencias del autocompletado
+	maxTagSuggestions = 8
+)
+
+// Estados por los que se puede filtrar el listado.
+const (
+	StatusOpen   = "open"
+	StatusClosed = "closed"
+)
+
+// tagRe son los caracteres válidos de una etiqueta ya normalizada.
+var tagRe = regexp.MustCompile(`^[\p{L}\p{N}]+(-[\p{L}\p{N}]+)*$`)
+
+// PollFilter son los criterios del listado público de encuestas. Los campos vacíos no
+// filtran, así que se pueden combinar libremente.
+type PollFilter struct {
+	// ViewerID marca el voto de quien consulta (0 = anónimo)
+	ViewerID int32
+	// Tag deja solo las encuestas con esa etiqueta
+	Tag string
+	// Search busca en la pregunta, sin distinguir mayúsculas
+	Search string
+	// Status es StatusOpen, StatusClosed o vacío para todas
+	Status string
+}
+
+// TagCount es una etiqueta con la cantidad de encuestas visibles que la usan.
+type TagCount struct {
+	Name  string `json:"name"`
+	Polls int64  `json:"polls"`
+}
+
+// NormalizeTag deja una etiqueta en su forma canónica: sin '#', en minúsculas y con
+// guiones en lugar de espacios. Devuelve "" si no queda nada.
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// normalizeTags normaliza, valida y deduplica las etiquetas de una encuesta.
+func normalizeTags(raw []string, v *validation) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range raw {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		switch {
+		case utf8.RuneCountInString(tag) > maxTagLength:
+			v.add("tags", "tag.too_long", tag, maxTagLength)
+		case !tagRe.MatchString(tag):
+			v.add("tags", "tag.invalid", tag)
+		default:
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxTagsPerPoll {
+		v.add("tags", "tag.too_many", maxTagsPerPoll)
+	}
+	return tags
+}
+
+// setPollTags crea las etiquetas que falten y las asocia a la encuesta.
+func setPollTags(ctx context.Context, q *db.Queries, pollID int32, tags []string) error {
+	for _, name := range tags {
+		tag, err := q.UpsertTag(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := q.AddPollTag(ctx, db.AddPollTagParams{PollID: pollID, TagID: tag.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachTags completa las etiquetas de las encuestas con una sola consulta.
+func (s *PollService) attachTags(ctx context.Context, polls []*PollResponse) error {
+	if len(polls) == 0 {
+		return nil
+	}
+	byID := make(map[int32]*PollResponse, len(polls))
+	ids := make([]int32, 0, len(polls))
+	for _, poll := range polls {
+		byID[poll.ID] = poll
+		ids = append(ids, poll.ID)
+	}
+
+	rows, err := s.Queries.GetTagsByPollIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		poll := byID[row.PollID]
+		poll.Tags = append(poll.Tags, row.Name)
+	}
+	return nil
+}
+
+// ListTags devuelve las etiquetas más usadas para la nube de /tags.
+func (s *PollService) ListTags(ctx context.Context) ([]TagCount, error) {
+	ctx, span := tracer.Start(ctx, "PollService.ListTags")
+	defer span.End()
+
+	rows, err := s.Queries.ListTagCounts(ctx, maxTagCloud)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]TagCount, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, TagCount{Name: row.Name, Polls: row.PollCount})
+	}
+	return tags, nil
+}
+
+// SuggestTags devuelve etiquetas existentes que empiezan con prefix (autocompletado).
+func (s *PollService) SuggestTags(ctx context.Context, prefix string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "PollService.SuggestTags")
+	defer span.End()
+
+	// Normalizado, el prefijo no puede traer comodines de LIKE
+	prefix = NormalizeTag(prefix)
+	if prefix == "" || !tagRe.MatchString(strings.TrimSuffix(prefix, "-")) {
+		return nil, nil
+	}
+	return s.Queries.SearchTags(ctx, db.SearchTagsParams{Prefix: prefix, MaxTags: maxTagSuggestions})
+}
+
+// params traduce el filtro a los parámetros opcionales de GetAllPolls.
+func (f PollFilter) params() db.GetAllPollsParams {
+	params := db.GetAllPollsParams{UserID: f.ViewerID}
+	if tag := NormalizeTag(f.Tag); tag != "" {
+		params.Tag = pgtype.Text{String: tag, Valid: true}
+	}
+	if search := strings.TrimSpace(f.Search); search != "" {
+		params.Search = pgtype.Text{String: escapeLike(search), Valid: true}
+	}
+	switch f.Status {
+	case StatusOpen:
+		params.Open = pgtype.Bool{Bool: true, Valid: true}
+	case StatusClosed:
+		params.Open = pgtype.Bool{Bool: false, Valid: true}
+	}
+	return params
+}
+
+// escapeLike escapa los comodines de LIKE para buscar el texto tal cual.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"go", "go"},
+		{"  Go  ", "go"},
+		{"#Golang", "golang"},
+		{"Comida Rápida", "comida-rápida"},
+		{"  muchos   espacios  ", "muchos-espacios"},
+		{"Ñandú", "ñandú"},
+		{"#", ""},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTag(tt.tag); got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   []string
+		want  []string
+		codes []string // errores de validación esperados, en orden
+	}{
+		{"sin etiquetas", nil, nil, nil},
+		{"duplicadas tras normalizar", []string{"Go", "#go", " GO "}, []string{"go"}, nil},
+		{"vacías", []string{"", "#", "equipo"}, []string{"equipo"}, nil},
+		{"caracteres inválidos", []string{"c++", "ok"}, []string{"ok"}, []string{"tag.invalid"}},
+		{"muy larga", []string{strings.Repeat("a", maxTagLength+1)}, nil, []string{"tag.too_long"}},
+		{"demasiadas", []string{"a", "b", "c", "d", "e", "f"}, []string{"a", "b", "c", "d", "e", "f"}, []string{"tag.too_many"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v validation
+			got := normalizeTags(tt.raw, &v)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+			var codes []string
+			for _, f := range v.fields {
+				if f.Field != "tags" {
+					t.Errorf("error en el campo %q, want tags", f.Field)
+				}
+				codes = append(codes, f.Err.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.codes) {
+				t.Errorf("errores = %q, want %q", codes, tt.codes)
+			}
+		})
+	}
+}
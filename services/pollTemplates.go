@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTemplateNotFound se devuelve cuando la plantilla no existe o es de otro usuario.
+var ErrTemplateNotFound = NotFound("template.not_found")
+
+// PollTemplate es una encuesta guardada para volver a crearla. El título puede llevar
+// marcadores (ver ExpandTitle) y el cierre se guarda como plazo, no como fecha.
+type PollTemplate struct {
+	ID                int32    `json:"id"`
+	Name              string   `json:"name"`
+	Title             string   `json:"title"`
+	Options           []string `json:"options"`
+	ResultsVisibility string   `json:"results_visibility"`
+	// ClosesInMinutes es cuánto dura abierta la encuesta desde que se crea (nil = sin cierre)
+	ClosesInMinutes *int32        `json:"closes_in_minutes"`
+	Rules           DecisionRules `json:"rules"`
+	Tags            []string      `json:"tags"`
+}
+
+// ExpandTitle reemplaza los marcadores del título con la fecha now: {{date}} (en el
+// formato del idioma del contexto), {{year}}, {{month}} y {{week}} (semana ISO).
+func ExpandTitle(ctx context.Context, title string, now time.Time) string {
+	_, week := now.ISOWeek()
+	return strings.NewReplacer(
+		"{{date}}", i18n.Date(ctx, now),
+		"{{year}}", strconv.Itoa(now.Year()),
+		"{{month}}", fmt.Sprintf("%02d", now.Month()),
+		"{{week}}", fmt.Sprintf("%02d", week),
+	).Replace(title)
+}
+
+// Draft arma el pedido de creación a partir de la plantilla, con los marcadores ya
+// reemplazados y el cierre contado desde now.
+func (t *PollTemplate) Draft(ctx context.Context, now time.Time) *PollRequest {
+	draft := &PollRequest{
+		Question:          ExpandTitle(ctx, t.Title, now),
+		ResultsVisibility: t.ResultsVisibility,
+		Rules:             t.Rules,
+		Tags:              t.Tags,
+	}
+	for _, option := range t.Options {
+		draft.Options = append(draft.Options, OptionRequest{Content: option})
+	}
+	if t.ClosesInMinutes != nil {
+		closesAt := now.Add(time.Duration(*t.ClosesInMinutes) * time.Minute)
+		draft.ClosesAt = &closesAt
+	}
+	return draft
+}
+
+// SaveTemplate guarda params como plantilla de userID. Sin nombre se usa la pregunta; si ya
+// hay una plantilla con ese nombre se reemplaza.
+func (s *PollService) SaveTemplate(ctx context.Context, userID int32, name string, params PollRequest) (*PollTemplate, error) {
+	ctx, span := tracer.Start(ctx, "PollService.SaveTemplate")
+	defer span.End()
+
+	tags, err := params.validate()
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = params.Question
+	}
+
+	var options []string
+	for _, option := range params.Options {
+		if option.Content != "" {
+			options = append(options, option.Content)
+		}
+	}
+	if len(options) < 2 {
+		return nil, InvalidField("options", "poll.min_options")
+	}
+
+	var closesIn *int32
+	if params.ClosesAt != nil {
+		minutes := int32(time.Until(*params.ClosesAt).Round(time.Minute) / time.Minute)
+		closesIn = &minutes
+	}
+
+	row, err := s.Queries.UpsertPollTemplate(ctx, db.UpsertPollTemplateParams{
+		UserID:            userID,
+		Name:              name,
+		Title:             params.Question,
+		Options:           options,
+		ResultsVisibility: params.ResultsVisibility,
+		ClosesInMinutes:   int4(closesIn),
+		QuorumVotes:       int4(params.Rules.QuorumVotes),
+		InvitedVoters:     int4(params.Rules.InvitedVoters),
+		QuorumPercent:     int4(params.Rules.QuorumPercent),
+		MajorityPercent:   params.Rules.MajorityPercent,
+		TieBreak:          params.Rules.TieBreak,
+		Tags:              append([]string{}, tags...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return templateFromRow(row), nil
+}
+
+// SavePollAsTemplate guarda una encuesta de userID como plantilla con su mismo título.
+func (s *PollService) SavePollAsTemplate(ctx context.Context, pollID, userID int32) (*PollTemplate, error) {
+	ctx, span := tracer.Start(ctx, "PollService.SavePollAsTemplate")
+	defer span.End()
+
+	poll, err := s.ownedPollResponse(ctx, pollID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// La fecha de cierre de la original no sirve para las próximas: la plantilla queda sin cierre
+	draft := requestFromPoll(poll)
+	draft.ClosesAt = nil
+	return s.SaveTemplate(ctx, userID, poll.Title, *draft)
+}
+
+// ListTemplates devuelve las plantillas de userID ordenadas por nombre.
+func (s *PollService) ListTemplates(ctx context.Context, userID int32) ([]PollTemplate, error) {
+	ctx, span := tracer.Start(ctx, "PollService.ListTemplates")
+	defer span.End()
+
+	rows, err := s.Queries.ListPollTemplates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]PollTemplate, 0, len(rows))
+	for _, row := range rows {
+		templates = append(templates, *templateFromRow(row))
+	}
+	return templates, nil
+}
+
+// GetTemplate devuelve una plantilla de userID.
+func (s *PollService) GetTemplate(ctx context.Context, id, userID int32) (*PollTemplate, error) {
+	row, err := s.Queries.GetPollTemplate(ctx, db.GetPollTemplateParams{ID: id, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return templateFromRow(row), nil
+}
+
+// DeleteTemplate borra una plantilla de userID.
+func (s *PollService) DeleteTemplate(ctx context.Context, id, userID int32) error {
+	ctx, span := tracer.Start(ctx, "PollService.DeleteTemplate")
+	defer span.End()
+
+	n, err := s.Queries.DeletePollTemplate(ctx, db.DeletePollTemplateParams{ID: id, UserID: userID})
+	if err != nil {
+		return foreignKeyViolation(err, "poll_series_template_id_fkey", "template.in_use")
+	}
+	if n == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// DuplicateDraft copia pregunta, opciones y configuración de una encuesta de userID para
+// crear otra. El título lleva una marca de copia porque tiene que ser único por dueño.
+func (s *PollService) DuplicateDraft(ctx context.Context, pollID, userID int32) (*PollRequest, error) {
+	ctx, span := tracer.Start(ctx, "PollService.DuplicateDraft")
+	defer span.End()
+
+	poll, err := s.ownedPollResponse(ctx, pollID, userID)
+	if err != nil {
+		return nil, err
+	}
+	draft := requestFromPoll(poll)
+	draft.Question = i18n.T(ctx, "poll.copy_title", poll.Title)
+	if poll.Closed {
+		draft.ClosesAt = nil
+	}
+	return draft, nil
+}
+
+// ownedPollResponse carga la encuesta completa (con reglas y etiquetas) y verifica que sea de userID.
+func (s *PollService) ownedPollResponse(ctx context.Context, pollID, userID int32) (*PollResponse, error) {
+	poll, err := s.GetPollByID(ctx, pollID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.IsOwner {
+		return nil, Forbidden("poll.not_owner")
+	}
+	return poll, nil
+}
+
+func requestFromPoll(poll *PollResponse) *PollRequest {
+	draft := &PollRequest{
+		Question:          poll.Title,
+		UserID:            poll.UserID,
+		ClosesAt:          poll.ClosesAt,
+		ResultsVisibility: poll.ResultsVisibility,
+		Rules:             poll.Rules,
+		Tags:              poll.Tags,
+		OrgID:             poll.OrgID,
+		MembersOnly:       poll.MembersOnly,
+	}
+	for _, option := range poll.Options {
+		draft.Options = append(draft.Options, OptionRequest{Content: option.Content})
+	}
+	return draft
+}
+
+func templateFromRow(row db.PollTemplate) *PollTemplate {
+	return &PollTemplate{
+		ID:                row.ID,
+		Name:              row.Name,
+		Title:             row.Title,
+		Options:           row.Options,
+		ResultsVisibility: row.ResultsVisibility,
+		ClosesInMinutes:   int4Ptr(row.ClosesInMinutes),
+		Rules: DecisionRules{
+			QuorumVotes:     int4Ptr(row.QuorumVotes),
+			InvitedVoters:   int4Ptr(row.InvitedVoters),
+			QuorumPercent:   int4Ptr(row.QuorumPercent),
+			MajorityPercent: row.MajorityPercent,
+			TieBreak:        row.TieBreak,
+		},
+		Tags: row.Tags,
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestExpandTitle(t *testing.T) {
+	// Lunes 30 de diciembre de 2024: ya es la semana 1 de 2025 según ISO
+	now := time.Date(2024, time.December, 30, 10, 0, 0, 0, time.Local)
+	es := i18n.WithLocale(context.Background(), i18n.ES)
+	en := i18n.WithLocale(context.Background(), i18n.EN)
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		title string
+		want  string
+	}{
+		{"sin marcadores", es, "Retro semanal", "Retro semanal"},
+		{"fecha en español", es, "Retro del {{date}}", "Retro del 30/12/2024"},
+		{"fecha en inglés", en, "Retro {{date}}", "Retro Dec 30, 2024"},
+		{"año, mes y semana", es, "{{year}}-{{month}} S{{week}}", "2024-12 S01"},
+		{"repetidos", es, "{{month}}/{{month}}", "12/12"},
+		{"desconocido", es, "{{día}} {{date", "{{día}} {{date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandTitle(tt.ctx, tt.title, now); got != tt.want {
+				t.Errorf("ExpandTitle(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateDraft(t *testing.T) {
+	closed := pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+	fake := newFakeDB().add("GetPollByID",
+		db.GetPollByIDRow{ID: 1, Title: "Almuerzo", UserID: 7, ClosesAt: closed, ResultsVisibility: ResultsAfterVote, Status: PollPublished, OptionID: 10, OptionContent: "Pizza"},
+		db.GetPollByIDRow{ID: 1, Title: "Almuerzo", UserID: 7, ClosesAt: closed, ResultsVisibility: ResultsAfterVote, Status: PollPublished, OptionID: 11, OptionContent: "Sushi"},
+	)
+	s := &PollService{Queries: db.New(fake)}
+
+	tests := []struct {
+		name   string
+		locale string
+		userID int32
+		want   string
+		err    error
+	}{
+		{"copia en español", i18n.ES, 7, "Almuerzo (copia)", nil},
+		{"copia en inglés", i18n.EN, 7, "Almuerzo (copy)", nil},
+		{"de otra persona", i18n.ES, 8, "", Forbidden("poll.not_owner")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft, err := s.DuplicateDraft(i18n.WithLocale(context.Background(), tt.locale), 1, tt.userID)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("DuplicateDraft = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if draft.Question != tt.want {
+				t.Errorf("Question = %q, want %q", draft.Question, tt.want)
+			}
+			// La copia de una encuesta cerrada no hereda la fecha de cierre vencida
+			if draft.ClosesAt != nil || draft.ResultsVisibility != ResultsAfterVote {
+				t.Errorf("ClosesAt = %v y ResultsVisibility = %q, want nil y %q", draft.ClosesAt, draft.ResultsVisibility, ResultsAfterVote)
+			}
+			if len(draft.Options) != 2 || draft.Options[0].Content != "Pizza" || draft.Options[1].Content != "Sushi" {
+				t.Errorf("Options = %+v, want Pizza y Sushi", draft.Options)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimitResult es el resultado de pedir un token a un bucket.
+type RateLimitResult struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// LockoutPolicy define el bloqueo progresivo tras intentos fallidos:
+// a partir de Threshold fallos se bloquea BaseDelay, duplicando en cada fallo extra hasta MaxDelay.
+type LockoutPolicy struct {
+	Threshold int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// ResetAfter olvida los fallos si no hubo ninguno en ese periodo.
+	ResetAfter time.Duration
+}
+
+// RateLimitStore guarda el estado de los token buckets y de los bloqueos de login.
+// Hay una implementación en memoria (una sola réplica) y otra en Postgres (varias réplicas).
+type RateLimitStore interface {
+	// Take consume un token del bucket key, que se rellena a rate tokens por segundo hasta burst.
+	Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error)
+	// LockedFor devuelve cuánto tiempo le queda bloqueado a key (0 si no lo está).
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// RegisterFailure suma un fallo a key y devuelve el bloqueo resultante (0 si no se bloqueó).
+	RegisterFailure(ctx context.Context, key string, policy LockoutPolicy) (time.Duration, error)
+	// ResetFailures olvida los fallos de key (p. ej. tras un login correcto).
+	ResetFailures(ctx context.Context, key string) error
+	// RunCleanup borra periódicamente los buckets y fallos viejos hasta que ctx se cancele.
+	RunCleanup(ctx context.Context)
+}
+
+// refillBucket calcula los tokens disponibles en un bucket y consume uno si alcanza.
+func refillBucket(tokens float64, last, now time.Time, rate float64, burst int) (float64, RateLimitResult) {
+	available := math.Min(float64(burst), tokens+now.Sub(last).Seconds()*rate)
+	if available >= 1 {
+		return available - 1, RateLimitResult{Allowed: true}
+	}
+	wait := time.Duration((1 - available) / rate * float64(time.Second))
+	return available, RateLimitResult{Allowed: false, RetryAfter: wait}
+}
+
+// lockoutDuration devuelve el bloqueo que corresponde a una cantidad de fallos.
+func lockoutDuration(failures int, policy LockoutPolicy) time.Duration {
+	if failures < policy.Threshold {
+		return 0
+	}
+	delay := policy.BaseDelay
+	for i := policy.Threshold; i < failures && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	return delay
+}
+
+// ---------------------------------------------------------------------------
+// Implementación en memoria
+// ---------------------------------------------------------------------------
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type memoryFailure struct {
+	failures    int
+	lockedUntil time.Time
+	lastFailure time.Time
+}
+
+// MemoryRateLimitStore guarda los buckets en memoria. Sirve para una sola réplica.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*memoryBucket
+	failures map[string]*memoryFailure
+}
+
+// NewMemoryRateLimitStore crea el store en memoria. La limpieza de entradas viejas corre
+// aparte, con RunCleanup.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:  make(map[string]*memoryBucket),
+		failures: make(map[string]*memoryFailure),
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, result := refillBucket(bucket.tokens, bucket.last, now, rate, burst)
+	bucket.tokens = tokens
+	bucket.last = now
+	return result, nil
+}
+
+func (s *MemoryRateLimitStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.failures[key]; ok {
+		if remaining := time.Until(f.lockedUntil); remaining > 0 {
+			return remaining, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *MemoryRateLimitStore) RegisterFailure(ctx context.Context, key string, policy LockoutPolicy) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	f, ok := s.failures[key]
+	if !ok || (policy.ResetAfter > 0 && now.Sub(f.lastFailure) > policy.ResetAfter) {
+		f = &memoryFailure{}
+		s.failures[key] = f
+	}
+	f.failures++
+	f.lastFailure = now
+
+	lock := lockoutDuration(f.failures, policy)
+	if lock > 0 {
+		f.lockedUntil = now.Add(lock)
+	}
+	return lock, nil
+}
+
+func (s *MemoryRateLimitStore) ResetFailures(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *MemoryRateLimitStore) RunCleanup(ctx context.Context) {
+	s.cleanup(ctx, time.Minute, time.Hour)
+}
+
+// cleanup borra cada every los buckets y fallos que llevan maxIdle sin usarse, hasta que
+// ctx se cancele.
+func (s *MemoryRateLimitStore) cleanup(ctx context.Context, every, maxIdle time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.prune(now, maxIdle)
+		}
+	}
+}
+
+func (s *MemoryRateLimitStore) prune(now time.Time, maxIdle time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.last) > maxIdle {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if now.Sub(f.lastFailure) > maxIdle && now.After(f.lockedUntil) {
+			delete(s.failures, key)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Implementación en Postgres
+// ---------------------------------------------------------------------------
+
+// PostgresRateLimitStore guarda los buckets en Postgres para compartirlos entre réplicas.
+// Cada operación bloquea la fila con SELECT ... FOR UPDATE dentro de una transacción.
+type PostgresRateLimitStore struct {
+	Queries *db.Queries
+	DB      *pgxpool.Pool
+}
+
+// NewPostgresRateLimitStore crea el store en Postgres. La limpieza de filas viejas corre
+// aparte, con RunCleanup.
+func NewPostgresRateLimitStore(queries *db.Queries, pool *pgxpool.Pool) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{Queries: queries, DB: pool}
+}
+
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	// La fila se crea llena antes de bloquearla: si no existiera, dos peticiones simultáneas
+	// no encontrarían nada que bloquear y las dos gastarían un token del mismo bucket lleno
+	now := time.Now()
+	err = qtx.InsertRateLimitBucket(ctx, db.InsertRateLimitBucketParams{
+		Key:       key,
+		Tokens:    float64(burst),
+		UpdatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	bucket, err := qtx.GetRateLimitBucketForUpdate(ctx, key)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	tokens, result := refillBucket(bucket.Tokens, bucket.UpdatedAt.Time, now, rate, burst)
+	err = qtx.UpsertRateLimitBucket(ctx, db.UpsertRateLimitBucketParams{
+		Key:       key,
+		Tokens:    tokens,
+		UpdatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RateLimitResult{}, err
+	}
+	return result, nil
+}
+
+func (s *PostgresRateLimitStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	// Solo se lee: no hace falta bloquear la fila
+	f, err := s.Queries.GetLoginFailure(ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !f.LockedUntil.Valid {
+		return 0, nil
+	}
+	return max(time.Until(f.LockedUntil.Time), 0), nil
+}
+
+func (s *PostgresRateLimitStore) RegisterFailure(ctx context.Context, key string, policy LockoutPolicy) (time.Duration, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	now := time.Now()
+	failures := 0
+	lockedUntil := pgtype.Timestamptz{}
+	f, err := qtx.GetLoginFailureForUpdate(ctx, key)
+	if err == nil {
+		if policy.ResetAfter <= 0 || now.Sub(f.LastFailureAt.Time) <= policy.ResetAfter {
+			failures = int(f.Failures)
+			lockedUntil = f.LockedUntil
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	failures++
+
+	lock := lockoutDuration(failures, policy)
+	if lock > 0 {
+		lockedUntil = pgtype.Timestamptz{Time: now.Add(lock), Valid: true}
+	}
+
+	err = qtx.UpsertLoginFailure(ctx, db.UpsertLoginFailureParams{
+		Key:           key,
+		Failures:      int32(failures),
+		LockedUntil:   lockedUntil,
+		LastFailureAt: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return lock, nil
+}
+
+func (s *PostgresRateLimitStore) ResetFailures(ctx context.Context, key string) error {
+	return s.Queries.DeleteLoginFailure(ctx, key)
+}
+
+func (s *PostgresRateLimitStore) RunCleanup(ctx context.Context) {
+	s.cleanup(ctx, 10*time.Minute, time.Hour)
+}
+
+// cleanup borra cada every los buckets y fallos de login que llevan maxIdle sin usarse
+// (los fallos, solo si ya no bloquean), hasta que ctx se cancele.
+func (s *PostgresRateLimitStore) cleanup(ctx context.Context, every, maxIdle time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			before := pgtype.Timestamptz{Time: now.Add(-maxIdle), Valid: true}
+			if err := s.Queries.DeleteStaleRateLimitBuckets(ctx, before); err != nil {
+				slog.ErrorContext(ctx, "error limpiando buckets de rate limit", "error", err)
+			}
+			if err := s.Queries.DeleteStaleLoginFailures(ctx, before); err != nil {
+				slog.ErrorContext(ctx, "error limpiando fallos de login", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	policy := LockoutPolicy{Threshold: 3, BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, 30 * time.Second},
+		{4, time.Minute},
+		{6, 4 * time.Minute},
+		{7, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := lockoutDuration(tt.failures, policy); got != tt.want {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestRefillBucket(t *testing.T) {
+	now := time.Now()
+	tokens, result := refillBucket(1, now, now, 1, 5)
+	if !result.Allowed || tokens != 0 {
+		t.Fatalf("con un token: tokens=%v allowed=%v, want 0 y true", tokens, result.Allowed)
+	}
+	_, result = refillBucket(0, now, now.Add(500*time.Millisecond), 1, 5)
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("sin tokens: %+v, want rechazo con 500ms de espera", result)
+	}
+	tokens, _ = refillBucket(0, now, now.Add(time.Hour), 1, 5)
+	if tokens != 4 {
+		t.Fatalf("el bucket se rellena hasta burst: tokens=%v, want 4", tokens)
+	}
+}
+
+func TestMemoryRateLimitStoreFailures(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	policy := LockoutPolicy{Threshold: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+
+	if lock, _ := store.RegisterFailure(ctx, "ana", policy); lock != 0 {
+		t.Fatalf("primer fallo: bloqueo %v, want 0", lock)
+	}
+	if lock, _ := store.RegisterFailure(ctx, "ana", policy); lock != time.Minute {
+		t.Fatalf("segundo fallo: bloqueo %v, want 1m", lock)
+	}
+	if locked, _ := store.LockedFor(ctx, "ana"); locked <= 0 {
+		t.Error("ana no quedó bloqueada")
+	}
+	if locked, _ := store.LockedFor(ctx, "otra"); locked != 0 {
+		t.Errorf("otra clave bloqueada %v, want 0", locked)
+	}
+
+	store.ResetFailures(ctx, "ana")
+	if locked, _ := store.LockedFor(ctx, "ana"); locked != 0 {
+		t.Errorf("tras reiniciar sigue bloqueada %v", locked)
+	}
+}
+
+func TestMemoryRateLimitStorePrune(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	policy := LockoutPolicy{Threshold: 1, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	store.Take(ctx, "bucket", 1, 1)
+	store.RegisterFailure(ctx, "locked", policy)
+	store.RegisterFailure(ctx, "stale", LockoutPolicy{Threshold: 10})
+
+	store.prune(time.Now().Add(2*time.Minute), time.Minute)
+
+	if _, ok := store.buckets["bucket"]; ok {
+		t.Error("no se borró el bucket viejo")
+	}
+	if _, ok := store.failures["stale"]; ok {
+		t.Error("no se borró el fallo viejo")
+	}
+	if _, ok := store.failures["locked"]; !ok {
+		t.Error("se borró un fallo que todavía bloquea")
+	}
+}
+
+func TestMemoryRateLimitStoreCleanupStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewMemoryRateLimitStore().cleanup(ctx, time.Millisecond, time.Minute)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup no terminó al cancelar el contexto")
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frecuencias de una serie de encuestas recurrentes.
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	// FrequencyCron usa una expresión cron de cinco campos (minuto hora día mes día-de-semana)
+	FrequencyCron = "cron"
+)
+
+// Frequencies son las frecuencias válidas, en el orden en que se ofrecen en el formulario.
+var Frequencies = []string{FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyCron}
+
+// Recurrence dice cuándo se crea cada encuesta de una serie. La hora de las diarias,
+// semanales y mensuales (y el día del mes de las mensuales) es la de StartsAt.
+type Recurrence struct {
+	Frequency string `json:"frequency"`
+	// Weekdays son los días de las semanales: 0 es domingo, 6 es sábado
+	Weekdays []int32 `json:"weekdays"`
+	Cron     string  `json:"cron"`
+	// StartsAt es la primera ocurrencia posible
+	StartsAt time.Time `json:"starts_at"`
+}
+
+func (r *Recurrence) validate() error {
+	var v validation
+	if r.StartsAt.IsZero() {
+		v.add("starts_at", "series.starts_at_required")
+	}
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyMonthly:
+	case FrequencyWeekly:
+		if len(r.Weekdays) == 0 {
+			v.add("weekdays", "series.weekdays_required")
+		}
+		for _, day := range r.Weekdays {
+			if day < 0 || day > 6 {
+				v.add("weekdays", "series.invalid_weekday")
+				break
+			}
+		}
+	case FrequencyCron:
+		schedule, err := parseCron(r.Cron)
+		if err != nil {
+			v.add("cron", "series.invalid_cron")
+		} else if schedule.next(time.Now()).IsZero() {
+			v.add("cron", "series.cron_never")
+		}
+	default:
+		v.add("frequency", "series.invalid_frequency")
+	}
+	return v.err()
+}
+
+// Next devuelve la primera ocurrencia posterior a after (y no anterior a StartsAt), en la
+// zona horaria del servidor. Devuelve el tiempo cero si la regla no vuelve a ocurrir.
+func (r *Recurrence) Next(after time.Time) time.Time {
+	start := r.StartsAt.In(time.Local)
+	from := after.In(time.Local)
+	if from.Before(start) {
+		// Así StartsAt misma cuenta como ocurrencia
+		from = start.Add(-time.Nanosecond)
+	}
+
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly:
+		day := time.Date(from.Year(), from.Month(), from.Day(), start.Hour(), start.Minute(), 0, 0, time.Local)
+		// En ocho días aparece cualquier día de la semana
+		for i := 0; i < 8; i++ {
+			candidate := day.AddDate(0, 0, i)
+			if candidate.After(from) && (r.Frequency == FrequencyDaily || r.onWeekday(candidate.Weekday())) {
+				return candidate
+			}
+		}
+	case FrequencyMonthly:
+		for i := 0; i < 3; i++ {
+			month := time.Date(from.Year(), from.Month()+time.Month(i), 1, 0, 0, 0, 0, time.Local)
+			// El 31 cae el último día en los meses más cortos
+			day := min(start.Day(), daysIn(month))
+			candidate := time.Date(month.Year(), month.Month(), day, start.Hour(), start.Minute(), 0, 0, time.Local)
+			if candidate.After(from) {
+				return candidate
+			}
+		}
+	case FrequencyCron:
+		if schedule, err := parseCron(r.Cron); err == nil {
+			return schedule.next(from)
+		}
+	}
+	return time.Time{}
+}
+
+func (r *Recurrence) onWeekday(day time.Weekday) bool {
+	for _, d := range r.Weekdays {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.Local).Day()
+}
+
+var errInvalidCron = errors.New("invalid cron expression")
+
+// cronSchedule es una expresión cron ya interpretada: cada campo es un conjunto de bits
+// con los valores permitidos.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Como en cron, si se restringen día del mes y día de la semana alcanza con uno
+	domAny, dowAny bool
+}
+
+// parseCron interpreta "minuto hora día mes día-de-semana". Cada campo acepta *, valores,
+// rangos (1-5), listas (1,15) y pasos (*/15, 8-18/2). En el día de la semana 7 también es domingo.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errInvalidCron
+	}
+	var c cronSchedule
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+	return &c, nil
+}
+
+func parseCronField(field string, low, high int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, s, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return 0, errInvalidCron
+			}
+			part, step = base, n
+		}
+		from, to := low, high
+		if part != "*" {
+			first, last, isRange := strings.Cut(part, "-")
+			var err error
+			if from, err = strconv.Atoi(first); err != nil {
+				return 0, errInvalidCron
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(last); err != nil {
+					return 0, errInvalidCron
+				}
+			}
+		}
+		if from < low || to > high || from > to {
+			return 0, errInvalidCron
+		}
+		for n := from; n <= to; n += step {
+			bits |= 1 << n
+		}
+	}
+	return bits, nil
+}
+
+// next busca la primera ocurrencia posterior a after, saltando meses, días y horas que no
+// coinciden. Devuelve el tiempo cero si no hay ninguna en los próximos cinco años.
+func (c *cronSchedule) next(after time.Time) time.Time {
+	t := after.In(time.Local).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.Local)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.Local)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.Local)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
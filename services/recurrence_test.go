@@ -0,0 +1,126 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// at arma una fecha en la zona del servidor, que es en la que trabaja Recurrence.
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// El 6 de enero de 2025 es lunes
+	start := at(2025, time.January, 6, 9, 30)
+	tests := []struct {
+		name  string
+		rule  Recurrence
+		after time.Time
+		want  time.Time
+	}{
+		{"diaria antes del inicio", Recurrence{Frequency: FrequencyDaily, StartsAt: start}, at(2025, time.January, 1, 0, 0), start},
+		{"diaria en el inicio", Recurrence{Frequency: FrequencyDaily, StartsAt: start}, start, at(2025, time.January, 7, 9, 30)},
+		{"diaria antes de la hora", Recurrence{Frequency: FrequencyDaily, StartsAt: start}, at(2025, time.March, 3, 8, 0), at(2025, time.March, 3, 9, 30)},
+		{"diaria después de la hora", Recurrence{Frequency: FrequencyDaily, StartsAt: start}, at(2025, time.March, 3, 10, 0), at(2025, time.March, 4, 9, 30)},
+		{"semanal al día siguiente", Recurrence{Frequency: FrequencyWeekly, Weekdays: []int32{1, 3}, StartsAt: start}, start, at(2025, time.January, 8, 9, 30)},
+		{"semanal a la semana siguiente", Recurrence{Frequency: FrequencyWeekly, Weekdays: []int32{1}, StartsAt: start}, start, at(2025, time.January, 13, 9, 30)},
+		{"semanal el domingo", Recurrence{Frequency: FrequencyWeekly, Weekdays: []int32{0}, StartsAt: start}, start, at(2025, time.January, 12, 9, 30)},
+		{"mensual", Recurrence{Frequency: FrequencyMonthly, StartsAt: start}, start, at(2025, time.February, 6, 9, 30)},
+		{"mensual el 31 en febrero", Recurrence{Frequency: FrequencyMonthly, StartsAt: at(2025, time.January, 31, 8, 0)}, at(2025, time.January, 31, 8, 0), at(2025, time.February, 28, 8, 0)},
+		{"mensual el 31 en bisiesto", Recurrence{Frequency: FrequencyMonthly, StartsAt: at(2024, time.January, 31, 8, 0)}, at(2024, time.February, 1, 0, 0), at(2024, time.February, 29, 8, 0)},
+		{"mensual vuelve al 31", Recurrence{Frequency: FrequencyMonthly, StartsAt: at(2025, time.January, 31, 8, 0)}, at(2025, time.February, 28, 8, 0), at(2025, time.March, 31, 8, 0)},
+		{"cron cada lunes a las 10", Recurrence{Frequency: FrequencyCron, Cron: "0 10 * * 1", StartsAt: start}, start, at(2025, time.January, 6, 10, 0)},
+		{"cron no antes del inicio", Recurrence{Frequency: FrequencyCron, Cron: "0 10 * * 1", StartsAt: start}, at(2024, time.December, 1, 0, 0), at(2025, time.January, 6, 10, 0)},
+		{"cron inválido", Recurrence{Frequency: FrequencyCron, Cron: "0 10 *", StartsAt: start}, start, time.Time{}},
+		{"frecuencia desconocida", Recurrence{Frequency: "yearly", StartsAt: start}, start, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	start := at(2025, time.January, 6, 9, 30)
+	tests := []struct {
+		name string
+		rule Recurrence
+		ok   bool
+	}{
+		{"diaria", Recurrence{Frequency: FrequencyDaily, StartsAt: start}, true},
+		{"sin inicio", Recurrence{Frequency: FrequencyDaily}, false},
+		{"semanal sin días", Recurrence{Frequency: FrequencyWeekly, StartsAt: start}, false},
+		{"semanal con día inválido", Recurrence{Frequency: FrequencyWeekly, Weekdays: []int32{7}, StartsAt: start}, false},
+		{"cron válido", Recurrence{Frequency: FrequencyCron, Cron: "*/15 8-18 * * 1-5", StartsAt: start}, true},
+		{"cron que nunca ocurre", Recurrence{Frequency: FrequencyCron, Cron: "0 0 30 2 *", StartsAt: start}, false},
+		{"frecuencia desconocida", Recurrence{Frequency: "yearly", StartsAt: start}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.validate(); (err == nil) != tt.ok {
+				t.Errorf("validate = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr string
+		ok   bool
+	}{
+		{"* * * * *", true},
+		{"0 9 * * 1-5", true},
+		{"*/15 8-18/2 1,15 * 7", true},
+		{"59 23 31 12 0", true},
+		{"", false},
+		{"* * * *", false},
+		{"* * * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"5-1 * * * *", false},
+		{"*/0 * * * *", false},
+		{"a * * * *", false},
+	}
+	for _, tt := range tests {
+		if _, err := parseCron(tt.expr); (err == nil) != tt.ok {
+			t.Errorf("parseCron(%q) = %v, want ok=%v", tt.expr, err, tt.ok)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// El 6 de enero de 2025 es lunes
+	from := at(2025, time.January, 6, 9, 7)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", at(2025, time.January, 6, 9, 8)},
+		{"*/15 * * * *", at(2025, time.January, 6, 9, 15)},
+		{"0 9 * * *", at(2025, time.January, 7, 9, 0)},
+		{"0 9 * * 0", at(2025, time.January, 12, 9, 0)},
+		{"0 9 * * 7", at(2025, time.January, 12, 9, 0)}, // 7 también es domingo
+		{"0 0 1 3 *", at(2025, time.March, 1, 0, 0)},
+		{"0 0 29 2 *", at(2028, time.February, 29, 0, 0)},
+		// Con día del mes y día de la semana alcanza con que coincida uno
+		{"0 12 15 * 3", at(2025, time.January, 8, 12, 0)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := schedule.next(from); !got.Equal(tt.want) {
+			t.Errorf("next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Motivos de reporte. ReportReasonFiltered lo usa solo el filtro de contenido.
+const (
+	ReportReasonSpam       = "spam"
+	ReportReasonOffensive  = "offensive"
+	ReportReasonMisleading = "misleading"
+	ReportReasonOther      = "other"
+	ReportReasonFiltered   = "filtered"
+)
+
+// ReportReasons son los motivos que puede elegir un usuario al reportar.
+var ReportReasons = []string{ReportReasonSpam, ReportReasonOffensive, ReportReasonMisleading, ReportReasonOther}
+
+const maxReportDetails = 500
+
+// ReportService recibe los reportes de los usuarios y oculta automáticamente las
+// encuestas que acumulan demasiados.
+type ReportService struct {
+	Queries *db.Queries
+	DB      *pgxpool.Pool
+	// AutoHideThreshold es la cantidad de reportes abiertos a partir de la cual la encuesta
+	// se oculta sola hasta que la revise un moderador (0 = nunca).
+	AutoHideThreshold int
+}
+
+// NewReportService crea una nueva instancia de ReportService.
+func NewReportService(queries *db.Queries, db *pgxpool.Pool, autoHideThreshold int) *ReportService {
+	return &ReportService{Queries: queries, DB: db, AutoHideThreshold: autoHideThreshold}
+}
+
+// ReportPoll registra el reporte de un usuario. Devuelve true si con este reporte la encuesta quedó oculta.
+func (s *ReportService) ReportPoll(ctx context.Context, pollID, reporterID int32, reason, details string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "ReportService.ReportPoll")
+	defer span.End()
+
+	if !isReportReason(reason) {
+		return false, InvalidField("reason", "report.invalid_reason")
+	}
+	details = strings.TrimSpace(details)
+	if len(details) > maxReportDetails {
+		return false, InvalidField("details", "report.details_too_long", maxReportDetails)
+	}
+
+	poll, err := s.Queries.GetPollByID(ctx, pollID)
+	if err != nil {
+		return false, err
+	}
+	if len(poll) == 0 || poll[0].Hidden || poll[0].Status != PollPublished {
+		return false, ErrPollNotFound
+	}
+	if poll[0].UserID == reporterID {
+		return false, Forbidden("report.own_poll")
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	created, err := qtx.CreateReport(ctx, db.CreateReportParams{
+		PollID:     pollID,
+		ReporterID: pgtype.Int4{Int32: reporterID, Valid: true},
+		Reason:     reason,
+		Details:    details,
+	})
+	if err != nil {
+		return false, err
+	}
+	if created == 0 {
+		return false, Conflict("report.duplicate")
+	}
+
+	hidden, err := s.autoHide(ctx, qtx, pollID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return hidden, nil
+}
+
+// flagPoll crea un reporte automático (sin usuario) cuando el filtro detecta un término
+// prohibido y, como un reporte más, puede ocultar la encuesta. Corre sobre q para quedar en la
+// misma transacción que guarda el contenido filtrado. Con s nil solo se crea el reporte.
+func (s *ReportService) flagPoll(ctx context.Context, q *db.Queries, pollID int32, term string) error {
+	_, err := q.CreateReport(ctx, db.CreateReportParams{
+		PollID:     pollID,
+		ReporterID: pgtype.Int4{},
+		Reason:     ReportReasonFiltered,
+		Details:    fmt.Sprintf("Término detectado: %s", term),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.autoHide(ctx, q, pollID)
+	return err
+}
+
+// autoHide oculta la encuesta si sus reportes abiertos llegaron a AutoHideThreshold y lo deja
+// en la auditoría. Devuelve true si la ocultó.
+func (s *ReportService) autoHide(ctx context.Context, q *db.Queries, pollID int32) (bool, error) {
+	if s == nil || s.AutoHideThreshold <= 0 {
+		return false, nil
+	}
+	open, err := q.CountOpenReportsByPoll(ctx, pollID)
+	if err != nil {
+		return false, err
+	}
+	if open < int64(s.AutoHideThreshold) {
+		return false, nil
+	}
+	if _, err := q.SetPollHidden(ctx, db.SetPollHiddenParams{Hidden: true, ID: pollID}); err != nil {
+		return false, err
+	}
+	// Sin actor: la ocultó el sistema
+	err = q.CreateAuditLogEntry(ctx, db.CreateAuditLogEntryParams{
+		ActorID:    pgtype.Int4{},
+		Action:     "poll.auto_hide",
+		TargetType: "poll",
+		TargetID:   pollID,
+		Details:    fmt.Sprintf("%d reportes abiertos", open),
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func isReportReason(reason string) bool {
+	for _, r := range ReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
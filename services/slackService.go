@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Headers con los que Slack firma cada petición.
+const (
+	SlackSignatureHeader = "X-Slack-Signature"
+	SlackTimestampHeader = "X-Slack-Request-Timestamp"
+)
+
+// SlackResponseURLPrefix es el único destino permitido para responder a una interacción.
+const SlackResponseURLPrefix = "https://hooks.slack.com/"
+
+const (
+	slackProvider = "slack"
+	// slackMaxSkew es la antigüedad máxima de una petición firmada (evita que se reenvíe una vieja)
+	slackMaxSkew = 5 * time.Minute
+	// slackLinkTTL es la validez del enlace para vincular la cuenta
+	slackLinkTTL = 15 * time.Minute
+	// slackVoteAction es el action_id de los botones de voto; el value lleva "<poll>:<opción>"
+	slackVoteAction = "vote"
+)
+
+// SlackService crea encuestas desde un slash command de Slack y registra los votos de sus
+// botones, a nombre del usuario de webpolls vinculado a cada usuario de Slack.
+type SlackService struct {
+	Queries *db.Queries
+	Polls   *PollService
+	// SigningSecret es el "Signing Secret" de la app de Slack
+	SigningSecret string
+	// BaseURL es la URL pública del sitio, para los enlaces de los mensajes
+	BaseURL string
+	// Now da la hora para validar firmas y enlaces; se puede fijar para reproducir peticiones grabadas
+	Now func() time.Time
+}
+
+// NewSlackService crea una nueva instancia de SlackService.
+func NewSlackService(queries *db.Queries, polls *PollService, signingSecret, baseURL string) *SlackService {
+	return &SlackService{
+		Queries:       queries,
+		Polls:         polls,
+		SigningSecret: signingSecret,
+		BaseURL:       strings.TrimSuffix(baseURL, "/"),
+		Now:           time.Now,
+	}
+}
+
+// SlashCommand son los campos del formulario que Slack envía al invocar el comando.
+type SlashCommand struct {
+	Command  string
+	Text     string
+	TeamID   string
+	UserID   string
+	UserName string
+}
+
+// SlackAction es el botón que tocó un usuario en un mensaje interactivo.
+type SlackAction struct {
+	TeamID      string
+	UserID      string
+	ActionID    string
+	Value       string
+	ResponseURL string
+}
+
+// SlackMessage es un mensaje de Slack con bloques (Block Kit).
+type SlackMessage struct {
+	ResponseType    string       `json:"response_type,omitempty"`
+	ReplaceOriginal bool         `json:"replace_original,omitempty"`
+	Text            string       `json:"text"`
+	Blocks          []SlackBlock `json:"blocks,omitempty"`
+}
+
+type SlackBlock struct {
+	Type     string         `json:"type"`
+	BlockID  string         `json:"block_id,omitempty"`
+	Text     *SlackText     `json:"text,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
+}
+
+// SlackElement es un botón (en un bloque actions, Text es un SlackText) o un texto (en un
+// bloque context, Text es un string).
+type SlackElement struct {
+	Type     string `json:"type"`
+	Text     any    `json:"text,omitempty"`
+	ActionID string `json:"action_id,omitempty"`
+	Value    string `json:"value,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Style    string `json:"style,omitempty"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// VerifySignature comprueba la firma v0 de Slack: HMAC-SHA256 de "v0:<timestamp>:<cuerpo>".
+func (s *SlackService) VerifySignature(timestamp, signature string, body []byte) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("timestamp de Slack inválido")
+	}
+	if skew := s.Now().Sub(time.Unix(ts, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return errors.New("la petición de Slack es demasiado vieja")
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.SigningSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("firma de Slack inválida")
+	}
+	return nil
+}
+
+// HandleCommand crea la encuesta de `/poll "Pregunta" "Opción 1" "Opción 2"` y devuelve el
+// mensaje interactivo para publicar en el canal.
+func (s *SlackService) HandleCommand(ctx context.Context, cmd SlashCommand) (*SlackMessage, error) {
+	ctx, span := tracer.Start(ctx, "SlackService.HandleCommand")
+	defer span.End()
+
+	ctx, userID, linked, err := s.resolveUser(ctx, cmd.TeamID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	args := ParseCommandArgs(cmd.Text)
+	if len(args) == 0 || (len(args) == 1 && strings.EqualFold(args[0], "help")) {
+		return ephemeral(i18n.T(ctx, "slack.usage", cmd.Command)), nil
+	}
+	if !linked {
+		return s.linkPrompt(ctx, cmd.TeamID, cmd.UserID), nil
+	}
+
+	var options []OptionRequest
+	for _, option := range args[1:] {
+		options = append(options, OptionRequest{Content: option})
+	}
+	poll, err := s.Polls.CreatePoll(ctx, PollRequest{Question: args[0], UserID: userID, Options: options})
+	if err != nil {
+		return slackError(ctx, "slack.create_failed", err), nil
+	}
+	return s.pollMessage(ctx, poll.ID)
+}
+
+// HandleAction registra el voto de un botón y devuelve el mensaje actualizado, que reemplaza
+// al original. Si el usuario no está vinculado o el voto falla, devuelve un mensaje efímero.
+func (s *SlackService) HandleAction(ctx context.Context, action SlackAction) (*SlackMessage, error) {
+	ctx, span := tracer.Start(ctx, "SlackService.HandleAction")
+	defer span.End()
+
+	if action.ActionID != slackVoteAction {
+		return nil, InvalidField("action_id", "slack.unknown_action", action.ActionID)
+	}
+	pollPart, optionPart, _ := strings.Cut(action.Value, ":")
+	pollID, err := strconv.ParseInt(pollPart, 10, 32)
+	if err != nil {
+		return nil, InvalidField("value", "slack.invalid_vote")
+	}
+	optionID, err := strconv.ParseInt(optionPart, 10, 32)
+	if err != nil {
+		return nil, InvalidField("value", "slack.invalid_vote")
+	}
+
+	ctx, userID, linked, err := s.resolveUser(ctx, action.TeamID, action.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return s.linkPrompt(ctx, action.TeamID, action.UserID), nil
+	}
+
+	if err := s.Polls.Vote(ctx, int32(pollID), int32(optionID), userID); err != nil {
+		return slackError(ctx, "slack.vote_failed", err), nil
+	}
+	msg, err := s.pollMessage(ctx, int32(pollID))
+	if err != nil {
+		return nil, err
+	}
+	msg.ReplaceOriginal = true
+	return msg, nil
+}
+
+// LinkToken firma el usuario de Slack para el enlace de vinculación.
+func (s *SlackService) LinkToken(teamID, externalUserID string) string {
+	payload := fmt.Sprintf("%s:%s:%d", teamID, externalUserID, s.Now().Add(slackLinkTTL).Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.sign(encoded)
+}
+
+// ParseLinkToken valida un enlace de vinculación y devuelve el equipo y el usuario de Slack.
+func (s *SlackService) ParseLinkToken(token string) (teamID, externalUserID string, err error) {
+	invalid := Invalid("slack.invalid_link")
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return "", "", invalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", invalid
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return "", "", invalid
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || s.Now().Unix() > expires {
+		return "", "", invalid
+	}
+	return parts[0], parts[1], nil
+}
+
+// LinkAccount vincula el usuario de Slack del enlace con la cuenta de webpolls.
+func (s *SlackService) LinkAccount(ctx context.Context, userID int32, token string) error {
+	ctx, span := tracer.Start(ctx, "SlackService.LinkAccount")
+	defer span.End()
+
+	teamID, externalUserID, err := s.ParseLinkToken(token)
+	if err != nil {
+		return err
+	}
+	return s.Queries.LinkExternalAccount(ctx, db.LinkExternalAccountParams{
+		Provider:       slackProvider,
+		TeamID:         teamID,
+		ExternalUserID: externalUserID,
+		UserID:         userID,
+	})
+}
+
+// resolveUser busca la cuenta de webpolls vinculada al usuario de Slack. El contexto que
+// devuelve tiene el idioma de esa cuenta, o el idioma por defecto si no está vinculada o no
+// eligió uno: el Accept-Language de la petición es el de los servidores de Slack.
+func (s *SlackService) resolveUser(ctx context.Context, teamID, externalUserID string) (context.Context, int32, bool, error) {
+	account, err := s.Queries.GetExternalAccountUser(ctx, db.GetExternalAccountUserParams{
+		Provider:       slackProvider,
+		TeamID:         teamID,
+		ExternalUserID: externalUserID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return i18n.WithLocale(ctx, i18n.Default), 0, false, nil
+	}
+	if err != nil {
+		return ctx, 0, false, err
+	}
+	locale := i18n.Default
+	if i18n.IsSupported(account.Locale.String) {
+		locale = account.Locale.String
+	}
+	return i18n.WithLocale(ctx, locale), account.UserID, true, nil
+}
+
+// pollMessage arma el mensaje con un botón por opción. Los conteos se muestran como los
+// vería un anónimo, según la visibilidad de resultados de la encuesta.
+func (s *SlackService) pollMessage(ctx context.Context, pollID int32) (*SlackMessage, error) {
+	poll, err := s.Polls.GetPollByID(ctx, pollID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var buttons []SlackElement
+	for _, option := range poll.Options {
+		label := option.Content
+		if poll.ResultsVisible {
+			label = fmt.Sprintf("%s (%d)", option.Content, option.VoteCount)
+		}
+		buttons = append(buttons, SlackElement{
+			Type:     "button",
+			Text:     SlackText{Type: "plain_text", Text: label},
+			ActionID: slackVoteAction,
+			Value:    fmt.Sprintf("%d:%d", poll.ID, option.ID),
+		})
+	}
+
+	footer := fmt.Sprintf("<%s/polls/%d|%s>", s.BaseURL, poll.ID, i18n.T(ctx, "slack.view_poll"))
+	if poll.ResultsVisible {
+		footer = i18n.T(ctx, "slack.votes", poll.TotalVotes) + " · " + footer
+	}
+
+	return &SlackMessage{
+		ResponseType: "in_channel",
+		Text:         poll.Title,
+		Blocks: []SlackBlock{
+			{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: "*" + escapeSlack(poll.Title) + "*"}},
+			{Type: "actions", BlockID: fmt.Sprintf("poll_%d", poll.ID), Elements: buttons},
+			{Type: "context", Elements: []SlackElement{{Type: "mrkdwn", Text: footer}}},
+		},
+	}, nil
+}
+
+// linkPrompt pide al usuario de Slack que vincule su cuenta antes de usar el comando.
+func (s *SlackService) linkPrompt(ctx context.Context, teamID, externalUserID string) *SlackMessage {
+	link := fmt.Sprintf("%s/integrations/slack/link?token=%s", s.BaseURL, s.LinkToken(teamID, externalUserID))
+	msg := ephemeral(i18n.T(ctx, "slack.link_prompt"))
+	msg.Blocks = []SlackBlock{
+		{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: msg.Text}},
+		{Type: "actions", Elements: []SlackElement{{
+			Type:  "button",
+			Text:  SlackText{Type: "plain_text", Text: i18n.T(ctx, "slack.link_submit")},
+			URL:   link,
+			Style: "primary",
+		}}},
+	}
+	return msg
+}
+
+func (s *SlackService) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.SigningSecret))
+	mac.Write([]byte("link:" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseCommandArgs separa el texto del comando en argumentos. Los argumentos van entre
+// comillas rectas o tipográficas (Slack suele convertirlas); sin comillas, cada palabra es uno.
+func ParseCommandArgs(text string) []string {
+	var args []string
+	var current strings.Builder
+	inQuotes, started := false, false
+	flush := func() {
+		if started {
+			args = append(args, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		started = false
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			if inQuotes {
+				flush()
+			} else {
+				flush()
+				started = true
+			}
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	flush()
+
+	// Comillas vacías ("") no cuentan como argumento
+	filtered := args[:0]
+	for _, arg := range args {
+		if arg != "" {
+			filtered = append(filtered, arg)
+		}
+	}
+	return filtered
+}
+
+func ephemeral(text string) *SlackMessage {
+	return &SlackMessage{ResponseType: "ephemeral", Text: text}
+}
+
+// slackError arma el mensaje efímero con el texto de key. Los errores de dominio se traducen;
+// el resto solo va al log, para no mostrarle detalles internos al usuario.
+func slackError(ctx context.Context, key string, err error) *SlackMessage {
+	var domain *DomainError
+	if errors.As(err, &domain) {
+		return ephemeral(i18n.T(ctx, key, i18n.Message(ctx, domain)))
+	}
+	slog.ErrorContext(ctx, "slack request failed", "error", err)
+	return ephemeral(i18n.T(ctx, key, i18n.T(ctx, "error.internal")))
+}
+
+// escapeSlack escapa los caracteres de control del formato mrkdwn.
+func escapeSlack(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	db "webpolls/db/sqlc"
+	"webpolls/i18n"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestSlackVerifySignature(t *testing.T) {
+	// Ejemplo de la documentación de Slack ("Verifying requests from Slack")
+	const (
+		secret    = "8f742231b10e8888abcd99yyyzzz85a5"
+		timestamp = "1531420618"
+		body      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+		signature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+	)
+	sent := time.Unix(1531420618, 0)
+	s := &SlackService{SigningSecret: secret}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		timestamp string
+		signature string
+		body      string
+		ok        bool
+	}{
+		{"firma válida", sent.Add(time.Minute), timestamp, signature, body, true},
+		{"reloj algo atrasado", sent.Add(-time.Minute), timestamp, signature, body, true},
+		{"petición vieja", sent.Add(6 * time.Minute), timestamp, signature, body, false},
+		{"petición del futuro", sent.Add(-6 * time.Minute), timestamp, signature, body, false},
+		{"timestamp inválido", sent, "ayer", signature, body, false},
+		{"cuerpo alterado", sent, timestamp, signature, body + "&x=1", false},
+		{"timestamp alterado", sent, "1531420619", signature, body, false},
+		{"sin firma", sent, timestamp, "", body, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.Now = func() time.Time { return tt.now }
+			err := s.VerifySignature(tt.timestamp, tt.signature, []byte(tt.body))
+			if (err == nil) != tt.ok {
+				t.Errorf("VerifySignature = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseCommandArgs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{``, nil},
+		{`help`, []string{"help"}},
+		{`"¿Pizza o empanadas?" "Pizza" "Empanadas"`, []string{"¿Pizza o empanadas?", "Pizza", "Empanadas"}},
+		{`“¿Comillas tipográficas?” “Sí” “No”`, []string{"¿Comillas tipográficas?", "Sí", "No"}},
+		{`sin comillas cada palabra`, []string{"sin", "comillas", "cada", "palabra"}},
+		{`"  con espacios  " "" "b"`, []string{"con espacios", "b"}},
+		{`"mezcla" suelta`, []string{"mezcla", "suelta"}},
+		{`"sin cerrar`, []string{"sin cerrar"}},
+	}
+	for _, tt := range tests {
+		got := ParseCommandArgs(tt.text)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCommandArgs(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSlackLinkToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := &SlackService{SigningSecret: "secreto", Now: func() time.Time { return now }}
+	token := s.LinkToken("T1", "U2")
+
+	team, user, err := s.ParseLinkToken(token)
+	if err != nil || team != "T1" || user != "U2" {
+		t.Fatalf("ParseLinkToken = (%q, %q, %v), want (T1, U2, nil)", team, user, err)
+	}
+
+	other := &SlackService{SigningSecret: "otro", Now: s.Now}
+	if _, _, err := other.ParseLinkToken(token); err == nil {
+		t.Error("se aceptó un enlace firmado con otro secreto")
+	}
+	if _, _, err := s.ParseLinkToken(token + "x"); err == nil {
+		t.Error("se aceptó un enlace alterado")
+	}
+	s.Now = func() time.Time { return now.Add(slackLinkTTL + time.Second) }
+	if _, _, err := s.ParseLinkToken(token); err == nil {
+		t.Error("se aceptó un enlace vencido")
+	}
+}
+
+// Las respuestas salen en el idioma de la cuenta vinculada, o en el idioma por defecto.
+func TestSlackCommandLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		account []any
+		text    string
+		want    string
+	}{
+		{"ayuda sin vincular", nil, "help", i18n.Translate(i18n.Default, "slack.usage", "/poll")},
+		{"vincular", nil, `"¿Almuerzo?" "Pizza" "Sushi"`, i18n.Translate(i18n.Default, "slack.link_prompt")},
+		{"ayuda en inglés", []any{db.GetExternalAccountUserRow{UserID: 7, Locale: pgtype.Text{String: i18n.EN, Valid: true}}}, "help", "Usage: `/poll"},
+		{"ayuda sin idioma elegido", []any{db.GetExternalAccountUserRow{UserID: 7}}, "help", i18n.Translate(i18n.Default, "slack.usage", "/poll")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SlackService{Queries: db.New(newFakeDB().add("GetExternalAccountUser", tt.account...)), Now: time.Now}
+			// El idioma de la petición es el de los servidores de Slack y no cuenta
+			ctx := i18n.WithLocale(context.Background(), i18n.EN)
+			msg, err := s.HandleCommand(ctx, SlashCommand{Command: "/poll", Text: tt.text, TeamID: "T1", UserID: "U2"})
+			if err != nil {
+				t.Fatalf("HandleCommand: %v", err)
+			}
+			if !strings.HasPrefix(msg.Text, tt.want) {
+				t.Errorf("text = %q, want %q", msg.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlackActionInvalidValue(t *testing.T) {
+	s := &SlackService{}
+	for _, value := range []string{"", "1", "x:2", "1:y"} {
+		_, err := s.HandleAction(context.Background(), SlackAction{ActionID: slackVoteAction, Value: value})
+		if !errors.Is(err, InvalidField("value", "slack.invalid_vote")) {
+			t.Errorf("HandleAction(%q) = %v, want slack.invalid_vote", value, err)
+		}
+	}
+	_, err := s.HandleAction(context.Background(), SlackAction{ActionID: "otra"})
+	if !errors.Is(err, InvalidField("action_id", "slack.unknown_action", "otra")) {
+		t.Errorf("HandleAction(otra) = %v, want slack.unknown_action", err)
+	}
+}
@@ -1,23 +1,52 @@
 package services
 
 import (
-	"log"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type SSEBroker struct {
-	Notifier       chan []byte
-	newClients     chan chan []byte
+	Notifier       chan sseMessage
+	newClients     chan sseClient
 	closingClients chan chan []byte
-	clients        map[chan []byte]bool
+	// clients guarda el usuario de cada stream (0 = anónimo)
+	clients  map[chan []byte]int32
+	shutdown chan struct{}
+	closed   bool
+}
+
+// sseClient es un stream recién conectado y el usuario con sesión que lo abrió.
+type sseClient struct {
+	messages chan []byte
+	userID   int32
 }
 
+// sseMessage es un evento ya armado. Con userID distinto de 0 solo lo reciben los streams
+// de ese usuario.
+type sseMessage struct {
+	data   []byte
+	userID int32
+}
+
+// sseReconnectHint se envía al cerrar el stream durante un apagado: los clientes reintentan
+// a los pocos segundos y caen en otra réplica.
+const sseReconnectHint = "retry: 3000\nevent: reconnect\ndata: {}\n\n"
+
 func NewSSEBroker() *SSEBroker {
 	broker := &SSEBroker{
-		Notifier:       make(chan []byte, 1),
-		newClients:     make(chan chan []byte),
+		Notifier:       make(chan sseMessage, 1),
+		newClients:     make(chan sseClient),
 		closingClients: make(chan chan []byte),
-		clients:        make(map[chan []byte]bool),
+		clients:        make(map[chan []byte]int32),
+		shutdown:       make(chan struct{}),
 	}
 	go broker.listen()
 	return broker
@@ -26,20 +55,38 @@ func NewSSEBroker() *SSEBroker {
 func (broker *SSEBroker) listen() {
 	for {
 		select {
-		case s := <-broker.newClients:
-			broker.clients[s] = true
-			log.Printf("Client added. %d registered clients", len(broker.clients))
+		case c := <-broker.newClients:
+			if broker.closed {
+				close(c.messages)
+				continue
+			}
+			broker.clients[c.messages] = c.userID
+			sseClients.Set(float64(len(broker.clients)))
+			slog.Debug("SSE client added", "clients", len(broker.clients))
+		case <-broker.shutdown:
+			// Cerrar los canales termina cada ServeHTTP con el aviso de reconexión
+			broker.closed = true
+			for s := range broker.clients {
+				close(s)
+				delete(broker.clients, s)
+			}
+			sseClients.Set(0)
 		case s := <-broker.closingClients:
 			delete(broker.clients, s)
-			log.Printf("Removed client. %d registered clients", len(broker.clients))
+			sseClients.Set(float64(len(broker.clients)))
+			slog.Debug("SSE client removed", "clients", len(broker.clients))
 		case event := <-broker.Notifier:
-			for clientMessageChan := range broker.clients {
+			for clientMessageChan, userID := range broker.clients {
+				if event.userID != 0 && event.userID != userID {
+					continue
+				}
 				select {
-				case clientMessageChan <- event:
+				case clientMessageChan <- event.data:
 				default:
 					// Si el cliente está lento y el canal está lleno, saltamos este mensaje
 					// para no bloquear a los demás clientes.
-					log.Println("Skipping message for slow client")
+					sseDroppedMessages.Inc()
+					slog.Warn("skipping SSE message for slow client")
 				}
 			}
 		}
@@ -47,6 +94,12 @@ func (broker *SSEBroker) listen() {
 }
 
 func (broker *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	broker.ServeUser(w, r, 0)
+}
+
+// ServeUser abre el stream de un usuario con sesión: además de los eventos públicos recibe
+// los que se le envían con PublishToUser.
+func (broker *SSEBroker) ServeUser(w http.ResponseWriter, r *http.Request, userID int32) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
@@ -60,7 +113,7 @@ func (broker *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	messageChan := make(chan []byte)
-	broker.newClients <- messageChan
+	broker.newClients <- sseClient{messages: messageChan, userID: userID}
 
 	defer func() {
 		broker.closingClients <- messageChan
@@ -68,21 +121,55 @@ func (broker *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	notify := r.Context().Done()
 
-	go func() {
-		<-notify
-		broker.closingClients <- messageChan
-	}()
-
 	for {
-		msg, open := <-messageChan
-		if !open {
-			break
+		select {
+		case <-notify:
+			// El cliente se desconectó; el defer lo da de baja
+			return
+		case msg, open := <-messageChan:
+			if !open {
+				w.Write([]byte(sseReconnectHint))
+				flusher.Flush()
+				return
+			}
+			w.Write(msg)
+			flusher.Flush()
 		}
-		w.Write(msg)
-		flusher.Flush()
 	}
 }
 
+// Shutdown cierra todos los streams abiertos enviándoles un aviso de reconexión.
+// Los clientes que se conecten después reciben el aviso de inmediato.
+func (broker *SSEBroker) Shutdown() {
+	broker.shutdown <- struct{}{}
+}
+
 func (broker *SSEBroker) Broadcast(msg []byte) {
-	broker.Notifier <- msg
+	broker.Notifier <- sseMessage{data: msg}
+}
+
+// Publish envía un evento a todos los clientes. El contexto de traza viaja en líneas de comentario
+// del evento (": traceparent ...") para poder correlacionar lo que reciben los clientes con el voto.
+func (broker *SSEBroker) Publish(ctx context.Context, event string, data []byte) {
+	broker.publish(ctx, 0, event, data)
+}
+
+// PublishToUser envía un evento solo a los streams abiertos por userID.
+func (broker *SSEBroker) PublishToUser(ctx context.Context, userID int32, event string, data []byte) {
+	broker.publish(ctx, userID, event, data)
+}
+
+func (broker *SSEBroker) publish(ctx context.Context, userID int32, event string, data []byte) {
+	ctx, span := tracer.Start(ctx, "SSEBroker.Publish", trace.WithAttributes(attribute.String("sse.event", event)))
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	var msg bytes.Buffer
+	for _, key := range carrier.Keys() {
+		fmt.Fprintf(&msg, ": %s %s\n", key, carrier.Get(key))
+	}
+	fmt.Fprintf(&msg, "event: %s\ndata: %s\n\n", event, data)
+	broker.Notifier <- sseMessage{data: msg.Bytes(), userID: userID}
 }
@@ -0,0 +1,6 @@
+package services
+
+import "go.opentelemetry.io/otel"
+
+// tracer abre un span por cada método público de los servicios.
+var tracer = otel.Tracer("webpolls/services")
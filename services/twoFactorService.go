@@ -0,0 +1,352 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"image/png"
+	"net/url"
+	"strings"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	totpIssuer = "WebPolls"
+	// totpPeriod es la duración de cada paso de los códigos TOTP, en segundos.
+	totpPeriod         = 30
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// Alfabeto sin caracteres ambiguos (0/O, 1/I/L) para que los códigos se puedan copiar a mano.
+	recoveryCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+	// PendingTwoFactorTTL es el tiempo que tiene el usuario para ingresar el código tras la contraseña.
+	PendingTwoFactorTTL = 5 * time.Minute
+)
+
+// TwoFactorService maneja el segundo factor (TOTP) y los códigos de recuperación.
+type TwoFactorService struct {
+	Queries *db.Queries
+	DB      *pgxpool.Pool
+}
+
+// NewTwoFactorService crea una nueva instancia de TwoFactorService.
+func NewTwoFactorService(queries *db.Queries, db *pgxpool.Pool) *TwoFactorService {
+	return &TwoFactorService{Queries: queries, DB: db}
+}
+
+// TOTPEnrollment es un secreto TOTP todavía no confirmado, listo para mostrar al usuario.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+	// QRCode es una imagen PNG en formato data URI, generada en el servidor.
+	QRCode string `json:"qr_code"`
+}
+
+// TwoFactorStatus resume el estado del segundo factor de un usuario.
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
+
+// BeginEnrollment genera un secreto TOTP nuevo para el usuario. No se guarda hasta
+// que el usuario lo confirma con un código en Enable.
+func (s *TwoFactorService) BeginEnrollment(ctx context.Context, accountName string) (*TOTPEnrollment, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorService.BeginEnrollment")
+	defer span.End()
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: accountName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return enrollmentFromKey(key)
+}
+
+// Enrollment reconstruye la inscripción a partir de un secreto pendiente (p. ej. guardado en sesión).
+func (s *TwoFactorService) Enrollment(accountName, secret string) (*TOTPEnrollment, error) {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", totpIssuer)
+	params.Set("period", "30")
+	params.Set("digits", "6")
+	params.Set("algorithm", "SHA1")
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + accountName,
+		RawQuery: params.Encode(),
+	}
+
+	key, err := otp.NewKeyFromURL(u.String())
+	if err != nil {
+		return nil, err
+	}
+	return enrollmentFromKey(key)
+}
+
+func enrollmentFromKey(key *otp.Key) (*TOTPEnrollment, error) {
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: key.Secret(),
+		URL:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// Enable confirma la inscripción validando un código del secreto pendiente, activa el
+// segundo factor y devuelve los códigos de recuperación en claro (solo se muestran una vez).
+func (s *TwoFactorService) Enable(ctx context.Context, userID int32, secret, code string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorService.Enable")
+	defer span.End()
+
+	step, ok := totpStep(normalizeCode(code), secret, time.Now())
+	if !ok {
+		return nil, InvalidField("code", "twofactor.invalid_code")
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	err = qtx.SetUserTOTP(ctx, db.SetUserTOTPParams{
+		TotpSecret:  pgtype.Text{String: secret, Valid: true},
+		TotpEnabled: true,
+		// El código de la confirmación no sirve para iniciar sesión
+		TotpLastStep: pgtype.Int8{Int64: step, Valid: true},
+		ID:           userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := replaceRecoveryCodes(ctx, qtx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify valida el segundo paso del login: acepta un código TOTP o un código de recuperación,
+// que queda consumido. Un código TOTP tampoco se acepta dos veces: se guarda su paso de
+// tiempo y se rechazan los de ese paso o anteriores.
+func (s *TwoFactorService) Verify(ctx context.Context, userID int32, code string) error {
+	ctx, span := tracer.Start(ctx, "TwoFactorService.Verify")
+	defer span.End()
+
+	user, err := s.Queries.GetUserAuthByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TotpEnabled || !user.TotpSecret.Valid {
+		return Conflict("twofactor.not_enabled")
+	}
+
+	code = normalizeCode(code)
+	if step, ok := totpStep(code, user.TotpSecret.String, time.Now()); ok {
+		accepted, err := s.Queries.AcceptTOTPStep(ctx, db.AcceptTOTPStepParams{Step: step, ID: userID})
+		if err != nil {
+			return err
+		}
+		if accepted == 0 {
+			return Unauthorized("twofactor.code_reused")
+		}
+		return nil
+	}
+
+	_, err = s.Queries.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hashRecoveryCode(code),
+	})
+	if err == pgx.ErrNoRows {
+		return Unauthorized("twofactor.invalid_code")
+	}
+	return err
+}
+
+// Disable desactiva el segundo factor. Exige la contraseña actual como re-autenticación.
+func (s *TwoFactorService) Disable(ctx context.Context, userID int32, password string) error {
+	ctx, span := tracer.Start(ctx, "TwoFactorService.Disable")
+	defer span.End()
+
+	if err := s.checkPassword(ctx, userID, password); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.Queries.WithTx(tx)
+
+	err = qtx.SetUserTOTP(ctx, db.SetUserTOTPParams{
+		TotpSecret:  pgtype.Text{},
+		TotpEnabled: false,
+		ID:          userID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RegenerateRecoveryCodes invalida los códigos anteriores y genera otros nuevos.
+// Exige la contraseña actual como re-autenticación.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int32, password string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorService.RegenerateRecoveryCodes")
+	defer span.End()
+
+	if err := s.checkPassword(ctx, userID, password); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	codes, err := replaceRecoveryCodes(ctx, s.Queries.WithTx(tx), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Status devuelve si el usuario tiene activado el segundo factor y cuántos códigos de recuperación le quedan.
+func (s *TwoFactorService) Status(ctx context.Context, userID int32) (*TwoFactorStatus, error) {
+	ctx, span := tracer.Start(ctx, "TwoFactorService.Status")
+	defer span.End()
+
+	user, err := s.Queries.GetUserAuthByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{Enabled: user.TotpEnabled}
+	if user.TotpEnabled {
+		status.RemainingRecoveryCodes, err = s.Queries.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *TwoFactorService) checkPassword(ctx context.Context, userID int32, password string) error {
+	user, err := s.Queries.GetUserAuthByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return InvalidField("password", "auth.wrong_password")
+	}
+	if !user.TotpEnabled {
+		return Conflict("twofactor.not_enabled")
+	}
+	return nil
+}
+
+// totpStep devuelve el paso de tiempo al que corresponde code, con un paso de tolerancia
+// para cada lado (como totp.Validate). ok es false si no coincide con ninguno.
+func totpStep(code, secret string, now time.Time) (step int64, ok bool) {
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current - 1, current, current + 1} {
+		valid, err := totp.ValidateCustom(code, secret, time.Unix(step*totpPeriod, 0).UTC(), opts)
+		if err == nil && valid {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func replaceRecoveryCodes(ctx context.Context, qtx *db.Queries, userID int32) ([]string, error) {
+	if err := qtx.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		err = qtx.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, formatRecoveryCode(code))
+	}
+	return codes, nil
+}
+
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = recoveryCodeAlphabet[int(b[i])%len(recoveryCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// formatRecoveryCode separa el código en dos mitades (XXXXX-XXXXX) para que sea más legible.
+func formatRecoveryCode(code string) string {
+	return code[:len(code)/2] + "-" + code[len(code)/2:]
+}
+
+// normalizeCode quita espacios y guiones y pasa a mayúsculas, así el usuario puede
+// escribir los códigos como los ve.
+func normalizeCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// hashRecoveryCode guarda solo el SHA-256: los códigos son aleatorios y de alta entropía,
+// por lo que no hace falta un hash lento como bcrypt.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestTOTPStep(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	now := time.Unix(1700000000, 0)
+	current := now.Unix() / totpPeriod
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	codeAt := func(step int64) string {
+		code, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name string
+		step int64
+		ok   bool
+	}{
+		{"paso actual", current, true},
+		{"paso anterior", current - 1, true},
+		{"paso siguiente", current + 1, true},
+		{"fuera de la ventana", current - 2, false},
+		{"muy adelantado", current + 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := totpStep(codeAt(tt.step), secret, now)
+			if ok != tt.ok || (ok && step != tt.step) {
+				t.Errorf("totpStep = (%d, %v), want (%d, %v)", step, ok, tt.step, tt.ok)
+			}
+		})
+	}
+
+	if _, ok := totpStep("000000x", secret, now); ok {
+		t.Error("totpStep aceptó un código mal formado")
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	for in, want := range map[string]string{
+		" abcde-fghjk ": "ABCDEFGHJK",
+		"123 456":       "123456",
+	} {
+		if got := normalizeCode(in); got != want {
+			t.Errorf("normalizeCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRecoveryCode(t *testing.T) {
+	code, err := newRecoveryCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != recoveryCodeLength {
+		t.Fatalf("largo %d, want %d", len(code), recoveryCodeLength)
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(recoveryCodeAlphabet, c) {
+			t.Fatalf("el código %q tiene un carácter fuera del alfabeto", code)
+		}
+	}
+
+	// Lo que ve el usuario vuelve al mismo hash aunque lo escriba en minúsculas
+	shown := formatRecoveryCode(code)
+	if hashRecoveryCode(normalizeCode(strings.ToLower(shown))) != hashRecoveryCode(code) {
+		t.Errorf("el código mostrado %q no coincide con el guardado", shown)
+	}
+}
+
+func TestEnrollment(t *testing.T) {
+	enrollment, err := (&TwoFactorService{}).Enrollment("ana@example.com", "JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enrollment.URL, "otpauth://totp/WebPolls:ana@example.com?") || !strings.Contains(enrollment.URL, "secret=JBSWY3DPEHPK3PXP") {
+		t.Errorf("URL = %q", enrollment.URL)
+	}
+	if !strings.HasPrefix(enrollment.QRCode, "data:image/png;base64,") {
+		t.Errorf("QRCode no es un PNG en data URI: %.40q", enrollment.QRCode)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	db "webpolls/db/sqlc"
+	"webpolls/i18n"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -14,10 +15,32 @@ type UserService struct {
 	Queries *db.Queries
 }
 
+// Roles de usuario. Los moderadores y administradores tienen acceso al panel /admin.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// ErrAccountSuspended se devuelve al iniciar sesión con una cuenta suspendida.
+var ErrAccountSuspended = Forbidden("auth.account_suspended")
+
+// ErrUserNotFound se devuelve al actualizar un usuario que no existe.
+var ErrUserNotFound = NotFound("user.not_found")
+
+// UserStatus es el estado de la cuenta que se revisa en cada petición autenticada.
+type UserStatus struct {
+	Role      string
+	Suspended bool
+}
+
 type UserResponse struct {
-	Id       int32  `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	Id               int32  `json:"id"`
+	Username         string `json:"username"`
+	Email            string `json:"email"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	// Locale es el idioma preferido; vacío si el usuario no eligió uno
+	Locale string `json:"locale,omitempty"`
 }
 
 type UserRequest = db.CreateUserParams
@@ -26,18 +49,31 @@ func NewUserService(queries *db.Queries) *UserService {
 	return &UserService{Queries: queries}
 }
 func (s *UserService) CreateUser(ctx context.Context, params UserRequest) (*UserResponse, error) {
-	if params.Username == "" || params.Email == "" || params.Password == "" {
-		return nil, errors.New("Todos los campos son obligatorios")
+	ctx, span := tracer.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
+	var v validation
+	for _, field := range []struct{ name, value string }{
+		{"username", params.Username},
+		{"email", params.Email},
+		{"password", params.Password},
+	} {
+		if field.value == "" {
+			v.add(field.name, "user.fields_required")
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
 	}
 
 	_, err := s.Queries.GetUserByUsername(ctx, params.Username)
 	if err == nil {
-		return nil, errors.New("El nombre de usuario ya existe")
+		return nil, Conflict("user.username_taken")
 	}
 
 	_, err = s.Queries.GetUserByEmail(ctx, params.Email)
 	if err == nil {
-		return nil, errors.New("El email ya existe")
+		return nil, Conflict("user.email_taken")
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
@@ -60,40 +96,85 @@ func (s *UserService) CreateUser(ctx context.Context, params UserRequest) (*User
 }
 
 func (s *UserService) Authenticate(ctx context.Context, email, password string) (*UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Authenticate")
+	defer span.End()
+
 	user, err := s.Queries.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("credenciales inválidas")
+			return nil, Unauthorized("auth.invalid_credentials")
 		}
 		return nil, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, errors.New("credenciales inválidas")
+		return nil, Unauthorized("auth.invalid_credentials")
+	}
+
+	if user.SuspendedAt.Valid {
+		return nil, ErrAccountSuspended
 	}
 
 	return &UserResponse{
-		Id:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
+		Id:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		TwoFactorEnabled: user.TotpEnabled,
+		Locale:           user.Locale.String,
 	}, nil
 }
 
 func (s *UserService) GetUserByID(ctx context.Context, id int32) (*UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUserByID")
+	defer span.End()
+
 	userRow, err := s.Queries.GetUserByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &UserResponse{
-		Id:       userRow.ID,
-		Username: userRow.Username,
-		Email:    userRow.Email,
+		Id:               userRow.ID,
+		Username:         userRow.Username,
+		Email:            userRow.Email,
+		TwoFactorEnabled: userRow.TotpEnabled,
+		Locale:           userRow.Locale.String,
 	}, nil
 }
 
+// GetUserStatus devuelve el rol actual del usuario y si está suspendido.
+func (s *UserService) GetUserStatus(ctx context.Context, id int32) (*UserStatus, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUserStatus")
+	defer span.End()
+
+	status, err := s.Queries.GetUserStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &UserStatus{Role: status.Role, Suspended: status.SuspendedAt.Valid}, nil
+}
+
+// EnsureAdmin da el rol de administrador a la cuenta con ese email, si existe.
+// Se usa al arrancar para crear el primer administrador (ADMIN_EMAIL).
+func (s *UserService) EnsureAdmin(ctx context.Context, email string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "UserService.EnsureAdmin")
+	defer span.End()
+
+	rows, err := s.Queries.PromoteUserToAdminByEmail(ctx, email)
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
 func (s *UserService) DeleteUser(ctx context.Context, id int32) (string, error) {
+	ctx, span := tracer.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
+
 	return s.Queries.DeleteUser(ctx, id)
 }
 
@@ -104,9 +185,12 @@ type UpdateUserRequest struct {
 }
 
 func (s *UserService) UpdateUser(ctx context.Context, id int32, params UpdateUserRequest) (*UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+
 	actualUser, err := s.GetUserByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("usuario no encontrado")
+		return nil, ErrUserNotFound
 	}
 
 	var username, email, password pgtype.Text
@@ -114,7 +198,7 @@ func (s *UserService) UpdateUser(ctx context.Context, id int32, params UpdateUse
 	if params.Username != nil && *params.Username != actualUser.Username {
 		userByUsername, err := s.Queries.GetUserByUsername(ctx, *params.Username)
 		if err == nil && userByUsername.ID != id {
-			return nil, errors.New("el nombre de usuario ya existe")
+			return nil, Conflict("user.username_taken")
 		}
 		username = pgtype.Text{String: *params.Username, Valid: true}
 	}
@@ -122,7 +206,7 @@ func (s *UserService) UpdateUser(ctx context.Context, id int32, params UpdateUse
 	if params.Email != nil && *params.Email != actualUser.Email {
 		userByEmail, err := s.Queries.GetUserByEmail(ctx, *params.Email)
 		if err == nil && userByEmail.ID != id {
-			return nil, errors.New("el email ya existe")
+			return nil, Conflict("user.email_taken")
 		}
 		email = pgtype.Text{String: *params.Email, Valid: true}
 	}
@@ -149,6 +233,9 @@ func (s *UserService) UpdateUser(ctx context.Context, id int32, params UpdateUse
 }
 
 func (s *UserService) GetUsers(ctx context.Context) ([]UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUsers")
+	defer span.End()
+
 	users, err := s.Queries.GetAllUsers(ctx)
 	if err != nil {
 		return nil, err
@@ -165,3 +252,17 @@ func (s *UserService) GetUsers(ctx context.Context) ([]UserResponse, error) {
 
 	return userResponses, nil
 }
+
+// SetLocale guarda el idioma preferido del usuario.
+func (s *UserService) SetLocale(ctx context.Context, id int32, locale string) error {
+	ctx, span := tracer.Start(ctx, "UserService.SetLocale")
+	defer span.End()
+
+	if !i18n.IsSupported(locale) {
+		return InvalidField("locale", "locale.unsupported", locale)
+	}
+	return s.Queries.SetUserLocale(ctx, db.SetUserLocaleParams{
+		Locale: pgtype.Text{String: locale, Valid: true},
+		ID:     id,
+	})
+}
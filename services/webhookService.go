@@ -0,0 +1,449 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	db "webpolls/db/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Eventos de encuesta que se pueden suscribir.
+const (
+	EventPollCreated = "poll.created"
+	EventPollVoted   = "poll.voted"
+	EventPollClosed  = "poll.closed"
+	EventPollDeleted = "poll.deleted"
+	// EventPing lo manda solo el botón de prueba, sin importar los filtros
+	EventPing = "ping"
+)
+
+// WebhookEvents son los eventos que se pueden elegir al crear un webhook.
+var WebhookEvents = []string{EventPollCreated, EventPollVoted, EventPollClosed, EventPollDeleted}
+
+// Estados de una entrega.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Headers de cada entrega. La firma es "sha256=" + HMAC-SHA256 de "<timestamp>.<cuerpo>" con el
+// secreto del webhook, en hexadecimal; el receptor debería rechazar timestamps viejos.
+const (
+	WebhookSignatureHeader = "X-Webpolls-Signature"
+	WebhookTimestampHeader = "X-Webpolls-Timestamp"
+	WebhookEventHeader     = "X-Webpolls-Event"
+	WebhookDeliveryHeader  = "X-Webpolls-Delivery"
+)
+
+const (
+	maxWebhooksPerUser = 10
+	maxDeliveryLog     = 50
+	// Tras webhookMaxAttempts intentos fallidos la entrega queda como failed
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	webhookTimeout     = 10 * time.Second
+	webhookBatchSize   = 20
+	// webhookLease reserva las entregas tomadas; si el proceso muere a mitad, otra réplica las reintenta al vencer
+	webhookLease = 2 * time.Minute
+)
+
+// WebhookService guarda las suscripciones de los usuarios y entrega los eventos de sus
+// encuestas desde una cola en Postgres.
+type WebhookService struct {
+	Queries *db.Queries
+	DB      *pgxpool.Pool
+	// Client hace las entregas: con timeout, sin seguir redirecciones y sin conectarse a
+	// direcciones internas
+	Client *http.Client
+	// Resolver resuelve las URLs al guardarlas (nil usa el del sistema)
+	Resolver *net.Resolver
+}
+
+// NewWebhookService crea una nueva instancia de WebhookService.
+func NewWebhookService(queries *db.Queries, db *pgxpool.Pool) *WebhookService {
+	return &WebhookService{
+		Queries: queries,
+		DB:      db,
+		Client:  newWebhookClient(),
+	}
+}
+
+type WebhookResponse struct {
+	ID        int32     `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int32      `json:"id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int32      `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode *int32     `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+// PollEventData son los datos de una encuesta que viajan en el evento.
+type PollEventData struct {
+	PollID   int32            `json:"poll_id"`
+	Title    string           `json:"title"`
+	Options  []OptionResponse `json:"options,omitempty"`
+	OptionID *int32           `json:"option_id,omitempty"`
+	Outcome  *PollOutcome     `json:"outcome,omitempty"`
+}
+
+// webhookPayload es el cuerpo JSON que recibe el endpoint.
+type webhookPayload struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID int32, rawURL string, events []string) (*WebhookResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, InvalidField("url", "webhook.invalid_url")
+	}
+	if err := checkWebhookHost(ctx, s.resolver(), u.Hostname()); err != nil {
+		return nil, err
+	}
+
+	var filtered []string
+	for _, event := range events {
+		if !slices.Contains(WebhookEvents, event) {
+			return nil, InvalidField("events", "webhook.invalid_event", event)
+		}
+		if !slices.Contains(filtered, event) {
+			filtered = append(filtered, event)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, InvalidField("events", "webhook.events_required")
+	}
+
+	existing, err := s.Queries.ListWebhooksByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, Conflict("webhook.limit", maxWebhooksPerUser)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := s.Queries.CreateWebhook(ctx, db.CreateWebhookParams{
+		UserID: userID,
+		Url:    u.String(),
+		Secret: secret,
+		Events: filtered,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID int32) ([]WebhookResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.ListWebhooks")
+	defer span.End()
+
+	rows, err := s.Queries.ListWebhooksByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []WebhookResponse
+	for _, row := range rows {
+		webhooks = append(webhooks, *toWebhookResponse(row))
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id, userID int32) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	deleted, err := s.Queries.DeleteWebhook(ctx, db.DeleteWebhookParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return NotFound("webhook.not_found")
+	}
+	return nil
+}
+
+// ListDeliveries devuelve el webhook del usuario con sus últimas entregas.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id, userID int32) (*WebhookResponse, []WebhookDeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.ListDeliveries")
+	defer span.End()
+
+	webhook, err := s.getWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.Queries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		WebhookID:  id,
+		MaxEntries: maxDeliveryLog,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var deliveries []WebhookDeliveryResponse
+	for _, row := range rows {
+		deliveries = append(deliveries, WebhookDeliveryResponse{
+			ID:             row.ID,
+			Event:          row.Event,
+			Status:         row.Status,
+			Attempts:       row.Attempts,
+			NextAttemptAt:  row.NextAttemptAt.Time,
+			LastStatusCode: int4Ptr(row.LastStatusCode),
+			LastError:      row.LastError,
+			CreatedAt:      row.CreatedAt.Time,
+			DeliveredAt:    timePtr(row.DeliveredAt),
+		})
+	}
+	return webhook, deliveries, nil
+}
+
+// SendTestEvent encola un evento ping para el webhook, sin importar los eventos suscritos.
+func (s *WebhookService) SendTestEvent(ctx context.Context, id, userID int32) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.SendTestEvent")
+	defer span.End()
+
+	if _, err := s.getWebhook(ctx, id, userID); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(webhookPayload{
+		Event:     EventPing,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]int32{"webhook_id": id},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.Queries.EnqueueWebhookDelivery(ctx, db.EnqueueWebhookDeliveryParams{
+		WebhookID: id,
+		Event:     EventPing,
+		Payload:   payload,
+	})
+	return err
+}
+
+// Emit encola el evento para los webhooks del dueño suscritos a él. Solo escribe en la cola,
+// así que no demora a quien lo llama; los errores se registran y no se propagan. Acepta un
+// servicio nil (sin webhooks).
+func (s *WebhookService) Emit(ctx context.Context, ownerID int32, event string, data any) {
+	if s == nil {
+		return
+	}
+	ctx, span := tracer.Start(ctx, "WebhookService.Emit")
+	defer span.End()
+
+	payload, err := json.Marshal(webhookPayload{Event: event, CreatedAt: time.Now().UTC(), Data: data})
+	if err == nil {
+		_, err = s.Queries.EnqueueWebhookEvent(ctx, db.EnqueueWebhookEventParams{
+			Event:   event,
+			Payload: payload,
+			UserID:  ownerID,
+		})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error enqueueing webhook event", "event", event, "owner_id", ownerID, "error", err)
+	}
+}
+
+// DispatchDue envía un lote de entregas vencidas en paralelo. Devuelve cuántas tomó.
+func (s *WebhookService) DispatchDue(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.DispatchDue")
+	defer span.End()
+
+	rows, err := s.Queries.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: pgtype.Timestamptz{Time: time.Now().Add(webhookLease), Valid: true},
+		BatchSize:  webhookBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, row := range rows {
+		wg.Go(func() { s.deliver(ctx, row) })
+	}
+	wg.Wait()
+	return len(rows), nil
+}
+
+// RunDispatcher revisa la cola cada every hasta que se cancele ctx.
+func (s *WebhookService) RunDispatcher(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Si el lote vino lleno probablemente quedan más, así que se sigue sin esperar
+			for {
+				n, err := s.DispatchDue(ctx)
+				if err != nil {
+					slog.Error("error despachando webhooks", "error", err)
+				}
+				if err != nil || n < webhookBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// deliver hace un intento de entrega y registra el resultado.
+func (s *WebhookService) deliver(ctx context.Context, row db.ClaimDueWebhookDeliveriesRow) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	statusCode, err := s.post(ctx, row, timestamp)
+	code := pgtype.Int4{Int32: int32(statusCode), Valid: statusCode != 0}
+
+	if err == nil {
+		recordWebhookDelivery(DeliveryDelivered)
+		if err := s.Queries.MarkWebhookDelivered(ctx, db.MarkWebhookDeliveredParams{LastStatusCode: code, ID: row.ID}); err != nil {
+			slog.ErrorContext(ctx, "error marking webhook delivered", "delivery_id", row.ID, "error", err)
+		}
+		return
+	}
+
+	status := DeliveryPending
+	if row.Attempts >= webhookMaxAttempts {
+		status = DeliveryFailed
+	}
+	recordWebhookDelivery(status)
+	slog.WarnContext(ctx, "webhook delivery failed", "delivery_id", row.ID, "attempt", row.Attempts, "error", err)
+
+	lastError := err.Error()
+	if len(lastError) > 500 {
+		lastError = lastError[:500]
+	}
+	err = s.Queries.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+		Status:         status,
+		LastStatusCode: code,
+		LastError:      lastError,
+		NextAttemptAt:  pgtype.Timestamptz{Time: time.Now().Add(webhookBackoff(row.Attempts)), Valid: true},
+		ID:             row.ID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "error marking webhook delivery failed", "delivery_id", row.ID, "error", err)
+	}
+}
+
+// post envía el payload firmado. Cualquier respuesta fuera de 2xx cuenta como error.
+func (s *WebhookService) post(ctx context.Context, row db.ClaimDueWebhookDeliveriesRow, timestamp string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, row.Url, bytes.NewReader(row.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "webpolls-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, row.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(int(row.ID)))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(row.Secret, timestamp, row.Payload))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("respuesta HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook calcula la firma que va en WebhookSignatureHeader.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff es la espera antes del siguiente intento: 30s, 1m, 2m... hasta 6h, con un
+// poco de azar para que no se reintenten todas juntas.
+func webhookBackoff(attempts int32) time.Duration {
+	delay := webhookMaxBackoff
+	if attempts < 20 {
+		delay = min(webhookBaseBackoff<<(attempts-1), webhookMaxBackoff)
+	}
+	return delay + mathrand.N(delay/10+1)
+}
+
+func (s *WebhookService) getWebhook(ctx context.Context, id, userID int32) (*WebhookResponse, error) {
+	webhook, err := s.Queries.GetWebhookByID(ctx, db.GetWebhookByIDParams{ID: id, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NotFound("webhook.not_found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *WebhookService) resolver() *net.Resolver {
+	if s.Resolver != nil {
+		return s.Resolver
+	}
+	return net.DefaultResolver
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func toWebhookResponse(webhook db.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestBlockedWebhookIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.10", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"255.255.255.255", true},
+		{"224.0.0.1", true},
+		{"fe80::1", true},
+		{"fd00:ec2::254", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"2606:4700::1111", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := blockedWebhookIP(netip.MustParseAddr(tt.ip)); got != tt.blocked {
+				t.Errorf("blockedWebhookIP(%s) = %v, want %v", tt.ip, got, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestCheckWebhookHost(t *testing.T) {
+	ctx := context.Background()
+	for _, host := range []string{"127.0.0.1", "169.254.169.254", "::1", "localhost"} {
+		err := checkWebhookHost(ctx, nil, host)
+		if !errors.Is(err, InvalidField("url", "webhook.blocked_url")) {
+			t.Errorf("checkWebhookHost(%q) = %v, want webhook.blocked_url", host, err)
+		}
+	}
+	if err := checkWebhookHost(ctx, nil, "8.8.8.8"); err != nil {
+		t.Errorf("checkWebhookHost(8.8.8.8) = %v, want nil", err)
+	}
+}
+
+// El cliente de entregas no se conecta a direcciones internas aunque la URL ya esté guardada
+// (o el DNS haya cambiado después de validarla).
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := newWebhookClient().Post(server.URL, "application/json", nil)
+	if !errors.Is(err, errWebhookBlockedAddress) {
+		t.Fatalf("got %v, want errWebhookBlockedAddress", err)
+	}
+	if called {
+		t.Fatal("el servidor interno recibió la entrega")
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// Calculado con: printf '1700000000.{"event":"ping"}' | openssl dgst -sha256 -hmac whsec_test
+	const want = "sha256=aa8efe37b751e71157c508c5ac4acb1e9fe5225db98355dfc00f4b680afbc447"
+	got := SignWebhook("whsec_test", "1700000000", []byte(`{"event":"ping"}`))
+	if got != want {
+		t.Fatalf("SignWebhook = %q, want %q", got, want)
+	}
+	for name, other := range map[string]string{
+		"otro secreto":   SignWebhook("whsec_other", "1700000000", []byte(`{"event":"ping"}`)),
+		"otro timestamp": SignWebhook("whsec_test", "1700000001", []byte(`{"event":"ping"}`)),
+		"otro cuerpo":    SignWebhook("whsec_test", "1700000000", []byte(`{"event":"pong"}`)),
+	} {
+		if other == got {
+			t.Errorf("%s: la firma no cambió", name)
+		}
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		base     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{10, 512 * 30 * time.Second},
+		{11, webhookMaxBackoff},
+		{19, webhookMaxBackoff},
+		{40, webhookMaxBackoff},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			got := webhookBackoff(tt.attempts)
+			if got < tt.base || got > tt.base+tt.base/10 {
+				t.Errorf("webhookBackoff(%d) = %v, want between %v and %v", tt.attempts, got, tt.base, tt.base+tt.base/10)
+			}
+		}
+	}
+}
+
+// La URL y los eventos se validan antes de tocar la base (con una IP pública para no
+// depender del DNS).
+func TestCreateWebhookValidation(t *testing.T) {
+	s := &WebhookService{}
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+		want   string
+	}{
+		{"sin esquema", "example.com/hook", []string{EventPollVoted}, "la URL debe ser http:// o https://"},
+		{"otro esquema", "ftp://example.com/hook", []string{EventPollVoted}, "la URL debe ser http:// o https://"},
+		{"evento desconocido", "https://93.184.216.34/hook", []string{"poll.edited"}, `evento "poll.edited" inválido`},
+		{"sin eventos", "https://93.184.216.34/hook", nil, "elige al menos un evento"},
+		{"ping no se suscribe", "https://93.184.216.34/hook", []string{EventPing}, `evento "ping" inválido`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateWebhook(context.Background(), 1, tt.url, tt.events)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("CreateWebhook = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errWebhookBlockedAddress se registra como error de la entrega cuando el destino resuelve a
+// una dirección interna.
+var errWebhookBlockedAddress = errors.New("la dirección de destino es interna o reservada")
+
+// blockedWebhookPrefixes son rangos que no cubren los métodos de netip.Addr y a los que un
+// webhook tampoco puede apuntar.
+var blockedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "esta red"
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // asignaciones de protocolo del IETF
+	netip.MustParsePrefix("198.18.0.0/15"),  // pruebas de rendimiento
+	netip.MustParsePrefix("240.0.0.0/4"),    // reservado, incluye el broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64: puede traducir a una IPv4 interna
+	netip.MustParsePrefix("64:ff9b:1::/48"), // NAT64 local
+	netip.MustParsePrefix("2002::/16"),      // 6to4: embebe una IPv4 cualquiera
+}
+
+// blockedWebhookIP indica si ip es de loopback, privada, de enlace local (ahí están los
+// servicios de metadatos como 169.254.169.254) o de algún rango reservado.
+func blockedWebhookIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedWebhookPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWebhookHost resuelve host y falla si alguna de sus direcciones es interna. Se valida
+// al guardar el webhook para avisarle al usuario; la protección real está al conectar
+// (webhookDialControl), porque el DNS puede cambiar después.
+func checkWebhookHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if blockedWebhookIP(ip) {
+			return InvalidField("url", "webhook.blocked_url")
+		}
+		return nil
+	}
+	ips, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(ips) == 0 {
+		return InvalidField("url", "webhook.unresolvable_host", host)
+	}
+	for _, ip := range ips {
+		if blockedWebhookIP(ip) {
+			return InvalidField("url", "webhook.blocked_url")
+		}
+	}
+	return nil
+}
+
+// webhookDialControl corre después de resolver el nombre y antes de conectar, con la
+// dirección que se va a usar de verdad: así un DNS que cambia entre la validación y la
+// entrega (DNS rebinding) no alcanza para llegar a la red interna.
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("dirección de destino inválida %q: %w", address, err)
+	}
+	if blockedWebhookIP(addr.Addr()) {
+		return errWebhookBlockedAddress
+	}
+	return nil
+}
+
+// newWebhookClient arma el cliente de las entregas: con timeout, sin seguir redirecciones,
+// sin proxy (el chequeo tiene que ver el destino real) y sin conectarse a direcciones internas.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: webhookDialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
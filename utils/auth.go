@@ -2,34 +2,28 @@ package utils
 
 import (
 	"net/http"
-	"os"
 
 	"github.com/gorilla/sessions"
 )
 
 var Store *sessions.CookieStore
 
-func InitSessionStore() {
-	key := os.Getenv("SESSION_KEY")
-	if key == "" {
-		key = "supersecretkey" // Fallback for development
-	}
-	secure := os.Getenv("SECURE")
-	if secure == "" {
-		secure = "false"
-	}
+// SessionCookie es el nombre de la cookie que guarda la sesión.
+const SessionCookie = "webpolls-session"
 
+// InitSessionStore crea el store de cookies firmado con key. secure marca la cookie como Secure (HTTPS).
+func InitSessionStore(key string, secure bool) {
 	Store = sessions.NewCookieStore([]byte(key))
 	Store.Options = &sessions.Options{
 		Path:     "/",
 		MaxAge:   86400 * 7, // 7 days
 		HttpOnly: true,
-		Secure:   secure == "true", // Set to true in production with HTTPS
+		Secure:   secure, // true en producción con HTTPS
 	}
 }
 
 func GetSession(r *http.Request) *sessions.Session {
-	session, _ := Store.Get(r, "webpolls-session")
+	session, _ := Store.Get(r, SessionCookie)
 	return session
 }
 
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+var trustProxy bool
+
+// SetTrustProxy indica si la app corre detrás de un proxy que sobrescribe X-Forwarded-For (TRUST_PROXY).
+func SetTrustProxy(trust bool) {
+	trustProxy = trust
+}
+
+// ClientIP devuelve la IP del cliente. Solo confía en X-Forwarded-For si se activó SetTrustProxy.
+func ClientIP(r *http.Request) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
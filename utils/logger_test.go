@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		format string
+		level  string
+		ok     bool
+	}{
+		{"", "", true},
+		{"text", "debug", true},
+		{"JSON", "warn", true},
+		{"xml", "info", false},
+		{"json", "verbose", false},
+	}
+	for _, tt := range tests {
+		if _, err := NewLogger(&bytes.Buffer{}, tt.format, tt.level); (err == nil) != tt.ok {
+			t.Errorf("NewLogger(%q, %q) = %v, want ok=%v", tt.format, tt.level, err, tt.ok)
+		}
+	}
+}
+
+func TestLoggerAddsRequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(&out, "json", "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With("component", "test").InfoContext(ctx, "hola")
+	logger.DebugContext(ctx, "no se registra")
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("se esperaba un solo registro JSON: %v (%s)", err, out.String())
+	}
+	if record["request_id"] != "req-1" || record["component"] != "test" || record["msg"] != "hola" {
+		t.Errorf("registro = %v", record)
+	}
+}
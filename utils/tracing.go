@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// InitTracing configura el TracerProvider global según el exportador: "otlp" (usa
+// OTEL_EXPORTER_OTLP_ENDPOINT y demás variables estándar), "stdout" para desarrollo local o
+// "none"/vacío para desactivar el tracing. Devuelve una función que vacía y cierra el exportador.
+func InitTracing(ctx context.Context, exporterName string) (func(context.Context) error, error) {
+	// El contexto de traza viaja en los headers traceparent/tracestate
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER %q inválido: debe ser otlp, stdout o none", exporterName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// OTEL_SERVICE_NAME y OTEL_RESOURCE_ATTRIBUTES pisan el nombre por defecto
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName("webpolls")),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
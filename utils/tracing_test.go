@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInitTracing(t *testing.T) {
+	tests := []struct {
+		exporter string
+		ok       bool
+	}{
+		{"", true},
+		{"none", true},
+		{"stdout", true},
+		{"jaeger", false},
+	}
+	for _, tt := range tests {
+		shutdown, err := InitTracing(context.Background(), tt.exporter)
+		if (err == nil) != tt.ok {
+			t.Errorf("InitTracing(%q) = %v, want ok=%v", tt.exporter, err, tt.ok)
+			continue
+		}
+		if tt.ok {
+			if err := shutdown(context.Background()); err != nil {
+				t.Errorf("shutdown de %q = %v", tt.exporter, err)
+			}
+		}
+	}
+}